@@ -0,0 +1,216 @@
+package uinput
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// An EventBatch accumulates events and sends them to the device using a single write, which saves a lot of syscalls
+// compared to sending each event on its own (e.g. when playing back macros or updating a gamepad at a high rate).
+// Events are queued by chaining calls to the batch and sent by calling Flush:
+//
+//	err := keyboard.Batch().KeyDown(KeyLeftshift).KeyDown(KeyA).Sync().KeyUp(KeyA).KeyUp(KeyLeftshift).Flush()
+//
+// Note that batched events bypass the state kept by the device, so options like WithMaxSimultaneousKeys and
+// WithPreSyncDelay do not apply to them.
+type EventBatch struct {
+	deviceFile *device
+	buf        []byte
+	synced     bool
+	err        error
+}
+
+func newEventBatch(deviceFile *device) *EventBatch {
+	return &EventBatch{deviceFile: deviceFile, synced: true}
+}
+
+// Event queues a single event of the given type and code.
+func (b *EventBatch) Event(evType uint16, code uint16, value int32) *EventBatch {
+	return b.event(inputEvent{Type: evType, Code: code, Value: value})
+}
+
+// EventAt queues a single event of the given type and code, carrying the given timestamp instead of the time the kernel
+// receives the event at. The timestamp is a time of CLOCK_MONOTONIC (see MonotonicTime), which allows to replay a
+// captured trace while preserving the timing of its events (see MacroAt). Consumers receive the timestamp converted to
+// the clock they selected. Note that only kernels 6.10 and later pass on the timestamps of uinput events, and only if
+// they are not in the future and at most TimestampWindow old; the time the event is received at is used otherwise.
+// Older kernels ignore the timestamps altogether.
+func (b *EventBatch) EventAt(t time.Duration, evType uint16, code uint16, value int32) *EventBatch {
+	if b.err == nil && t <= 0 {
+		b.err = fmt.Errorf("failed to queue event. Timestamp %v is not positive", t)
+	}
+	return b.event(inputEvent{Time: syscall.NsecToTimeval(t.Nanoseconds()), Type: evType, Code: code, Value: value})
+}
+
+func (b *EventBatch) event(iev inputEvent) *EventBatch {
+	if b.err != nil {
+		return b
+	}
+	b.buf = appendInputEvent(b.buf, iev)
+	b.synced = iev.Type == evSyn && iev.Code == synReport
+	return b
+}
+
+// Encoded queues events that have been encoded already using AppendEvent or AppendEventAt, which allows to prepare
+// frames once and send them repeatedly. The length of the encoded events must be a multiple of EventSize.
+func (b *EventBatch) Encoded(events []byte) *EventBatch {
+	if b.err != nil || len(events) == 0 {
+		return b
+	}
+	if len(events)%EventSize != 0 {
+		b.err = fmt.Errorf("failed to queue encoded events. Length %d is not a multiple of %d", len(events), EventSize)
+		return b
+	}
+	b.buf = append(b.buf, events...)
+	last, err := decodeInputEvent(events[len(events)-EventSize:])
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.synced = last.Type == evSyn && last.Code == synReport
+	return b
+}
+
+// KeyDown queues a key press of the given key (or button).
+func (b *EventBatch) KeyDown(key int) *EventBatch {
+	return b.key(key, btnStatePressed)
+}
+
+// KeyUp queues a key release of the given key (or button).
+func (b *EventBatch) KeyUp(key int) *EventBatch {
+	return b.key(key, btnStateReleased)
+}
+
+func (b *EventBatch) key(key int, value int32) *EventBatch {
+	if b.err == nil && !keyCodeInRange(key) {
+		b.err = sentinelErrorf(ErrKeyOutOfRange, "failed to queue key event. Code %d is not in range", key)
+	}
+	for _, ev := range keyEvents(uint16(key), value) {
+		b.Event(ev.Type, ev.Code, ev.Value)
+	}
+	return b
+}
+
+// Sync terminates the events queued so far, so that they are reported as a frame of their own. This is needed in order
+// to e.g. press and release a key within the same batch. Flush terminates the last frame implicitly.
+func (b *EventBatch) Sync() *EventBatch {
+	return b.Event(evSyn, synReport, 0)
+}
+
+// SyncAt terminates the events queued so far just like Sync, using the given timestamp for the sync event. The same
+// restrictions apply as for EventAt (kernel 6.10 or later, not in the future and at most TimestampWindow old).
+func (b *EventBatch) SyncAt(t time.Duration) *EventBatch {
+	return b.EventAt(t, evSyn, synReport, 0)
+}
+
+// TimestampWindow is how old the timestamp of an event may be at most, before the kernel replaces it with the time it
+// receives the event at (see EventAt).
+const TimestampWindow = 10 * time.Second
+
+// MacroAt queues all events of the macro, which are stamped so that the last event carries the given end time and the
+// others keep their distance to it (see EventAt). An end time of MonotonicTime makes the macro appear to have just
+// happened, even though all of its events are sent at once. Since the kernel replaces timestamps that are in the
+// future or older than TimestampWindow, the end time must not be in the future and the macro must not last longer than
+// TimestampWindow; queueing a longer macro fails. Note that kernels prior to 6.10 ignore the timestamps altogether.
+func (b *EventBatch) MacroAt(m *Macro, end time.Duration) *EventBatch {
+	if b.err == nil && m.Duration() > TimestampWindow {
+		b.err = fmt.Errorf("failed to queue macro. Its duration of %v exceeds the timestamp window of %v", m.Duration(), TimestampWindow)
+	}
+	start := end - m.Duration()
+	for _, ev := range m.Events {
+		b.EventAt(start+ev.Time, ev.Type, ev.Code, ev.Value)
+	}
+	return b
+}
+
+// Len returns the number of events queued so far.
+func (b *EventBatch) Len() int {
+	return len(b.buf) / EventSize
+}
+
+// Flush sends all queued events, terminated by a sync event, using a single write and empties the batch. If queueing
+// any of the events failed, nothing is sent and the first error is returned.
+func (b *EventBatch) Flush() error {
+	if b.err != nil {
+		err := b.err
+		b.reset()
+		return err
+	}
+	if !b.synced {
+		b.Sync()
+	}
+	if len(b.buf) == 0 {
+		return nil
+	}
+
+	_, err := writeEvents(b.deviceFile, b.buf)
+	b.reset()
+	if err != nil {
+		return fmt.Errorf("failed to write event batch to device file: %w", err)
+	}
+	return nil
+}
+
+// Grow makes room for at least n more events, so that queueing them does not allocate. Since Flush keeps the buffer of
+// the batch, a batch that is reused for every frame only allocates until it has grown to the size of the largest frame.
+func (b *EventBatch) Grow(n int) *EventBatch {
+	size := n * EventSize
+	if n > 0 && cap(b.buf)-len(b.buf) < size {
+		buf := make([]byte, len(b.buf), len(b.buf)+size)
+		copy(buf, b.buf)
+		b.buf = buf
+	}
+	return b
+}
+
+// FlushBatches sends the events of all given batches, each terminated by a sync event, using a single vectored write
+// (writev) and empties the batches. All batches must belong to the same device. This allows to prepare the events of a
+// high-rate device in separate, reused batches (e.g. one per controller of a gamepad), without copying them into one.
+// If queueing any of the events failed, nothing is sent and the first error is returned.
+func FlushBatches(batches ...*EventBatch) error {
+	var deviceFile *device
+	var err error
+	for _, b := range batches {
+		if deviceFile == nil {
+			deviceFile = b.deviceFile
+		}
+		if err == nil && b.err != nil {
+			err = b.err
+		}
+		if err == nil && b.deviceFile != deviceFile {
+			err = fmt.Errorf("failed to flush event batches. All batches must belong to the same device")
+		}
+	}
+	if err != nil {
+		for _, b := range batches {
+			b.reset()
+		}
+		return err
+	}
+
+	bufs := make([][]byte, 0, len(batches))
+	for _, b := range batches {
+		if !b.synced {
+			b.Sync()
+		}
+		bufs = append(bufs, b.buf)
+	}
+	if deviceFile == nil {
+		return nil
+	}
+	_, err = writeEventsVectored(deviceFile, bufs)
+	for _, b := range batches {
+		b.reset()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write event batches to device file: %w", err)
+	}
+	return nil
+}
+
+func (b *EventBatch) reset() {
+	b.buf = b.buf[:0]
+	b.synced = true
+	b.err = nil
+}
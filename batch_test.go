@@ -0,0 +1,187 @@
+package uinput
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestBatchIsTerminatedBySingleSync(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	b := vk.Batch().KeyDown(KeyLeftshift).KeyDown(KeyA).Sync().KeyUp(KeyA).KeyUp(KeyLeftshift)
+	if b.Len() != 5 {
+		t.Fatalf("Expected 5 queued events, but got %d", b.Len())
+	}
+	err := b.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("Expected batch to be empty after flushing, but got %d events", b.Len())
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestBatchIncludesScanCodesOfMediaKeys(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	err := newEventBatch(&device{File: file}).KeyDown(KeyMute).Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	if len(events) != 3 || events[0].Type != evMsc || events[1].Code != KeyMute {
+		t.Fatalf("Expected scan code, key event and sync, but got %+v", events)
+	}
+}
+
+func TestEmptyBatchSendsNothing(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	err := newEventBatch(&device{File: file}).Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events, but got %+v", events)
+	}
+}
+
+func TestBatchWithInvalidKeySendsNothing(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	err := newEventBatch(&device{File: file}).KeyDown(KeyA).KeyDown(-1).Flush()
+	if err == nil {
+		t.Fatalf("Expected flushing a batch with an invalid key to fail")
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events, but got %+v", events)
+	}
+}
+
+func TestFlushBatchesSendsAllBatches(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	first := vk.Batch().KeyDown(KeyA)
+	second := vk.Batch().KeyUp(KeyA)
+	err := FlushBatches(first, second, vk.Batch())
+	if err != nil {
+		t.Fatalf("Failed to flush batches: %v", err)
+	}
+	if first.Len() != 0 || second.Len() != 0 {
+		t.Fatalf("Expected the batches to be empty after flushing")
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestFlushBatchesFailsOnDifferentDevices(t *testing.T) {
+	first := createTestEventFile(t)
+	defer first.Close()
+	second := createTestEventFile(t)
+	defer second.Close()
+
+	err := FlushBatches(newEventBatch(&device{File: first}).KeyDown(KeyA), newEventBatch(&device{File: second}).KeyDown(KeyB))
+	if err == nil {
+		t.Fatalf("Expected flushing to fail due to batches of different devices, but got no error.")
+	}
+	if events := readTestEvents(t, first); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+}
+
+func TestBatchGrowPreallocatesEvents(t *testing.T) {
+	b := newEventBatch(nil).KeyDown(KeyA)
+	b.Grow(10)
+	if free := cap(b.buf) - len(b.buf); free < 10*binary.Size(inputEvent{}) {
+		t.Fatalf("Expected room for 10 more events, but got %d bytes", free)
+	}
+	if b.Len() != 1 {
+		t.Fatalf("Expected the queued event to be kept, but got %d events", b.Len())
+	}
+}
+
+// queueGamepadFrame queues a typical frame of a gamepad, which moves both sticks and presses a button.
+func queueGamepadFrame(b *EventBatch, i int) {
+	b.Event(evAbs, absX, int32(i)).Event(evAbs, absY, int32(-i)).
+		Event(evAbs, absRX, int32(i)).Event(evAbs, absRY, int32(-i)).
+		Event(evKey, ButtonSouth, int32(i%2)).Sync()
+}
+
+func BenchmarkBatchFlush(b *testing.B) {
+	file := openBenchmarkFile(b)
+	defer file.Close()
+	batch := newEventBatch(&device{File: file}).Grow(6)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		queueGamepadFrame(batch, i)
+		err := batch.Flush()
+		if err != nil {
+			b.Fatalf("Failed to flush batch: %v", err)
+		}
+	}
+	reportEventRate(b, start, 6)
+}
+
+func BenchmarkFlushBatches(b *testing.B) {
+	file := openBenchmarkFile(b)
+	defer file.Close()
+	batches := []*EventBatch{newEventBatch(&device{File: file}).Grow(6), newEventBatch(&device{File: file}).Grow(6), newEventBatch(&device{File: file}).Grow(6)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		for _, batch := range batches {
+			queueGamepadFrame(batch, i)
+		}
+		err := FlushBatches(batches...)
+		if err != nil {
+			b.Fatalf("Failed to flush batches: %v", err)
+		}
+	}
+	reportEventRate(b, start, 6*len(batches))
+}
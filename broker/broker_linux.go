@@ -0,0 +1,188 @@
+package broker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// DefaultSocketPath is the path of the socket a broker listens on, unless configured otherwise.
+const DefaultSocketPath = "/run/uinput-broker.sock"
+
+// DefaultRequestTimeout is the time a client has to send its request after connecting, unless configured otherwise.
+const DefaultRequestTimeout = 10 * time.Second
+
+// The lines of the protocol. A client sends openRequest, which the broker answers with okResponse (along with the file
+// descriptor) or with errorResponse followed by a message.
+const (
+	openRequest   = "open"
+	okResponse    = "ok"
+	errorResponse = "error "
+)
+
+// Credentials identify the process on the other end of a connection.
+type Credentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+// Broker opens uinput device files on behalf of its clients.
+type Broker struct {
+	// DevicePath is the path of the uinput device file, which is /dev/uinput if empty.
+	DevicePath string
+
+	// Authorize decides whether the client with the given credentials may open the device file, by returning nil. The
+	// error is reported back to the client otherwise. If Authorize is nil, only clients running as the same user as the
+	// broker are authorized, so a broker running as root needs to set Authorize in order to serve other users.
+	Authorize func(cred Credentials) error
+
+	// RequestTimeout is the time a client has to send its request after connecting, which is DefaultRequestTimeout if
+	// zero. The connection is closed if the request does not arrive in time, so that idle clients do not hold on to the
+	// resources of the broker.
+	RequestTimeout time.Duration
+}
+
+// Listen creates a unix domain socket at the given path, using the given permissions. A stale socket left behind by a
+// previous broker is removed.
+func Listen(path string, mode os.FileMode) (*net.UnixListener, error) {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on socket: %w", err)
+	}
+	err = os.Chmod(path, mode)
+	if err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to set socket permissions: %w", err)
+	}
+	return l, nil
+}
+
+// SystemdListener returns the socket passed by systemd socket activation (see sd_listen_fds). It fails if the
+// process has not been activated by systemd.
+func SystemdListener() (*net.UnixListener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errors.New("process has not been activated by systemd")
+	}
+	fds, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || fds < 1 {
+		return nil, errors.New("systemd did not pass a socket")
+	}
+	// the passed sockets start at file descriptor 3
+	f := os.NewFile(3, "systemd socket")
+	defer f.Close()
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use systemd socket: %w", err)
+	}
+	ul, ok := l.(*net.UnixListener)
+	if !ok {
+		l.Close()
+		return nil, errors.New("systemd socket is not a unix domain socket")
+	}
+	return ul, nil
+}
+
+// Serve accepts connections on the listener and serves each of them in a goroutine of its own, until accepting a
+// connection fails (e.g. because the listener has been closed).
+func (b *Broker) Serve(l *net.UnixListener) error {
+	for {
+		conn, err := l.AcceptUnix()
+		if err != nil {
+			return err
+		}
+		go b.serveConn(conn)
+	}
+}
+
+func (b *Broker) serveConn(conn *net.UnixConn) {
+	defer conn.Close()
+	timeout := b.RequestTimeout
+	if timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+	err := conn.SetReadDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	if line != openRequest+"\n" {
+		_, _ = conn.Write([]byte(errorResponse + "unknown request\n"))
+		return
+	}
+
+	f, err := b.open(conn)
+	if err != nil {
+		_, _ = conn.Write([]byte(errorResponse + err.Error() + "\n"))
+		return
+	}
+	defer f.Close()
+	_, _, _ = conn.WriteMsgUnix([]byte(okResponse+"\n"), syscall.UnixRights(int(f.Fd())), nil)
+}
+
+// open authorizes the client and opens the device file for it.
+func (b *Broker) open(conn *net.UnixConn) (*os.File, error) {
+	cred, err := peerCredentials(conn)
+	if err != nil {
+		return nil, err
+	}
+	authorize := b.Authorize
+	if authorize == nil {
+		authorize = authorizeSameUser
+	}
+	err = authorize(cred)
+	if err != nil {
+		return nil, fmt.Errorf("client is not authorized: %w", err)
+	}
+	path := b.DevicePath
+	if path == "" {
+		path = "/dev/uinput"
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open device file: %w", err)
+	}
+	return f, nil
+}
+
+// authorizeSameUser authorizes clients running as the same user as the broker, which is the default if Authorize is
+// nil.
+func authorizeSameUser(cred Credentials) error {
+	if cred.UID != uint32(os.Getuid()) {
+		return fmt.Errorf("user %d differs from the user of the broker", cred.UID)
+	}
+	return nil
+}
+
+// peerCredentials returns the credentials of the process on the other end of the connection (see SO_PEERCRED).
+func peerCredentials(conn *net.UnixConn) (Credentials, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return Credentials{}, err
+	}
+	var ucred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		ucred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return Credentials{}, err
+	}
+	if credErr != nil {
+		return Credentials{}, fmt.Errorf("failed to fetch client credentials: %w", credErr)
+	}
+	return Credentials{PID: ucred.Pid, UID: ucred.Uid, GID: ucred.Gid}, nil
+}
@@ -0,0 +1,153 @@
+package broker
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTestBroker serves the broker on a socket in a temporary directory, using a regular file in place of the device
+// file. It returns the path of the socket, the path of the device file and a function stopping the broker.
+func startTestBroker(t *testing.T, b *Broker) (string, string, func()) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	b.DevicePath = filepath.Join(dir, "uinput")
+	err = ioutil.WriteFile(b.DevicePath, nil, 0600)
+	if err != nil {
+		t.Fatalf("Failed to create device file: %v", err)
+	}
+	socket := filepath.Join(dir, "broker.sock")
+	l, err := Listen(socket, 0600)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go b.Serve(l)
+	return socket, b.DevicePath, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestOpenPassesDeviceFile(t *testing.T) {
+	socket, devicePath, stop := startTestBroker(t, &Broker{})
+	defer stop()
+
+	f, err := Open(socket)
+	if err != nil {
+		t.Fatalf("Failed to open device file: %v", err)
+	}
+	_, err = f.Write([]byte("event"))
+	f.Close()
+	if err != nil {
+		t.Fatalf("Failed to write to device file: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(devicePath)
+	if err != nil {
+		t.Fatalf("Failed to read device file: %v", err)
+	}
+	if string(content) != "event" {
+		t.Fatalf("Expected the passed file to refer to the device file, but it contains %q", content)
+	}
+}
+
+func TestOpenFailsIfClientIsNotAuthorized(t *testing.T) {
+	// the credentials are passed back through a channel, since Authorize runs on the goroutine of the broker
+	clients := make(chan Credentials, 1)
+	socket, _, stop := startTestBroker(t, &Broker{Authorize: func(cred Credentials) error {
+		clients <- cred
+		return errors.New("access denied")
+	}})
+	defer stop()
+
+	_, err := Open(socket)
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Fatalf("Expected Open to fail with the error of Authorize, but got %v", err)
+	}
+	client := <-clients
+	if client.UID != uint32(os.Getuid()) || client.PID != int32(os.Getpid()) {
+		t.Fatalf("Expected the credentials of the test process, but got %+v", client)
+	}
+}
+
+func TestBrokerOnlyAuthorizesSameUserByDefault(t *testing.T) {
+	err := authorizeSameUser(Credentials{UID: uint32(os.Getuid())})
+	if err != nil {
+		t.Fatalf("Expected a client of the same user to be authorized, but got %v", err)
+	}
+	err = authorizeSameUser(Credentials{UID: uint32(os.Getuid()) + 1})
+	if err == nil {
+		t.Fatalf("Expected a client of another user to be rejected, but got no error.")
+	}
+}
+
+func TestBrokerRejectsUnknownRequests(t *testing.T) {
+	socket, _, stop := startTestBroker(t, &Broker{})
+	defer stop()
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte("create\n"))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	response, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	if !strings.HasPrefix(string(response), errorResponse) {
+		t.Fatalf("Expected an error response, but got %q", response)
+	}
+}
+
+func TestBrokerClosesIdleConnections(t *testing.T) {
+	socket, _, stop := startTestBroker(t, &Broker{RequestTimeout: 50 * time.Millisecond})
+	defer stop()
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	err = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err != nil {
+		t.Fatalf("Failed to set deadline: %v", err)
+	}
+	// the broker closes the connection without a response, since no request has been sent
+	response, err := ioutil.ReadAll(conn)
+	if err != nil {
+		t.Fatalf("Expected the broker to close the connection, but got %v", err)
+	}
+	if len(response) != 0 {
+		t.Fatalf("Expected no response, but got %q", response)
+	}
+}
+
+func TestOpenFailsIfDeviceFileIsMissing(t *testing.T) {
+	socket, devicePath, stop := startTestBroker(t, &Broker{})
+	defer stop()
+	os.Remove(devicePath)
+
+	_, err := Open(socket)
+	if err == nil {
+		t.Fatalf("Expected Open to fail due to the missing device file, but got no error.")
+	}
+}
+
+func TestSystemdListenerFailsWithoutActivation(t *testing.T) {
+	os.Unsetenv("LISTEN_PID")
+	_, err := SystemdListener()
+	if err == nil {
+		t.Fatalf("Expected SystemdListener to fail without socket activation, but got no error.")
+	}
+}
@@ -0,0 +1,98 @@
+package broker
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/bendahl/uinput"
+)
+
+// Open requests a uinput device file from the broker listening on the given socket.
+func Open(socketPath string) (*os.File, error) {
+	conn, err := net.DialUnix("unix", nil, &net.UnixAddr{Name: socketPath, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to broker: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(openRequest + "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to broker: %w", err)
+	}
+	buf := make([]byte, 256)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response of broker: %w", err)
+	}
+	fd, err := receivedFd(oob[:oobn])
+	if err != nil {
+		return nil, err
+	}
+
+	// the response may be split across several reads, unlike the file descriptor, which arrives along with the first
+	response, err := bufio.NewReader(io.MultiReader(bytes.NewReader(buf[:n]), conn)).ReadString('\n')
+	if err != nil {
+		if fd >= 0 {
+			syscall.Close(fd)
+		}
+		return nil, fmt.Errorf("failed to read response of broker: %w", err)
+	}
+	response = strings.TrimSuffix(response, "\n")
+	if response != okResponse || fd < 0 {
+		if fd >= 0 {
+			syscall.Close(fd)
+		}
+		return nil, fmt.Errorf("broker refused to open device file: %s", strings.TrimPrefix(response, errorResponse))
+	}
+	return os.NewFile(uintptr(fd), "/dev/uinput"), nil
+}
+
+// receivedFd returns the file descriptor passed in the given control messages, or -1 if there is none.
+func receivedFd(oob []byte) (int, error) {
+	if len(oob) == 0 {
+		return -1, nil
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return -1, fmt.Errorf("failed to parse response of broker: %w", err)
+	}
+	for _, msg := range msgs {
+		fds, err := syscall.ParseUnixRights(&msg)
+		if err != nil || len(fds) == 0 {
+			continue
+		}
+		for _, extra := range fds[1:] {
+			syscall.Close(extra)
+		}
+		return fds[0], nil
+	}
+	return -1, errors.New("broker sent an unexpected control message")
+}
+
+// CreateKeyboard creates a keyboard using a device file opened by the broker listening on the given socket (see
+// uinput.CreateKeyboard).
+func CreateKeyboard(socketPath string, name []byte, opts ...uinput.DeviceOption) (uinput.Keyboard, error) {
+	f, err := Open(socketPath)
+	if err != nil {
+		return nil, err
+	}
+	return uinput.CreateKeyboardFromFile(f, name, opts...)
+}
+
+// CreateMouse creates a mouse using a device file opened by the broker listening on the given socket (see
+// uinput.CreateMouse).
+func CreateMouse(socketPath string, name []byte, opts ...uinput.DeviceOption) (uinput.Mouse, error) {
+	f, err := Open(socketPath)
+	if err != nil {
+		return nil, err
+	}
+	return uinput.CreateMouseFromFile(f, name, opts...)
+}
@@ -0,0 +1,18 @@
+// Package broker allows unprivileged programs to create virtual devices. A privileged Broker opens the uinput device
+// file on behalf of its clients and passes the file descriptor over a unix domain socket (SCM_RIGHTS). Since access
+// to uinput is checked upon opening the device file only, the clients are able to create and use devices on their own
+// afterwards, e.g. using CreateKeyboard, which returns a regular uinput.Keyboard.
+//
+// The broker is meant to run as a (socket activated) systemd service:
+//
+//	# uinput-broker.socket
+//	[Socket]
+//	ListenStream=/run/uinput-broker.sock
+//	SocketMode=0660
+//	SocketGroup=input
+//
+// Any client that may connect to the socket and is authorized is able to inject input events. By default, only clients
+// running as the same user as the broker are authorized, so a broker running as root needs to set Broker.Authorize in
+// order to serve other users (e.g. the members of the group of the socket). Access should additionally be restricted
+// using the permissions of the socket. The package is only supported on linux.
+package broker
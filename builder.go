@@ -0,0 +1,109 @@
+package uinput
+
+import "sort"
+
+// A DeviceBuilder declares the capabilities of a device that is not covered by any of the other device types (e.g. a
+// volume knob or an exotic remote control). Capabilities are declared by chaining calls to the builder, before the device
+// is created using Create:
+//
+//	dev, err := NewDeviceBuilder().
+//		Keys(KeyVolumeup, KeyVolumedown, KeyMute).
+//		Rel(0x07). // REL_DIAL
+//		Create("/dev/uinput", []byte("volume knob"))
+//
+// Event types (EV_KEY, EV_ABS, etc.) are registered implicitly for all codes that have been declared, so EventTypes
+// only needs to be used for event types without codes (e.g. EV_REP).
+type DeviceBuilder struct {
+	evTypes map[int]bool
+	caps    Capabilities
+}
+
+// NewDeviceBuilder returns a builder for a device without any capabilities.
+func NewDeviceBuilder() *DeviceBuilder {
+	return &DeviceBuilder{evTypes: make(map[int]bool)}
+}
+
+// EventTypes declares the given event types.
+func (b *DeviceBuilder) EventTypes(evTypes ...int) *DeviceBuilder {
+	for _, evType := range evTypes {
+		b.evTypes[evType] = true
+	}
+	return b
+}
+
+// Keys declares the given key and button codes.
+func (b *DeviceBuilder) Keys(codes ...int) *DeviceBuilder {
+	b.caps.Key = append(b.caps.Key, codes...)
+	return b.EventTypes(evKey)
+}
+
+// Rel declares the given relative axes.
+func (b *DeviceBuilder) Rel(codes ...int) *DeviceBuilder {
+	b.caps.Rel = append(b.caps.Rel, codes...)
+	return b.EventTypes(evRel)
+}
+
+// Abs declares an absolute axis reporting values within the given range.
+func (b *DeviceBuilder) Abs(code int, min int32, max int32) *DeviceBuilder {
+	b.caps.Abs = append(b.caps.Abs, code)
+	if b.caps.AbsRanges == nil {
+		b.caps.AbsRanges = make(map[int]AbsRange)
+	}
+	b.caps.AbsRanges[code] = AbsRange{Min: min, Max: max}
+	return b.EventTypes(evAbs)
+}
+
+// AbsAxis declares an absolute axis reporting values within the given range, which may also define the fuzz, flat and
+// resolution of the axis.
+func (b *DeviceBuilder) AbsAxis(code int, r AbsRange) *DeviceBuilder {
+	b.Abs(code, r.Min, r.Max)
+	b.caps.AbsRanges[code] = r
+	return b
+}
+
+// Msc declares the given misc events (e.g. MSC_SCAN).
+func (b *DeviceBuilder) Msc(codes ...int) *DeviceBuilder {
+	b.caps.Msc = append(b.caps.Msc, codes...)
+	return b.EventTypes(evMsc)
+}
+
+// Switches declares the given switches (e.g. SW_LID).
+func (b *DeviceBuilder) Switches(codes ...int) *DeviceBuilder {
+	b.caps.Sw = append(b.caps.Sw, codes...)
+	return b.EventTypes(evSw)
+}
+
+// LEDs declares the given LEDs (e.g. LED_CAPSL).
+func (b *DeviceBuilder) LEDs(codes ...int) *DeviceBuilder {
+	b.caps.Led = append(b.caps.Led, codes...)
+	return b.EventTypes(evLed)
+}
+
+// Sounds declares the given sounds (e.g. SND_BELL).
+func (b *DeviceBuilder) Sounds(codes ...int) *DeviceBuilder {
+	b.caps.Snd = append(b.caps.Snd, codes...)
+	return b.EventTypes(evSnd)
+}
+
+// Properties declares the given input properties (e.g. INPUT_PROP_DIRECT). Properties are not tied to an event type.
+func (b *DeviceBuilder) Properties(props ...int) *DeviceBuilder {
+	b.caps.Prop = append(b.caps.Prop, props...)
+	return b
+}
+
+// Capabilities returns the capabilities declared so far.
+func (b *DeviceBuilder) Capabilities() Capabilities {
+	caps := b.caps
+	caps.EV = nil
+	for evType := range b.evTypes {
+		caps.EV = append(caps.EV, evType)
+	}
+	sort.Ints(caps.EV)
+	return caps
+}
+
+// Create will create a new device with the declared capabilities. Events are sent to the device using SendEvent and
+// need to be terminated by calling Sync.
+func (b *DeviceBuilder) Create(path string, name []byte, opts ...DeviceOption) (CustomDevice, error) {
+	return CreateFromCapabilities(path, name, b.Capabilities(), opts...)
+}
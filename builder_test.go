@@ -0,0 +1,69 @@
+package uinput
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilderDeclaresCapabilities(t *testing.T) {
+	caps := NewDeviceBuilder().
+		Keys(KeyVolumeup, KeyVolumedown).
+		Abs(absX, 0, 1024).
+		Properties(inputPropDirect).
+		EventTypes(evRep).
+		Capabilities()
+
+	expected := Capabilities{
+		EV:        []int{evKey, evAbs, evRep},
+		Key:       []int{KeyVolumeup, KeyVolumedown},
+		Abs:       []int{absX},
+		Prop:      []int{inputPropDirect},
+		AbsRanges: map[int]AbsRange{absX: {Min: 0, Max: 1024}},
+	}
+	if !reflect.DeepEqual(caps, expected) {
+		t.Fatalf("Expected capabilities %+v, but got %+v\n", expected, caps)
+	}
+}
+
+func TestBuilderCreatesDevice(t *testing.T) {
+	dev, err := NewDeviceBuilder().
+		Keys(KeyVolumeup, KeyVolumedown).
+		Rel(relDial).
+		Create("/dev/uinput", []byte("Test Builder Device"))
+	if err != nil {
+		t.Fatalf("Failed to create the device. Last error was: %s\n", err)
+	}
+
+	err = dev.SendEvent(evRel, relDial, 1)
+	if err != nil {
+		t.Fatalf("Failed to send event. Last error was: %s\n", err)
+	}
+	err = dev.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync. Last error was: %s\n", err)
+	}
+
+	err = dev.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
+
+func TestBuilderFailsOnInvalidName(t *testing.T) {
+	_, err := NewDeviceBuilder().Keys(KeyA).Create("/dev/uinput", []byte(""))
+	if err == nil {
+		t.Fatalf("Expected device creation to fail on an empty name\n")
+	}
+}
+
+func TestBuilderDeclaresAbsAxisInfo(t *testing.T) {
+	r := AbsRange{Min: -100, Max: 100, Fuzz: 2, Flat: 8, Resolution: 12}
+	caps := NewDeviceBuilder().AbsAxis(absY, r).Capabilities()
+
+	if !reflect.DeepEqual(caps.Abs, []int{absY}) || caps.AbsRanges[absY] != r {
+		t.Fatalf("Expected axis %d with range %+v, but got %+v", absY, r, caps)
+	}
+	if !reflect.DeepEqual(caps.EV, []int{evAbs}) {
+		t.Fatalf("Expected EV_ABS to be declared, but got %v", caps.EV)
+	}
+}
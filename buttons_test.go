@@ -0,0 +1,18 @@
+package uinput
+
+import "testing"
+
+func TestButtonAliasesMatchGeneratedCodes(t *testing.T) {
+	aliases := map[string][2]int{
+		"ButtonLeft":      {ButtonLeft, BtnLeft},
+		"ButtonSouth":     {ButtonSouth, BtnSouth},
+		"ButtonMode":      {ButtonMode, BtnMode},
+		"ButtonThumbLeft": {ButtonThumbLeft, BtnThumbl},
+		"ButtonDpadRight": {ButtonDpadRight, BtnDpadRight},
+	}
+	for name, codes := range aliases {
+		if codes[0] != codes[1] {
+			t.Fatalf("Expected %s to be %d, but got %d", name, codes[1], codes[0])
+		}
+	}
+}
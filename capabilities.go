@@ -0,0 +1,278 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Capabilities describes the event codes a device advertises, grouped by event type. Each field holds the supported
+// codes of the respective type (e.g. Key holds all supported key and button codes). Capabilities can be read from a real
+// device using ParseCapabilities, in order to create a virtual device that advertises the exact same capabilities.
+type Capabilities struct {
+	EV   []int
+	Key  []int
+	Rel  []int
+	Abs  []int
+	Msc  []int
+	Sw   []int
+	Led  []int
+	Snd  []int
+	Prop []int
+
+	// AbsRanges optionally holds the value ranges of the absolute axes listed in Abs. Ranges are not part of the
+	// capabilities exposed in sysfs, so axes without a range will default to a range of 0.
+	AbsRanges map[int]AbsRange
+}
+
+// AbsRange is the range of values an absolute axis may report. Fuzz (noise filtering), Flat (dead zone) and Resolution
+// (units per millimeter, or per radian for rotational axes) are optional. Note that the resolution is only applied on
+// kernels supporting UI_ABS_SETUP (4.5 and newer).
+type AbsRange struct {
+	Min        int32
+	Max        int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// A CustomDevice is a device with an arbitrary set of capabilities. Since there are no high-level functions for such a
+// device, events are sent using SendEvent and need to be terminated by calling Sync.
+type CustomDevice interface {
+	// SendEvent will send a single event of the given type and code to the device.
+	SendEvent(evType uint16, code uint16, value int32) error
+
+	// SendRawEvent is the same as SendEvent, which makes custom devices interchangeable with the other devices (e.g. in
+	// order to play back a Macro).
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events by sending a SYN_REPORT.
+	Sync() error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
+	io.Closer
+}
+
+type vCustomDevice struct {
+	name       []byte
+	deviceFile *device
+	onClose    closeHooks
+	mu         deviceMutex
+}
+
+// ParseCapabilities reads the capabilities of an existing input device from sysfs. The given path is the sysfs
+// directory of the device, e.g. /sys/class/input/event3/device, which contains the capabilities directory as well as
+// the properties file.
+// Note that the bitmaps in sysfs consist of words of the kernel's native size, which is assumed to match the word size
+// of the running program.
+func ParseCapabilities(devicePath string) (Capabilities, error) {
+	var caps Capabilities
+	for _, file := range []struct {
+		name  string
+		codes *[]int
+	}{
+		{"capabilities/ev", &caps.EV},
+		{"capabilities/key", &caps.Key},
+		{"capabilities/rel", &caps.Rel},
+		{"capabilities/abs", &caps.Abs},
+		{"capabilities/msc", &caps.Msc},
+		{"capabilities/sw", &caps.Sw},
+		{"capabilities/led", &caps.Led},
+		{"capabilities/snd", &caps.Snd},
+		{"properties", &caps.Prop},
+	} {
+		content, err := ioutil.ReadFile(filepath.Join(devicePath, file.name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Capabilities{}, fmt.Errorf("failed to read %s: %w", file.name, err)
+		}
+		*file.codes, err = parseCapabilityBitmap(string(content), bits.UintSize)
+		if err != nil {
+			return Capabilities{}, fmt.Errorf("failed to parse %s: %w", file.name, err)
+		}
+	}
+	return caps, nil
+}
+
+// parseCapabilityBitmap parses a bitmap in the format used by sysfs: hexadecimal words separated by spaces, with the
+// most significant word first. The codes of all set bits are returned in ascending order.
+func parseCapabilityBitmap(bitmap string, wordSize int) ([]int, error) {
+	words := strings.Fields(bitmap)
+	var codes []int
+	for i := len(words) - 1; i >= 0; i-- {
+		word, err := strconv.ParseUint(words[i], 16, wordSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bitmap word %q: %w", words[i], err)
+		}
+		offset := (len(words) - 1 - i) * wordSize
+		for bit := 0; bit < wordSize; bit++ {
+			if word&(1<<uint(bit)) != 0 {
+				codes = append(codes, offset+bit)
+			}
+		}
+	}
+	return codes, nil
+}
+
+// CreateFromCapabilities will create a new device that advertises the given capabilities. Together with
+// ParseCapabilities, this allows to create a virtual clone of a physical device.
+// Note that force feedback (EV_FF) is not supported and will be left out.
+func CreateFromCapabilities(path string, name []byte, caps Capabilities, opts ...DeviceOption) (CustomDevice, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return createVCustomDevice(path, name, caps, opts)
+}
+
+// CreateFromCapabilitiesFile will create a new custom device just like CreateFromCapabilities, but uses the given
+// uinput device file, which has been opened by the caller already. The custom device takes ownership of the file (see
+// CreateKeyboardFromFile).
+func CreateFromCapabilitiesFile(file *os.File, name []byte, caps Capabilities, opts ...DeviceOption) (CustomDevice, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVCustomDevice(file.Name(), name, caps, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVCustomDevice(path string, name []byte, caps Capabilities, opts []DeviceOption) (CustomDevice, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := createCustomDevice(path, name, caps, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vCustomDevice{name: name, deviceFile: fd, mu: newDeviceMutex(options)}, nil
+}
+
+// NewFromCapabilities is the same as CreateFromCapabilities, but takes the name as a string, which is truncated if it
+// exceeds 80 bytes (see WithNameTruncation).
+func NewFromCapabilities(path string, name string, caps Capabilities, opts ...DeviceOption) (CustomDevice, error) {
+	return CreateFromCapabilities(path, []byte(name), caps, withStringName(opts)...)
+}
+
+// SendEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a set
+// of events.
+func (vc *vCustomDevice) SendEvent(evType uint16, code uint16, value int32) error {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return sendRawEvent(vc.deviceFile, evType, code, value)
+}
+
+// SendRawEvent is the same as SendEvent.
+func (vc *vCustomDevice) SendRawEvent(evType uint16, code uint16, value int32) error {
+	return vc.SendEvent(evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendEvent.
+func (vc *vCustomDevice) Sync() error {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return syncEvents(vc.deviceFile)
+}
+
+// FetchSyspath will return the syspath to the device file.
+func (vc *vCustomDevice) FetchSyspath() (string, error) {
+	return lookupSyspath(vc.deviceFile.File, vc.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vc *vCustomDevice) EventNode() (string, error) {
+	return lookupEventNode(vc.deviceFile.File, vc.name)
+}
+
+// Close closes the device and releases the device.
+func (vc *vCustomDevice) Close() error {
+	err := closeDevice(vc.deviceFile)
+	vc.onClose.run()
+	return err
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vc *vCustomDevice) OnClose(callback func()) {
+	vc.onClose.add(callback)
+}
+
+func createCustomDevice(path string, name []byte, caps Capabilities, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not create custom input device: %w", err)
+	}
+
+	for _, evType := range caps.EV {
+		if evType == evFf {
+			continue
+		}
+		err = registerDevice(deviceFile, uintptr(evType))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register event type %d: %w", evType, err)
+		}
+	}
+
+	for _, bitSet := range []struct {
+		name  string
+		cmd   uintptr
+		codes []int
+	}{
+		{"key", uiSetKeyBit, caps.Key},
+		{"relative axis", uiSetRelBit, caps.Rel},
+		{"absolute axis", uiSetAbsBit, caps.Abs},
+		{"misc", uiSetMscBit, caps.Msc},
+		{"switch", uiSetSwBit, caps.Sw},
+		{"led", uiSetLedBit, caps.Led},
+		{"sound", uiSetSndBit, caps.Snd},
+		{"property", uiSetPropBit, caps.Prop},
+	} {
+		for _, code := range bitSet.codes {
+			err = ioctl(deviceFile, bitSet.cmd, uintptr(code))
+			if err != nil {
+				deviceFile.Close()
+				return nil, fmt.Errorf("failed to register %s event %d: %w", bitSet.name, code, err)
+			}
+		}
+	}
+
+	dev := uinputUserDev{
+		Name: toUinputName(name),
+		ID:   options.inputID(0x0818),
+	}
+	var absRes [absSize]int32
+	for axis, r := range caps.AbsRanges {
+		if axis < 0 || axis >= absSize {
+			deviceFile.Close()
+			return nil, fmt.Errorf("absolute axis %d is out of range", axis)
+		}
+		dev.Absmin[axis] = r.Min
+		dev.Absmax[axis] = r.Max
+		dev.Absfuzz[axis] = r.Fuzz
+		dev.Absflat[axis] = r.Flat
+		absRes[axis] = r.Resolution
+	}
+
+	return createUsbDeviceWithResolution(deviceFile, dev, absRes, options)
+}
@@ -0,0 +1,127 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCapabilityBitmap(t *testing.T) {
+	for _, tc := range []struct {
+		bitmap   string
+		wordSize int
+		expected []int
+	}{
+		{"0\n", 64, nil},
+		{"3\n", 64, []int{0, 1}},
+		{"120013\n", 64, []int{0, 1, 4, 17, 20}},
+		{"1 0 5", 64, []int{0, 2, 128}},
+		{"80000000 1", 32, []int{0, 63}},
+	} {
+		codes, err := parseCapabilityBitmap(tc.bitmap, tc.wordSize)
+		if err != nil {
+			t.Fatalf("Failed to parse bitmap %q: %v", tc.bitmap, err)
+		}
+		if fmt.Sprint(codes) != fmt.Sprint(tc.expected) {
+			t.Fatalf("Expected %v for bitmap %q, but got %v", tc.expected, tc.bitmap, codes)
+		}
+	}
+}
+
+func TestParseCapabilityBitmapFailsOnInvalidInput(t *testing.T) {
+	_, err := parseCapabilityBitmap("12 xyz", 64)
+	if err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+}
+
+func TestParseCapabilities(t *testing.T) {
+	devicePath, err := ioutil.TempDir(os.TempDir(), "uinput-capabilities-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(devicePath)
+
+	err = os.Mkdir(filepath.Join(devicePath, "capabilities"), 0755)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create dir: %v", err)
+	}
+	// the mouse buttons start at code 272, which is bit 16 of the word holding codes 256 and above
+	keys := "70000" + strings.Repeat(" 0", 256/bits.UintSize) + "\n"
+	for file, content := range map[string]string{
+		"capabilities/ev":  "7\n",
+		"capabilities/key": keys,
+		"capabilities/rel": "103\n",
+		"properties":       "0\n",
+	} {
+		err = ioutil.WriteFile(filepath.Join(devicePath, file), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to setup test. Unable to write file: %v", err)
+		}
+	}
+
+	caps, err := ParseCapabilities(devicePath)
+	if err != nil {
+		t.Fatalf("Failed to parse capabilities: %v", err)
+	}
+	if fmt.Sprint(caps.EV) != fmt.Sprint([]int{evSyn, evKey, evRel}) {
+		t.Fatalf("Unexpected event types: %v", caps.EV)
+	}
+	if fmt.Sprint(caps.Key) != fmt.Sprint([]int{evMouseBtnLeft, evMouseBtnRight, evMouseBtnMiddle}) {
+		t.Fatalf("Unexpected keys: %v", caps.Key)
+	}
+	if fmt.Sprint(caps.Rel) != fmt.Sprint([]int{relX, relY, relWheel}) {
+		t.Fatalf("Unexpected relative axes: %v", caps.Rel)
+	}
+	if len(caps.Abs) != 0 || len(caps.Prop) != 0 {
+		t.Fatalf("Expected no absolute axes and properties, but got %v and %v", caps.Abs, caps.Prop)
+	}
+}
+
+func TestCreateFromCapabilities(t *testing.T) {
+	caps := Capabilities{
+		EV:  []int{evKey, evRel},
+		Key: []int{evMouseBtnLeft, evMouseBtnRight},
+		Rel: []int{relX, relY},
+	}
+	dev, err := CreateFromCapabilities("/dev/uinput", []byte("Test Cloned Mouse"), caps)
+	if err != nil {
+		t.Fatalf("Failed to create the custom device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	err = dev.SendEvent(evRel, relX, 10)
+	if err != nil {
+		t.Fatalf("Failed to send event. Last error was: %s\n", err)
+	}
+	err = dev.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync events. Last error was: %s\n", err)
+	}
+}
+
+func TestCreateFromCapabilitiesFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateFromCapabilities("", []byte("CustomDevice"), Capabilities{})
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestCreateFromCapabilitiesFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-custom-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register event type 1: failed to close device: inappropriate ioctl for device"
+	_, err = CreateFromCapabilities(file.Name(), []byte("CustomDevice"), Capabilities{EV: []int{evKey}})
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
@@ -0,0 +1,481 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// A ClickPad is a multi-touch touch pad without separate buttons, whose whole surface can be pressed down instead, like
+// the touch pads of most current laptops. Contacts are reported using the multi-touch protocol type B just like for the
+// TouchScreen, along with the BTN_TOOL_* events that tell the number of contacts. Since the device is marked as a button
+// pad (INPUT_PROP_BUTTONPAD), libinput applies its touch pad features to it, like tap-to-click, clickfinger and software
+// buttons, which makes the device suitable for integration tests of these features.
+type ClickPad interface {
+	// TouchDown will put a new finger down in the given slot at the given position.
+	TouchDown(slot int, x int32, y int32) error
+
+	// TouchDownWithTool will put a new contact of the given kind down in the given slot at the given position.
+	TouchDownWithTool(slot int, x int32, y int32, tool ContactTool) error
+
+	// TouchMove will move the contact in the given slot to the given position.
+	TouchMove(slot int, x int32, y int32) error
+
+	// SetTool will change the kind of the contact in the given slot, e.g. when a finger turns out to be a palm.
+	SetTool(slot int, tool ContactTool) error
+
+	// TouchUp will lift the contact in the given slot.
+	TouchUp(slot int) error
+
+	// Tap will briefly put a finger down in the given slot at the given position and lift it again.
+	Tap(slot int, x int32, y int32) error
+
+	// Click will press the pad down and release it again.
+	Click() error
+
+	// ClickPress will press the pad down. Note that the pad will not be released until ClickRelease is invoked.
+	ClickPress() error
+
+	// ClickRelease will release the pad pressed down by ClickPress.
+	ClickRelease() error
+
+	// TwoFingerScroll will perform a scroll gesture, moving two fingers by the given distance.
+	TwoFingerScroll(dx int32, dy int32) error
+
+	// Pinch will perform a pinch gesture, changing the distance between two fingers by the given factor.
+	Pinch(scale float64) error
+
+	// Swipe will perform a swipe gesture using the given number of fingers.
+	Swipe(fingers int, direction SwipeDirection) error
+
+	// Rotate will perform a rotation gesture, turning two fingers around their center by the given angle in degrees.
+	Rotate(degrees float64) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
+	io.Closer
+}
+
+// A ContactTool is the kind of contact touching a ClickPad.
+type ContactTool int
+
+const (
+	// ToolFinger is a finger, which is the kind of contact put down by TouchDown.
+	ToolFinger ContactTool = iota
+	// ToolThumb is a thumb, which is reported as a finger with a higher pressure. libinput only detects thumbs by their
+	// pressure if a thumb pressure threshold is configured for the device using a quirk (AttrThumbPressureThreshold).
+	ToolThumb
+	// ToolPalm is a palm resting on the pad, which is reported as MT_TOOL_PALM along with the maximum pressure.
+	ToolPalm
+)
+
+// the pressure reported for each kind of contact. Fingers stay below the default palm pressure threshold of libinput
+// (130), while exceeding its default touch threshold.
+const (
+	clickPadFingerPressure = 80
+	clickPadThumbPressure  = 120
+	clickPadMaxPressure    = 255
+)
+
+// clickPadTools are the BTN_TOOL_* codes reported for one to five contacts. Five or more contacts are all reported as
+// BTN_TOOL_QUINTTAP.
+var clickPadTools = []int{evBtnToolFinger, evBtnToolDoubletap, evBtnToolTripletap, evBtnToolQuadtap, evBtnToolQuinttap}
+
+type vClickPad struct {
+	// the touch screen keeps track of the contacts in the slots, which is the same for both kinds of devices. Its mutex
+	// locks the click pad as well.
+	vs    *vTouchScreen
+	tools map[int]ContactTool
+}
+
+// CreateClickPad will create a new click pad. Just like for the touch screen, the x and y-axis boundaries (min and max)
+// need to be defined upon creation, along with the number of slots, which is the maximum number of contacts that may
+// touch the pad at the same time. libinput uses the resolution of the axes to size features like the software buttons,
+// which may be set using WithAxis.
+func CreateClickPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (ClickPad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return createVClickPad(path, name, minX, maxX, minY, maxY, slots, opts)
+}
+
+// CreateClickPadFromFile will create a new click pad just like CreateClickPad, but uses the given uinput device file,
+// which has been opened by the caller already. The click pad takes ownership of the file (see CreateKeyboardFromFile).
+func CreateClickPadFromFile(file *os.File, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (ClickPad, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVClickPad(file.Name(), name, minX, maxX, minY, maxY, slots, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVClickPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts []DeviceOption) (ClickPad, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+	if minX >= maxX || minY >= maxY {
+		return nil, fmt.Errorf("invalid pad area. Minimum values must be less than maximum values")
+	}
+	if slots <= 0 {
+		return nil, fmt.Errorf("%d is not a valid number of slots. Expected a positive value", slots)
+	}
+
+	fd, err := createClickPad(path, name, minX, maxX, minY, maxY, slots, options)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := &vTouchScreen{name: name, deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, slots: slots,
+		active: make(map[int]bool), countContacts: true, mu: newDeviceMutex(options)}
+	return &vClickPad{vs: vs, tools: make(map[int]ContactTool)}, nil
+}
+
+// NewClickPad is the same as CreateClickPad, but takes the name as a string, which is truncated if it exceeds 80 bytes
+// (see WithNameTruncation).
+func NewClickPad(path string, name string, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (ClickPad, error) {
+	return CreateClickPad(path, []byte(name), minX, maxX, minY, maxY, slots, withStringName(opts)...)
+}
+
+// TouchDown will put a new finger down in the given slot at the given position. The slot must not hold a contact
+// already.
+func (vc *vClickPad) TouchDown(slot int, x int32, y int32) error {
+	return vc.TouchDownWithTool(slot, x, y, ToolFinger)
+}
+
+// TouchDownWithTool will put a new contact of the given kind down in the given slot at the given position. The first
+// contact on the pad will also cause a BTN_TOUCH press to be reported, and the BTN_TOOL_* event is updated to the new
+// number of contacts.
+func (vc *vClickPad) TouchDownWithTool(slot int, x int32, y int32, tool ContactTool) error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	return vc.touchDown(slot, x, y, tool)
+}
+
+// touchDown puts a new contact of the given kind down. The device needs to be locked by the caller.
+func (vc *vClickPad) touchDown(slot int, x int32, y int32, tool ContactTool) error {
+	toolEvents, err := contactToolEvents(tool)
+	if err != nil {
+		return fmt.Errorf("failed to perform TouchDown: %w", err)
+	}
+	f := vc.vs.frame()
+	err = f.touchDown(slot, x, y)
+	if err != nil {
+		return err
+	}
+	f.events = append(f.events, toolEvents...)
+	err = f.send()
+	if err != nil {
+		return err
+	}
+	vc.tools[slot] = tool
+	return nil
+}
+
+// TouchMove will move the contact in the given slot to the given position.
+func (vc *vClickPad) TouchMove(slot int, x int32, y int32) error {
+	return vc.vs.TouchMove(slot, x, y)
+}
+
+// SetTool will change the kind of the contact in the given slot, which allows to test how a consumer handles a finger
+// turning into a palm and vice versa.
+func (vc *vClickPad) SetTool(slot int, tool ContactTool) error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	err := vc.vs.validateSlot(slot)
+	if err != nil {
+		return fmt.Errorf("failed to perform SetTool: %w", err)
+	}
+	if !vc.vs.active[slot] {
+		return fmt.Errorf("failed to perform SetTool. Slot %d does not hold a contact", slot)
+	}
+	toolEvents, err := contactToolEvents(tool)
+	if err != nil {
+		return fmt.Errorf("failed to perform SetTool: %w", err)
+	}
+	err = vc.vs.sendEvents(append([]inputEvent{{Type: evAbs, Code: absMTSlot, Value: int32(slot)}}, toolEvents...))
+	if err != nil {
+		return err
+	}
+	vc.tools[slot] = tool
+	return nil
+}
+
+// TouchUp will lift the contact in the given slot. Lifting the last contact on the pad will also cause a BTN_TOUCH
+// release to be reported.
+func (vc *vClickPad) TouchUp(slot int) error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	return vc.touchUp(slot)
+}
+
+// touchUp lifts the contact in the given slot. The device needs to be locked by the caller.
+func (vc *vClickPad) touchUp(slot int) error {
+	f := vc.vs.frame()
+	err := f.touchUp(slot)
+	if err != nil {
+		return err
+	}
+	err = f.send()
+	if err != nil {
+		return err
+	}
+	delete(vc.tools, slot)
+	return nil
+}
+
+// Tap will put a finger down in the given slot at the given position and lift it right away, which libinput turns into
+// a click if tap-to-click is enabled. Tapping with several fingers at once requires putting them down using TouchDown
+// and lifting them using TouchUp.
+func (vc *vClickPad) Tap(slot int, x int32, y int32) error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	err := vc.touchDown(slot, x, y, ToolFinger)
+	if err != nil {
+		return err
+	}
+	return vc.touchUp(slot)
+}
+
+// TwoFingerScroll will put down two fingers next to each other in the center of the pad, move both by the given
+// distance and lift them again (see TouchScreen.TwoFingerScroll). libinput turns the gesture into scroll events, if
+// two finger scrolling is enabled. The gesture takes about 200ms.
+func (vc *vClickPad) TwoFingerScroll(dx int32, dy int32) error {
+	start, end, err := vc.vs.twoFingerScrollPoints(dx, dy)
+	if err != nil {
+		return err
+	}
+	return vc.gesture(start, end)
+}
+
+// Pinch will put down two fingers in the center of the pad and move them apart (scale > 1) or together (scale < 1)
+// (see TouchScreen.Pinch), which libinput reports as a pinch gesture. The gesture takes about 200ms.
+func (vc *vClickPad) Pinch(scale float64) error {
+	start, end, err := vc.vs.pinchPoints(scale)
+	if err != nil {
+		return err
+	}
+	return vc.gesture(start, end)
+}
+
+// Swipe will put down the given number of fingers in a row in the center of the pad and move them in the given
+// direction (see TouchScreen.Swipe). libinput reports swipes of three or more fingers as swipe gestures, which desktops
+// commonly use in order to switch workspaces. The gesture takes about 200ms.
+func (vc *vClickPad) Swipe(fingers int, direction SwipeDirection) error {
+	start, end, err := vc.vs.swipePoints(fingers, direction)
+	if err != nil {
+		return err
+	}
+	return vc.gesture(start, end)
+}
+
+// Rotate will put down two fingers around the center of the pad and turn them by the given angle in degrees (see
+// TouchScreen.Rotate). libinput reports the rotation as part of a pinch gesture. The gesture takes about 200ms.
+func (vc *vClickPad) Rotate(degrees float64) error {
+	path, err := vc.vs.rotatePath(degrees)
+	if err != nil {
+		return err
+	}
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	return vc.vs.gesturePath(path, fingerEvents())
+}
+
+// gesture performs a gesture using fingers, whose number is reported by the BTN_TOOL_* events along with the contacts.
+func (vc *vClickPad) gesture(start []point, end []point) error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	return vc.vs.gesture(start, end, fingerEvents())
+}
+
+// fingerEvents returns the events that mark a contact as a finger.
+func fingerEvents() []inputEvent {
+	finger, _ := contactToolEvents(ToolFinger)
+	return finger
+}
+
+// Click will press the pad down and release it again. Since the pad only has a single button, libinput decides which
+// button is clicked based on the position or the number of the contacts on the pad, depending on its click method.
+func (vc *vClickPad) Click() error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	err := sendBtnEvent(vc.vs.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the Click event: %w", err)
+	}
+	return sendBtnEvent(vc.vs.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
+}
+
+// ClickPress will press the pad down. Note that the pad will not be released until ClickRelease is invoked.
+func (vc *vClickPad) ClickPress() error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	return sendBtnEvent(vc.vs.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
+}
+
+// ClickRelease will release the pad pressed down by ClickPress.
+func (vc *vClickPad) ClickRelease() error {
+	vc.vs.mu.Lock()
+	defer vc.vs.mu.Unlock()
+	return sendBtnEvent(vc.vs.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
+}
+
+// FetchSyspath will return the syspath to the device file.
+func (vc *vClickPad) FetchSyspath() (string, error) {
+	return vc.vs.FetchSyspath()
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vc *vClickPad) EventNode() (string, error) {
+	return vc.vs.EventNode()
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vc *vClickPad) SendRawEvent(evType uint16, code uint16, value int32) error {
+	return vc.vs.SendRawEvent(evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vc *vClickPad) Sync() error {
+	return vc.vs.Sync()
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vc *vClickPad) OnClose(callback func()) {
+	vc.vs.OnClose(callback)
+}
+
+// Close closes the device and releases the device.
+func (vc *vClickPad) Close() error {
+	return vc.vs.Close()
+}
+
+// contactToolEvents returns the ABS_MT_TOOL_TYPE and ABS_MT_PRESSURE events that report the kind of a contact.
+func contactToolEvents(tool ContactTool) ([]inputEvent, error) {
+	toolType, pressure := int32(mtToolFinger), int32(clickPadFingerPressure)
+	switch tool {
+	case ToolFinger:
+	case ToolThumb:
+		pressure = clickPadThumbPressure
+	case ToolPalm:
+		toolType, pressure = mtToolPalm, clickPadMaxPressure
+	default:
+		return nil, fmt.Errorf("unknown contact tool %d", tool)
+	}
+	return []inputEvent{
+		{Type: evAbs, Code: absMTToolType, Value: toolType},
+		{Type: evAbs, Code: absMTPressure, Value: pressure},
+	}, nil
+}
+
+// contactCountEvents returns the BTN_TOOL_* events that report a change in the number of contacts on the pad.
+func contactCountEvents(before int, after int) []inputEvent {
+	if contactCountTool(before) == contactCountTool(after) {
+		return nil
+	}
+	var events []inputEvent
+	if before > 0 {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(contactCountTool(before)), Value: btnStateReleased})
+	}
+	if after > 0 {
+		events = append(events, inputEvent{Type: evKey, Code: uint16(contactCountTool(after)), Value: btnStatePressed})
+	}
+	return events
+}
+
+// contactCountTool returns the BTN_TOOL_* code for the given number of contacts, or -1 if there is no contact.
+func contactCountTool(contacts int) int {
+	if contacts <= 0 {
+		return -1
+	}
+	if contacts > len(clickPadTools) {
+		contacts = len(clickPadTools)
+	}
+	return clickPadTools[contacts-1]
+}
+
+func createClickPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not create click pad input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	// the pad itself is the left button, while the tools report the number of contacts
+	for _, event := range append([]int{evMouseBtnLeft, evBtnTouch}, clickPadTools...) {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+	for _, event := range []int{absX, absY, absMTSlot, absMTTrackingID, absMTPositionX, absMTPositionY, absMTToolType, absMTPressure} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	// mark the device as a touch pad that moves a cursor indirectly and can be pressed down as a whole
+	for _, prop := range []int{inputPropPointer, inputPropButtonpad} {
+		err = ioctl(deviceFile, uiSetPropBit, uintptr(prop))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register input property %d: %w", prop, err)
+		}
+	}
+
+	var absMin [absSize]int32
+	absMin[absX] = minX
+	absMin[absY] = minY
+	absMin[absMTPositionX] = minX
+	absMin[absMTPositionY] = minY
+
+	var absMax [absSize]int32
+	absMax[absX] = maxX
+	absMax[absY] = maxY
+	absMax[absMTPositionX] = maxX
+	absMax[absMTPositionY] = maxY
+	absMax[absMTSlot] = int32(slots - 1)
+	absMax[absMTTrackingID] = maxTrackingID
+	absMax[absMTToolType] = mtToolPalm
+	absMax[absMTPressure] = clickPadMaxPressure
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     options.inputID(0x081d),
+			Absmin: absMin,
+			Absmax: absMax}, options)
+}
@@ -0,0 +1,261 @@
+package uinput
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestClickPad(t *testing.T) *vClickPad {
+	file := createTestEventFile(t)
+	vs := &vTouchScreen{deviceFile: &device{File: file}, maxX: 1024, maxY: 768, slots: 4, active: make(map[int]bool), countContacts: true}
+	return &vClickPad{vs: vs, tools: make(map[int]ContactTool)}
+}
+
+func TestClickPad(t *testing.T) {
+	cp, err := CreateClickPad("/dev/uinput", []byte("Test ClickPad"), 0, 1024, 0, 768, 3)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual click pad. Last error was: %s\n", err)
+	}
+	defer cp.Close()
+
+	err = cp.Tap(0, 100, 100)
+	if err != nil {
+		t.Fatalf("Failed to tap. Last error was: %s\n", err)
+	}
+	err = cp.TouchDown(0, 900, 700)
+	if err != nil {
+		t.Fatalf("Failed to put down finger. Last error was: %s\n", err)
+	}
+	err = cp.Click()
+	if err != nil {
+		t.Fatalf("Failed to click. Last error was: %s\n", err)
+	}
+	err = cp.TouchUp(0)
+	if err != nil {
+		t.Fatalf("Failed to lift finger. Last error was: %s\n", err)
+	}
+}
+
+func TestClickPadReportsNumberOfContacts(t *testing.T) {
+	cp := newTestClickPad(t)
+	defer cp.vs.deviceFile.Close()
+
+	if err := cp.TouchDown(0, 10, 20); err != nil {
+		t.Fatalf("Failed to put down first finger: %v", err)
+	}
+	if err := cp.TouchDown(1, 30, 40); err != nil {
+		t.Fatalf("Failed to put down second finger: %v", err)
+	}
+	if err := cp.TouchUp(0); err != nil {
+		t.Fatalf("Failed to lift first finger: %v", err)
+	}
+	if err := cp.TouchUp(1); err != nil {
+		t.Fatalf("Failed to lift second finger: %v", err)
+	}
+
+	events := readTestEvents(t, cp.vs.deviceFile.File)
+	expected := []inputEvent{
+		{Type: evAbs, Code: absMTSlot, Value: 0},
+		{Type: evAbs, Code: absMTTrackingID, Value: 0},
+		{Type: evAbs, Code: absMTPositionX, Value: 10},
+		{Type: evAbs, Code: absMTPositionY, Value: 20},
+		{Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+		{Type: evAbs, Code: absX, Value: 10},
+		{Type: evAbs, Code: absY, Value: 20},
+		{Type: evAbs, Code: absMTToolType, Value: mtToolFinger},
+		{Type: evAbs, Code: absMTPressure, Value: clickPadFingerPressure},
+		{Type: evKey, Code: evBtnToolFinger, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absMTSlot, Value: 1},
+		{Type: evAbs, Code: absMTTrackingID, Value: 1},
+		{Type: evAbs, Code: absMTPositionX, Value: 30},
+		{Type: evAbs, Code: absMTPositionY, Value: 40},
+		{Type: evAbs, Code: absMTToolType, Value: mtToolFinger},
+		{Type: evAbs, Code: absMTPressure, Value: clickPadFingerPressure},
+		{Type: evKey, Code: evBtnToolFinger, Value: btnStateReleased},
+		{Type: evKey, Code: evBtnToolDoubletap, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absMTSlot, Value: 0},
+		{Type: evAbs, Code: absMTTrackingID, Value: -1},
+		{Type: evKey, Code: evBtnToolDoubletap, Value: btnStateReleased},
+		{Type: evKey, Code: evBtnToolFinger, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absMTSlot, Value: 1},
+		{Type: evAbs, Code: absMTTrackingID, Value: -1},
+		{Type: evKey, Code: evBtnTouch, Value: btnStateReleased},
+		{Type: evKey, Code: evBtnToolFinger, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestClickPadReportsPalms(t *testing.T) {
+	cp := newTestClickPad(t)
+	defer cp.vs.deviceFile.Close()
+
+	if err := cp.TouchDownWithTool(2, 10, 20, ToolThumb); err != nil {
+		t.Fatalf("Failed to put down thumb: %v", err)
+	}
+	if err := cp.SetTool(2, ToolPalm); err != nil {
+		t.Fatalf("Failed to turn the thumb into a palm: %v", err)
+	}
+
+	events := readTestEvents(t, cp.vs.deviceFile.File)
+	if len(events) < 5 {
+		t.Fatalf("Expected the contact to be reported, but got %+v", events)
+	}
+	expected := []inputEvent{
+		{Type: evAbs, Code: absMTSlot, Value: 2},
+		{Type: evAbs, Code: absMTToolType, Value: mtToolPalm},
+		{Type: evAbs, Code: absMTPressure, Value: clickPadMaxPressure},
+		{Type: evSyn, Code: synReport},
+	}
+	palm := events[len(events)-len(expected):]
+	for i := range expected {
+		if palm[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, palm[i])
+		}
+	}
+	thumb := inputEvent{Type: evAbs, Code: absMTPressure, Value: clickPadThumbPressure}
+	if events[len(events)-len(expected)-3] != thumb {
+		t.Fatalf("Expected the thumb to be reported with pressure %d, but got %+v", clickPadThumbPressure, events)
+	}
+}
+
+func TestClickPadGesturesReportNumberOfFingers(t *testing.T) {
+	for fingers, tool := range map[int]uint16{2: evBtnToolDoubletap, 3: evBtnToolTripletap, 4: evBtnToolQuadtap} {
+		cp := newTestClickPad(t)
+		var err error
+		if fingers == 2 {
+			err = cp.TwoFingerScroll(0, 100)
+		} else {
+			err = cp.Swipe(fingers, SwipeUp)
+		}
+		if err != nil {
+			t.Fatalf("Failed to perform gesture with %d fingers: %v", fingers, err)
+		}
+
+		frames := splitFrames(t, readTestEvents(t, cp.vs.deviceFile.File))
+		_ = cp.vs.deviceFile.Close()
+		if len(frames) != gestureSteps+2 {
+			t.Fatalf("Expected %d frames, but got %d", gestureSteps+2, len(frames))
+		}
+		first, last := frames[0], frames[len(frames)-1]
+		pressed := inputEvent{Type: evKey, Code: tool, Value: btnStatePressed}
+		released := inputEvent{Type: evKey, Code: tool, Value: btnStateReleased}
+		if first[len(first)-1] != pressed || last[len(last)-1] != released {
+			t.Fatalf("Expected the gesture to report %d fingers using tool %d, but got %+v and %+v", fingers, tool, first, last)
+		}
+		var pressures int
+		for _, ev := range first {
+			if ev.Type == evAbs && ev.Code == absMTPressure && ev.Value == clickPadFingerPressure {
+				pressures++
+			}
+		}
+		if pressures != fingers {
+			t.Fatalf("Expected the pressure of %d fingers to be reported, but got %d", fingers, pressures)
+		}
+		if len(cp.vs.active) != 0 {
+			t.Fatalf("Expected all fingers to be lifted")
+		}
+	}
+}
+
+func TestClickPadPinchAndRotate(t *testing.T) {
+	cp := newTestClickPad(t)
+	defer cp.vs.deviceFile.Close()
+
+	err := cp.Pinch(0.5)
+	if err != nil {
+		t.Fatalf("Failed to perform gesture: %v", err)
+	}
+	positions := slotPositions(splitFrames(t, readTestEvents(t, cp.vs.deviceFile.File)))
+	first, second := positions[0], positions[1]
+	if second[len(second)-1]-first[len(first)-1] != (second[0]-first[0])/2 {
+		t.Fatalf("Expected the distance between the fingers to be halved, but got %v and %v", first, second)
+	}
+
+	cp = newTestClickPad(t)
+	defer cp.vs.deviceFile.Close()
+
+	err = cp.Rotate(-90)
+	if err != nil {
+		t.Fatalf("Failed to perform gesture: %v", err)
+	}
+	frames := splitFrames(t, readTestEvents(t, cp.vs.deviceFile.File))
+	doubleTap := inputEvent{Type: evKey, Code: evBtnToolDoubletap, Value: btnStatePressed}
+	if len(frames) != gestureSteps+2 || frames[0][len(frames[0])-1] != doubleTap {
+		t.Fatalf("Expected the rotation to be performed by two fingers, but got %v", frames)
+	}
+}
+
+func TestClickPadClickPressesLeftButton(t *testing.T) {
+	cp := newTestClickPad(t)
+	defer cp.vs.deviceFile.Close()
+
+	if err := cp.Click(); err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+
+	events := readTestEvents(t, cp.vs.deviceFile.File)
+	expected := []inputEvent{
+		{Type: evKey, Code: evMouseBtnLeft, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: evMouseBtnLeft, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestClickPadFailsOnInvalidToolUsage(t *testing.T) {
+	cp := newTestClickPad(t)
+	defer cp.vs.deviceFile.Close()
+
+	if err := cp.SetTool(0, ToolPalm); err == nil {
+		t.Fatalf("Expected SetTool to fail for a slot without contact, but got no error.")
+	}
+	if err := cp.TouchDownWithTool(0, 0, 0, ContactTool(42)); err == nil {
+		t.Fatalf("Expected TouchDownWithTool to fail for an unknown tool, but got no error.")
+	}
+	if len(cp.vs.active) != 0 {
+		t.Fatalf("Expected the contact not to be put down after a failure, but got %v", cp.vs.active)
+	}
+}
+
+func TestClickPadCreationFailsOnInvalidSlots(t *testing.T) {
+	_, err := CreateClickPad("/dev/uinput", []byte("ClickPadDevice"), 0, 1024, 0, 768, 0)
+	if err == nil {
+		t.Fatalf("Expected creation to fail due to an invalid number of slots, but got no error.")
+	}
+}
+
+func TestClickPadCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateClickPad("", []byte("ClickPadDevice"), 0, 1024, 0, 768, 2)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestClickPadCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreateClickPad("/dev/uinput", []byte(name), 0, 1024, 0, 768, 2)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
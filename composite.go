@@ -0,0 +1,57 @@
+package uinput
+
+// compositeMouseSuffix is appended to the name of the mouse of a composite device, just like many wireless receivers name
+// the mouse they provide along with a keyboard.
+const compositeMouseSuffix = " Mouse"
+
+// A Composite bundles a keyboard and a mouse, which are created and closed together. This suits applications that
+// forward both keyboard and pointer input (like remote desktop servers), which otherwise need to manage two devices.
+type Composite struct {
+	Keyboard Keyboard
+	Mouse    Mouse
+}
+
+// CreateComposite will create a keyboard and a mouse. The keyboard will be named as given, while the mouse will carry
+// the name followed by " Mouse". The options apply to both devices.
+func CreateComposite(path string, name []byte, opts ...DeviceOption) (*Composite, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	options := newDeviceOptions(opts)
+	name, err = prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+	mouseName, err := prepareUinputName(append(append([]byte{}, name...), compositeMouseSuffix...), options)
+	if err != nil {
+		return nil, err
+	}
+
+	keyboard, err := CreateKeyboard(path, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	mouse, err := CreateMouse(path, mouseName, opts...)
+	if err != nil {
+		_ = keyboard.Close()
+		return nil, err
+	}
+	return &Composite{Keyboard: keyboard, Mouse: mouse}, nil
+}
+
+// NewComposite is the same as CreateComposite, but takes the name as a string, which is truncated if it exceeds 80 bytes
+// (see WithNameTruncation).
+func NewComposite(path string, name string, opts ...DeviceOption) (*Composite, error) {
+	return CreateComposite(path, []byte(name), withStringName(opts)...)
+}
+
+// Close closes both the keyboard and the mouse. The mouse is closed even if closing the keyboard fails.
+func (c *Composite) Close() error {
+	err := c.Keyboard.Close()
+	mouseErr := c.Mouse.Close()
+	if err != nil {
+		return err
+	}
+	return mouseErr
+}
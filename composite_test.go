@@ -0,0 +1,64 @@
+package uinput
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompositeCreation(t *testing.T) {
+	composite, err := CreateComposite("/dev/uinput", []byte("Test Composite"))
+	if err != nil {
+		t.Fatalf("Failed to create the composite device. Last error was: %s\n", err)
+	}
+	defer composite.Close()
+
+	err = composite.Keyboard.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+	err = composite.Mouse.Move(10, 10)
+	if err != nil {
+		t.Fatalf("Failed to move mouse. Last error was: %s\n", err)
+	}
+}
+
+func TestCompositeCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateComposite("", []byte("CompositeDevice"))
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestCompositeCreationFailsIfMouseNameIsTooLong(t *testing.T) {
+	name := strings.Repeat("a", uinputMaxNameSize-len(compositeMouseSuffix)+1)
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name+compositeMouseSuffix, uinputMaxNameSize)
+	_, err := CreateComposite("/dev/uinput", []byte(name))
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestCompositeClosesBothDevices(t *testing.T) {
+	fake := NewFake()
+	keyboard, err := fake.CreateKeyboard()
+	if err != nil {
+		t.Fatalf("Failed to create fake keyboard: %v", err)
+	}
+	mouse, err := fake.CreateMouse()
+	if err != nil {
+		t.Fatalf("Failed to create fake mouse: %v", err)
+	}
+	composite := &Composite{Keyboard: keyboard, Mouse: mouse}
+
+	if err := composite.Close(); err != nil {
+		t.Fatalf("Failed to close composite device: %v", err)
+	}
+	if err := keyboard.KeyPress(KeyA); err == nil {
+		t.Fatalf("Expected the keyboard to be closed")
+	}
+	if err := mouse.Move(1, 1); err == nil {
+		t.Fatalf("Expected the mouse to be closed")
+	}
+}
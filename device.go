@@ -0,0 +1,41 @@
+package uinput
+
+import (
+	"os"
+	"time"
+)
+
+// A device is the device file of a virtual device, along with the settings and state its events are written with. It
+// is created once the device has been set up, so that everything needed to write an event is at hand without looking
+// it up elsewhere.
+type device struct {
+	*os.File
+
+	// unsynced is set while the last event written to the device has not been terminated by a sync event yet (e.g.
+	// events sent by SendRawEvent without calling Sync). It is accessed atomically.
+	unsynced int32
+	// destroyDelay is the delay set using WithDestroyDelay.
+	destroyDelay time.Duration
+	// observer is the callback set using WithEventObserver (if any).
+	observer func(Event)
+	// limiter paces the events of the device, if a maximum event rate is set using WithMaxEventRate.
+	limiter *rateLimiter
+	// timestamps is set if events are stamped with the time they are sent at, as set using WithMonotonicTimestamps.
+	timestamps bool
+	// mode is how events are written to the device, as set using WithNonBlockingWrites and WithWriteTimeout.
+	mode writeMode
+	// fake records the events of a fake device (see Fake), which has no device file.
+	fake *fakeFile
+}
+
+// newDevice returns the device of the given device file, configured according to the given options.
+func newDevice(file *os.File, options deviceOptions) *device {
+	return &device{
+		File:         file,
+		destroyDelay: options.destroyDelay,
+		observer:     options.observer,
+		limiter:      newRateLimiter(options),
+		timestamps:   options.timestamps,
+		mode:         writeMode{nonBlocking: options.nonBlocking, timeout: options.writeTimeout},
+	}
+}
@@ -7,93 +7,222 @@ import (
 	"syscall"
 )
 
-// A Dial is a device that will trigger rotation events.
+// A Dial is a device that will trigger rotation events, like a rotary encoder or the Surface Dial. Besides rotating, the
+// dial may be pressed like a button.
 // For details see: https://www.kernel.org/doc/Documentation/input/event-codes.txt
 type Dial interface {
 	// Turn will simulate a dial movement.
 	Turn(delta int32) error
 
+	// TurnWheel will simulate a dial movement that is reported as a wheel movement (REL_WHEEL), for consumers that do
+	// not support REL_DIAL.
+	TurnWheel(delta int32) error
+
+	// Press will simulate a press of the dial. Note that the dial will not be released until Release is invoked.
+	Press() error
+
+	// Release will simulate the release of the dial.
+	Release() error
+
+	// Click will simulate a press and release of the dial.
+	Click() error
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
 	io.Closer
 }
 
 type vDial struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *device
+	onClose    closeHooks
+	mu         deviceMutex
 }
 
 // CreateDial will create a new dial input device. A dial is a device that can trigger rotation events.
-func CreateDial(path string, name []byte) (Dial, error) {
+func CreateDial(path string, name []byte, opts ...DeviceOption) (Dial, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
 	}
-	err = validateUinputName(name)
+	return createVDial(path, name, opts)
+}
+
+// CreateDialFromFile will create a new dial just like CreateDial, but uses the given uinput device file, which has been
+// opened by the caller already. The dial takes ownership of the file (see CreateKeyboardFromFile).
+func CreateDialFromFile(file *os.File, name []byte, opts ...DeviceOption) (Dial, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVDial(file.Name(), name, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVDial(path string, name []byte, opts []DeviceOption) (Dial, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	fd, err := createDial(path, name)
+	fd, err := createDial(path, name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return vDial{name: name, deviceFile: fd}, nil
+	return &vDial{name: name, deviceFile: fd, mu: newDeviceMutex(options)}, nil
+}
+
+// NewDial is the same as CreateDial, but takes the name as a string, which is truncated if it exceeds 80 bytes (see
+// WithNameTruncation).
+func NewDial(path string, name string, opts ...DeviceOption) (Dial, error) {
+	return CreateDial(path, []byte(name), withStringName(opts)...)
 }
 
 // Turn will simulate a dial movement.
-func (vRel vDial) Turn(delta int32) error {
+func (vRel *vDial) Turn(delta int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendDialEvent(vRel.deviceFile, delta)
 }
 
+// TurnWheel will simulate a dial movement, which is reported as a wheel movement by the given number of notches.
+func (vRel *vDial) TurnWheel(delta int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendWheelEvent(vRel.deviceFile, false, delta, 0)
+}
+
+// Press will simulate a press of the dial (reported as BTN_0, just like the Surface Dial does). Note that the dial will
+// not be released until Release is invoked.
+func (vRel *vDial) Press() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendBtnEvent(vRel.deviceFile, []int{evBtn0}, btnStatePressed)
+}
+
+// Release will simulate the release of the dial.
+func (vRel *vDial) Release() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendBtnEvent(vRel.deviceFile, []int{evBtn0}, btnStateReleased)
+}
+
+// Click will simulate a press and release of the dial.
+func (vRel *vDial) Click() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	err := sendBtnEvent(vRel.deviceFile, []int{evBtn0}, btnStatePressed)
+	if err != nil {
+		return fmt.Errorf("failed to issue the Click event: %w", err)
+	}
+
+	return sendBtnEvent(vRel.deviceFile, []int{evBtn0}, btnStateReleased)
+}
+
 // Close closes the device and releases the device.
-func (vRel vDial) Close() error {
-	return closeDevice(vRel.deviceFile)
+func (vRel *vDial) Close() error {
+	err := closeDevice(vRel.deviceFile)
+	vRel.onClose.run()
+	return err
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vRel *vDial) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendRawEvent(vRel.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vRel *vDial) Sync() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return syncEvents(vRel.deviceFile)
 }
 
-func createDial(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+// FetchSyspath will return the syspath to the device file.
+func (vRel *vDial) FetchSyspath() (string, error) {
+	return lookupSyspath(vRel.deviceFile.File, vRel.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vRel *vDial) EventNode() (string, error) {
+	return lookupEventNode(vRel.deviceFile.File, vRel.name)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vRel *vDial) OnClose(callback func()) {
+	vRel.onClose.add(callback)
+}
+
+func createDial(path string, name []byte, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
 	if err != nil {
-		return nil, fmt.Errorf("could not create dial input device: %v", err)
+		return nil, fmt.Errorf("could not create dial input device: %w", err)
 	}
 
 	err = registerDevice(deviceFile, uintptr(evRel))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register dial input device: %v", err)
+		return nil, fmt.Errorf("failed to register dial input device: %w", err)
 	}
 
 	// register dial events
-	err = ioctl(deviceFile, uiSetRelBit, uintptr(relDial))
+	for _, event := range []int{relDial, relWheel} {
+		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register dial events: %w", err)
+		}
+	}
+
+	// register the button of the dial
+	err = registerDevice(deviceFile, uintptr(evKey))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register dial events: %v", err)
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	err = ioctl(deviceFile, uiSetKeyBit, uintptr(evBtn0))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register dial button: %w", err)
 	}
 
 	return createUsbDevice(deviceFile,
 		uinputUserDev{
 			Name: toUinputName(name),
-			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  0x4711,
-				Product: 0x0816,
-				Version: 1}})
+			ID:   options.inputID(0x0816)}, options)
 }
 
-func sendDialEvent(deviceFile *os.File, delta int32) error {
+func sendDialEvent(deviceFile *device, delta int32) error {
 	iev := inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evRel,
 		Code:  relDial,
 		Value: delta}
 
-	buf, err := inputEventToBuffer(iev)
-	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
-	}
-
-	_, err = deviceFile.Write(buf)
+	err := writeEvent(deviceFile, iev)
 	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
+		return fmt.Errorf("failed to write rel event to device file: %w", err)
 	}
 
 	return syncEvents(deviceFile)
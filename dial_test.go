@@ -75,3 +75,60 @@ func TestDialCreationFailsIfNameIsTooLong(t *testing.T) {
 		t.Fatalf("Expected: %s\nActual: %s", expected, err)
 	}
 }
+
+func TestDialOnCloseRunsOnClose(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vDial{deviceFile: &device{File: file}}
+
+	called := false
+	vRel.OnClose(func() { called = true })
+	_ = vRel.Close()
+
+	if !called {
+		t.Fatalf("Expected OnClose callback to be invoked on Close")
+	}
+}
+
+func TestDialEmitsWheelAndButtonEvents(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vDial{deviceFile: &device{File: file}}
+
+	if err := vRel.Turn(-2); err != nil {
+		t.Fatalf("Failed to turn dial: %v", err)
+	}
+	if err := vRel.TurnWheel(3); err != nil {
+		t.Fatalf("Failed to turn dial: %v", err)
+	}
+	if err := vRel.Click(); err != nil {
+		t.Fatalf("Failed to click dial: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evRel, Code: relDial, Value: -2},
+		{Type: evSyn, Code: synReport},
+		{Type: evRel, Code: relWheel, Value: 3},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: evBtn0, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: evBtn0, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestCreateDialFromFileFailsOnNilFile(t *testing.T) {
+	_, err := CreateDialFromFile(nil, []byte("Test Dial"))
+	if err == nil {
+		t.Fatalf("Expected dial creation to fail due to a missing device file, but got no error.")
+	}
+}
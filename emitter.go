@@ -0,0 +1,121 @@
+package uinput
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// A FixedRateEmitter accumulates relative mouse movements and emits them at a fixed rate, just like a real mouse
+// reports its movements at its polling rate (e.g. 1000Hz for gaming mice). Movements that are queued between two
+// ticks are combined into a single movement, which is emitted as a single frame. No event is emitted for ticks without
+// any pending movement.
+type FixedRateEmitter struct {
+	mouse  Mouse
+	ticker *time.Ticker
+	done   chan struct{}
+	wg     sync.WaitGroup
+	stop   sync.Once
+
+	mu     sync.Mutex
+	dx, dy int32
+	err    error
+}
+
+// NewFixedRateEmitter will start emitting the movements queued using Move on the given mouse hz times per second.
+// Call Stop in order to stop emitting events. Note that the mouse will not be closed by the emitter.
+func NewFixedRateEmitter(mouse Mouse, hz int) (*FixedRateEmitter, error) {
+	if mouse == nil {
+		return nil, fmt.Errorf("mouse must not be nil")
+	}
+	if hz <= 0 {
+		return nil, fmt.Errorf("%d is not a valid rate. Expected a positive value", hz)
+	}
+
+	e := &FixedRateEmitter{
+		mouse:  mouse,
+		ticker: time.NewTicker(time.Second / time.Duration(hz)),
+		done:   make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e, nil
+}
+
+// Move queues a relative movement along the x and y axes that will be emitted with the next tick.
+func (e *FixedRateEmitter) Move(x, y int32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.dx += x
+	e.dy += y
+}
+
+// Stop will stop emitting events after flushing any pending movement and return the first error that occurred
+// while emitting events, if any. Calling Stop again has no effect other than returning the error once more.
+func (e *FixedRateEmitter) Stop() error {
+	e.stop.Do(func() {
+		e.ticker.Stop()
+		close(e.done)
+		e.wg.Wait()
+		e.emit()
+	})
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+func (e *FixedRateEmitter) run() {
+	defer e.wg.Done()
+	for {
+		select {
+		case <-e.ticker.C:
+			e.emit()
+		case <-e.done:
+			return
+		}
+	}
+}
+
+func (e *FixedRateEmitter) emit() {
+	e.mu.Lock()
+	dx, dy := e.dx, e.dy
+	e.dx, e.dy = 0, 0
+	e.mu.Unlock()
+
+	if dx == 0 && dy == 0 {
+		return
+	}
+
+	err := moveFrame(e.mouse, dx, dy)
+	if err != nil {
+		e.mu.Lock()
+		if e.err == nil {
+			e.err = fmt.Errorf("failed to emit movement: %w", err)
+		}
+		e.mu.Unlock()
+	}
+}
+
+// A frameMover moves the pointer along both axes within a single frame.
+type frameMover interface {
+	moveFrame(x, y int32) error
+}
+
+// moveFrame moves the pointer of the given mouse along both axes within a single frame. Mice that are not created by
+// this package receive the movement as raw events terminated by a single sync event.
+func moveFrame(mouse Mouse, x, y int32) error {
+	if m, ok := mouse.(frameMover); ok {
+		return m.moveFrame(x, y)
+	}
+	if x != 0 {
+		if err := mouse.SendRawEvent(evRel, relX, x); err != nil {
+			return err
+		}
+	}
+	if y != 0 {
+		if err := mouse.SendRawEvent(evRel, relY, y); err != nil {
+			return err
+		}
+	}
+	return mouse.Sync()
+}
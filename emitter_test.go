@@ -0,0 +1,154 @@
+package uinput
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMouse records all movements that are passed to it.
+type recordingMouse struct {
+	Mouse
+	mu    sync.Mutex
+	moves [][2]int32
+	err   error
+}
+
+func (m *recordingMouse) moveFrame(x, y int32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.moves = append(m.moves, [2]int32{x, y})
+	return m.err
+}
+
+func TestFixedRateEmitterCombinesMovements(t *testing.T) {
+	mouse := &recordingMouse{}
+	e, err := NewFixedRateEmitter(mouse, 100)
+	if err != nil {
+		t.Fatalf("Failed to create emitter: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		e.Move(1, -2)
+	}
+	time.Sleep(50 * time.Millisecond)
+	e.Move(5, 5)
+
+	err = e.Stop()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	var x, y int32
+	for _, move := range mouse.moves {
+		if move[0] == 0 && move[1] == 0 {
+			t.Fatalf("Expected no empty movements to be emitted")
+		}
+		x += move[0]
+		y += move[1]
+	}
+	if x != 15 || y != -15 {
+		t.Fatalf("Expected total movement of (15, -15), but got (%d, %d)", x, y)
+	}
+	if len(mouse.moves) > 2 {
+		t.Fatalf("Expected queued movements to be combined, but got %d movements", len(mouse.moves))
+	}
+}
+
+func TestFixedRateEmitterReportsErrors(t *testing.T) {
+	mouse := &recordingMouse{err: errors.New("device closed")}
+	e, err := NewFixedRateEmitter(mouse, 1000)
+	if err != nil {
+		t.Fatalf("Failed to create emitter: %v", err)
+	}
+
+	e.Move(1, 1)
+	err = e.Stop()
+	if err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+}
+
+func TestFixedRateEmitterStopIsIdempotent(t *testing.T) {
+	mouse := &recordingMouse{}
+	e, err := NewFixedRateEmitter(mouse, 1000)
+	if err != nil {
+		t.Fatalf("Failed to create emitter: %v", err)
+	}
+
+	e.Move(1, 1)
+	err = e.Stop()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+	e.Move(2, 2)
+	err = e.Stop()
+	if err != nil {
+		t.Fatalf("Expected no error on the second stop, but got: %v", err)
+	}
+	if len(mouse.moves) != 1 {
+		t.Fatalf("Expected no movement to be emitted after the first stop, but got %v", mouse.moves)
+	}
+}
+
+func TestFixedRateEmitterEmitsOneFramePerTick(t *testing.T) {
+	fake := NewFake()
+	mouse, err := fake.CreateMouse()
+	if err != nil {
+		t.Fatalf("Failed to create fake mouse: %v", err)
+	}
+	defer mouse.Close()
+	e, err := NewFixedRateEmitter(mouse, 1000)
+	if err != nil {
+		t.Fatalf("Failed to create emitter: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		e.Move(3, -4)
+		time.Sleep(5 * time.Millisecond)
+	}
+	err = e.Stop()
+	if err != nil {
+		t.Fatalf("Expected no error, but got: %v", err)
+	}
+
+	events := fake.Events()
+	if len(events) == 0 || len(events)%3 != 0 {
+		t.Fatalf("Expected frames of three events, but got %+v", events)
+	}
+	for i := 0; i < len(events); i += 3 {
+		frame := events[i : i+3]
+		if frame[0].Code != relX || frame[1].Code != relY || frame[2] != (Event{Type: evSyn, Code: synReport}) {
+			t.Fatalf("Expected a single frame moving along both axes, but got %+v", frame)
+		}
+	}
+}
+
+func TestFixedRateEmitterRejectsInvalidRate(t *testing.T) {
+	_, err := NewFixedRateEmitter(&recordingMouse{}, 0)
+	if err == nil {
+		t.Fatalf("Expected an error due to an invalid rate, but got none")
+	}
+}
+
+func TestFixedRateEmitterWithMouse(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Fixed Rate Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	e, err := NewFixedRateEmitter(relDev, 1000)
+	if err != nil {
+		t.Fatalf("Failed to create emitter: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		e.Move(1, 1)
+		time.Sleep(time.Millisecond)
+	}
+	err = e.Stop()
+	if err != nil {
+		t.Fatalf("Failed to emit movements. Last error was: %s\n", err)
+	}
+}
@@ -0,0 +1,122 @@
+package uinput
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// the sizes of the fields of the timestamp of an input event, which depend on the platform
+const (
+	timevalSecSize  = unsafe.Sizeof(syscall.Timeval{}.Sec)
+	timevalUsecSize = unsafe.Sizeof(syscall.Timeval{}.Usec)
+)
+
+// EventSize is the size of a single encoded input event (struct input_event) in bytes, which depends on the platform
+// (24 bytes on 64-bit platforms and 16 bytes on 32-bit platforms).
+const EventSize = int(timevalSecSize+timevalUsecSize) + 8
+
+// nativeEndian is the byte order of the platform, which is the byte order the kernel expects events to be encoded in.
+var nativeEndian = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// AppendEvent appends a single event of the given type and code to buf, encoded the way the kernel expects it to be
+// written to a device, and returns the extended buffer. The event carries no timestamp, so that the kernel assigns the
+// time it receives the event at. Events encoded into a reused buffer can be queued using EventBatch.Encoded without any
+// allocations.
+func AppendEvent(buf []byte, evType uint16, code uint16, value int32) []byte {
+	return appendInputEvent(buf, inputEvent{Type: evType, Code: code, Value: value})
+}
+
+// AppendEventAt appends a single event just like AppendEvent, carrying the given timestamp of CLOCK_MONOTONIC (see
+// EventBatch.EventAt).
+func AppendEventAt(buf []byte, t time.Duration, evType uint16, code uint16, value int32) []byte {
+	iev := inputEvent{Time: syscall.NsecToTimeval(t.Nanoseconds()), Type: evType, Code: code, Value: value}
+	return appendInputEvent(buf, iev)
+}
+
+// appendInputEvent appends the encoded event to buf, which only allocates if the capacity of buf is exceeded.
+func appendInputEvent(buf []byte, iev inputEvent) []byte {
+	n := len(buf)
+	if cap(buf)-n < EventSize {
+		grown := make([]byte, n, 2*cap(buf)+EventSize)
+		copy(grown, buf)
+		buf = grown
+	}
+	buf = buf[:n+EventSize]
+	encodeInputEvent(buf[n:], iev)
+	return buf
+}
+
+// encodeInputEvent encodes the event into b, which must hold at least EventSize bytes.
+func encodeInputEvent(b []byte, iev inputEvent) {
+	putInt(b, timevalSecSize, int64(iev.Time.Sec))
+	b = b[timevalSecSize:]
+	putInt(b, timevalUsecSize, int64(iev.Time.Usec))
+	b = b[timevalUsecSize:]
+	nativeEndian.PutUint16(b, iev.Type)
+	nativeEndian.PutUint16(b[2:], iev.Code)
+	nativeEndian.PutUint32(b[4:], uint32(iev.Value))
+}
+
+// decodeInputEvent decodes a single event from b.
+func decodeInputEvent(b []byte) (iev inputEvent, err error) {
+	if len(b) < EventSize {
+		return inputEvent{}, fmt.Errorf("failed to decode input event. Expected %d bytes, but got %d", EventSize, len(b))
+	}
+	setInt(unsafe.Pointer(&iev.Time.Sec), timevalSecSize, getInt(b, timevalSecSize))
+	b = b[timevalSecSize:]
+	setInt(unsafe.Pointer(&iev.Time.Usec), timevalUsecSize, getInt(b, timevalUsecSize))
+	b = b[timevalUsecSize:]
+	iev.Type = nativeEndian.Uint16(b)
+	iev.Code = nativeEndian.Uint16(b[2:])
+	iev.Value = int32(nativeEndian.Uint32(b[4:]))
+	return iev, nil
+}
+
+// putInt encodes v as an integer of the given size (4 or 8 bytes).
+func putInt(b []byte, size uintptr, v int64) {
+	if size == 8 {
+		nativeEndian.PutUint64(b, uint64(v))
+	} else {
+		nativeEndian.PutUint32(b, uint32(v))
+	}
+}
+
+// getInt decodes an integer of the given size (4 or 8 bytes).
+func getInt(b []byte, size uintptr) int64 {
+	if size == 8 {
+		return int64(nativeEndian.Uint64(b))
+	}
+	return int64(int32(nativeEndian.Uint32(b)))
+}
+
+// setInt stores v in the integer of the given size (4 or 8 bytes) p points to, which allows to set the fields of a
+// timestamp regardless of their type on the platform.
+func setInt(p unsafe.Pointer, size uintptr, v int64) {
+	if size == 8 {
+		*(*int64)(p) = v
+	} else {
+		*(*int32)(p) = int32(v)
+	}
+}
+
+// eventBuffers holds buffers for single events, which saves allocating a buffer for every event that is written.
+var eventBuffers = sync.Pool{New: func() interface{} { return new([EventSize]byte) }}
+
+// writeEvent encodes the event into a pooled buffer and writes it to the device file (see writeEvents).
+func writeEvent(deviceFile *device, iev inputEvent) error {
+	buf := eventBuffers.Get().(*[EventSize]byte)
+	defer eventBuffers.Put(buf)
+	encodeInputEvent(buf[:], iev)
+	_, err := writeEvents(deviceFile, buf[:])
+	return err
+}
@@ -0,0 +1,83 @@
+package uinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestEventEncodingMatchesKernelLayout(t *testing.T) {
+	iev := inputEvent{Time: syscall.NsecToTimeval(int64(90*time.Second + 1500*time.Microsecond)), Type: evAbs,
+		Code: absY, Value: -42}
+	var expected bytes.Buffer
+	err := binary.Write(&expected, nativeEndian, iev)
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to encode event: %v", err)
+	}
+	if binary.Size(iev) != EventSize {
+		t.Fatalf("Expected an event size of %d bytes, but got %d", binary.Size(iev), EventSize)
+	}
+
+	buf := AppendEventAt(nil, 90*time.Second+1500*time.Microsecond, evAbs, absY, -42)
+	if !bytes.Equal(buf, expected.Bytes()) {
+		t.Fatalf("Expected the event to be encoded as %v, but got %v", expected.Bytes(), buf)
+	}
+	decoded, err := decodeInputEvent(buf)
+	if err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if decoded != iev {
+		t.Fatalf("Expected the decoded event to be %+v, but got %+v", iev, decoded)
+	}
+}
+
+func TestDecodeEventFailsOnShortBuffer(t *testing.T) {
+	_, err := decodeInputEvent(make([]byte, EventSize-1))
+	if err == nil {
+		t.Fatalf("Expected decoding to fail due to a short buffer, but got no error.")
+	}
+}
+
+func TestAppendEventDoesNotAllocate(t *testing.T) {
+	buf := make([]byte, 0, EventSize)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendEvent(buf[:0], evKey, KeyA, btnStatePressed)
+	})
+	if allocs != 0 {
+		t.Fatalf("Expected appending to a buffer of sufficient capacity not to allocate, but got %v allocations", allocs)
+	}
+}
+
+func TestBatchQueuesEncodedEvents(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	frame := AppendEvent(nil, evKey, KeyA, btnStatePressed)
+	frame = AppendEvent(frame, evSyn, synReport, 0)
+	b := newEventBatch(&device{File: file}).Encoded(frame).Encoded(frame)
+	if b.Len() != 4 {
+		t.Fatalf("Expected 4 queued events, but got %d", b.Len())
+	}
+	err := b.Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+	if events := readTestEvents(t, file); len(events) != 4 {
+		t.Fatalf("Expected the frame to be sent twice without an additional sync, but got %+v", events)
+	}
+
+	err = newEventBatch(&device{File: file}).Encoded(frame[:EventSize-1]).Flush()
+	if err == nil {
+		t.Fatalf("Expected flushing to fail due to a partially encoded event, but got no error.")
+	}
+}
+
+func BenchmarkAppendEvent(b *testing.B) {
+	buf := make([]byte, 0, EventSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = AppendEvent(buf[:0], evAbs, absX, int32(i))
+	}
+}
@@ -0,0 +1,104 @@
+package uinput
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// A CapturedEvent is an event read from an input device, along with the time the kernel reported it at.
+type CapturedEvent struct {
+	Time time.Time
+	Event
+}
+
+// An EventDevice is an existing input device (like a physical keyboard), whose events are read from its evdev node.
+// Together with the virtual devices of this package, this allows to capture the events of a physical device and replay
+// them on a virtual one.
+type EventDevice struct {
+	file    *os.File
+	grabbed bool
+}
+
+// OpenEventDevice will open the evdev node of an existing input device for reading its events. The path is the evdev
+// node of the device (e.g. /dev/input/event3, see EventNode), which requires read permissions.
+func OpenEventDevice(path string) (*EventDevice, error) {
+	err := checkPlatform()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, fmt.Errorf("event node path must not be empty")
+	}
+	eventFile, err := os.OpenFile(path, syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event node: %w", err)
+	}
+	return &EventDevice{file: eventFile}, nil
+}
+
+// Name will return the name the device reports (see EVIOCGNAME).
+func (d *EventDevice) Name() (string, error) {
+	name := make([]byte, 256)
+	err := ioctl(d.file, evIOCGName|uintptr(len(name))<<16, uintptr(unsafe.Pointer(&name[0])))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch device name: %w", err)
+	}
+	return string(bytes.TrimRight(name, "\x00")), nil
+}
+
+// ReadEvent will wait for at most the given timeout for the next event of the device. If no event arrives in time, ok
+// will be false. Sync events are returned as well, so that consumers can tell the frames of the device apart.
+func (d *EventDevice) ReadEvent(timeout time.Duration) (ev CapturedEvent, ok bool, err error) {
+	iev, ok, err := readEvent(d.file, timeout)
+	if err != nil || !ok {
+		return CapturedEvent{}, false, err
+	}
+	return CapturedEvent{
+		Time:  time.Unix(int64(iev.Time.Sec), int64(iev.Time.Usec)*int64(time.Microsecond)),
+		Event: Event{Type: iev.Type, Code: iev.Code, Value: iev.Value},
+	}, true, nil
+}
+
+// Record will capture the events of the device for the given duration, using the timestamps the kernel assigned to the
+// events. The resulting macro can be played back on any virtual device.
+func (d *EventDevice) Record(duration time.Duration) (*Macro, error) {
+	return recordEvents(d.file, duration)
+}
+
+// Grab will exclusively grab the device (see EVIOCGRAB), so that its events are no longer delivered to any other
+// consumer (like X11 or Wayland compositors) until Ungrab or Close is invoked.
+func (d *EventDevice) Grab() error {
+	err := ioctl(d.file, evIOCGrab, uintptr(1))
+	if err != nil {
+		return fmt.Errorf("failed to grab event node: %w", err)
+	}
+	d.grabbed = true
+	return nil
+}
+
+// Ungrab will release the grab of the device obtained by Grab.
+func (d *EventDevice) Ungrab() error {
+	if !d.grabbed {
+		return nil
+	}
+	err := ioctl(d.file, evIOCGrab, uintptr(0))
+	if err != nil {
+		return fmt.Errorf("failed to release grab of event node: %w", err)
+	}
+	d.grabbed = false
+	return nil
+}
+
+// Close will release the grab of the device, if any, and close its event node.
+func (d *EventDevice) Close() error {
+	err := d.Ungrab()
+	if err != nil {
+		_ = d.file.Close()
+		return err
+	}
+	return d.file.Close()
+}
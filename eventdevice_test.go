@@ -0,0 +1,79 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestEventDeviceReadsEventsWithKernelTimestamps(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer w.Close()
+	dev := &EventDevice{file: r}
+	defer dev.Close()
+
+	for _, iev := range []inputEvent{
+		{Time: syscall.Timeval{Sec: 10, Usec: 500}, Type: evKey, Code: KeyB, Value: btnStatePressed},
+		{Time: syscall.Timeval{Sec: 10, Usec: 500}, Type: evSyn, Code: synReport},
+	} {
+		buf, err := inputEventToBuffer(iev)
+		if err != nil {
+			t.Fatalf("Failed to encode event: %v", err)
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+
+	ev, ok, err := dev.ReadEvent(50 * time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("Expected an event to be read, but got %v (ok %v)", err, ok)
+	}
+	if ev.Event != (Event{Type: evKey, Code: KeyB, Value: btnStatePressed}) {
+		t.Fatalf("Expected a press of KeyB, but got %+v", ev.Event)
+	}
+	if !ev.Time.Equal(time.Unix(10, 500000)) {
+		t.Fatalf("Expected the kernel timestamp to be reported, but got %v", ev.Time)
+	}
+
+	ev, ok, err = dev.ReadEvent(50 * time.Millisecond)
+	if err != nil || !ok || ev.Type != evSyn {
+		t.Fatalf("Expected a sync event to be read, but got %+v (ok %v, error %v)", ev, ok, err)
+	}
+}
+
+func TestEventDeviceReadTimesOut(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer w.Close()
+	dev := &EventDevice{file: r}
+	defer dev.Close()
+
+	_, ok, err := dev.ReadEvent(10 * time.Millisecond)
+	if err != nil || ok {
+		t.Fatalf("Expected the read to time out without an event, but got ok %v and error %v", ok, err)
+	}
+}
+
+func TestOpenEventDeviceFailsOnEmptyPath(t *testing.T) {
+	expected := "event node path must not be empty"
+	_, err := OpenEventDevice("")
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestOpenEventDeviceFailsOnMissingNode(t *testing.T) {
+	_, err := OpenEventDevice("/dev/input/does-not-exist")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected a missing event node to be reported, but got %v", err)
+	}
+}
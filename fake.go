@@ -0,0 +1,197 @@
+package uinput
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// A Fake creates devices that record the events they emit in memory instead of passing them to the kernel. This allows
+// applications using this package to test their input logic without access to /dev/uinput (e.g. in CI, or on other
+// platforms than linux), by inspecting the recorded events afterwards. Fake devices behave just like real devices,
+// except that they are not visible to the system, so anything that requires the kernel (like FetchSyspath, Grab, LED
+// state or force feedback) fails or has no effect. All devices created by the same Fake record their events in order
+// into a single list.
+type Fake struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewFake will create a new Fake, which has not recorded any events yet.
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Events returns all events recorded so far, including the sync events terminating them.
+func (f *Fake) Events() []Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Event(nil), f.events...)
+}
+
+// Reset discards all events recorded so far.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = nil
+}
+
+// record appends the events in the given buffer, holding one or more encoded events, to the recorded events.
+func (f *Fake) record(buf []byte) (int, error) {
+	size := EventSize
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 0; i+size <= len(buf); i += size {
+		iev, err := bufferToInputEvent(buf[i : i+size])
+		if err != nil {
+			return i, err
+		}
+		f.events = append(f.events, Event{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+	}
+	return len(buf), nil
+}
+
+// CreateKeyboard will create a fake keyboard (see CreateKeyboard).
+func (f *Fake) CreateKeyboard(opts ...DeviceOption) (Keyboard, error) {
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	vk := &vKeyboard{name: []byte("fake keyboard"), deviceFile: fd, options: options, pressed: make(map[int]bool), mu: newDeviceMutex(options)}
+	if options.keyRepeat {
+		err := vk.SetRepeat(int(options.repeatDelay.Milliseconds()), int(options.repeatPeriod.Milliseconds()))
+		if err != nil {
+			_ = closeDevice(fd)
+			return nil, err
+		}
+	}
+	return vk, nil
+}
+
+// CreateMouse will create a fake mouse (see CreateMouse).
+func (f *Fake) CreateMouse(opts ...DeviceOption) (Mouse, error) {
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vMouse{name: []byte("fake mouse"), deviceFile: fd, easing: options.easing, drag: options.drag, mu: newDeviceMutex(options)}, nil
+}
+
+// CreateTouchPad will create a fake touch pad (see CreateTouchPad).
+func (f *Fake) CreateTouchPad(minX int32, maxX int32, minY int32, maxY int32, opts ...DeviceOption) (TouchPad, error) {
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vTouchPad{name: []byte("fake touch pad"), deviceFile: fd, easing: options.easing, drag: options.drag, mu: newDeviceMutex(options)}, nil
+}
+
+// CreateGamepad will create a fake gamepad (see CreateGamepad). Force feedback is not supported.
+func (f *Fake) CreateGamepad(opts ...DeviceOption) (Gamepad, error) {
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vGamepad{name: []byte("fake gamepad"), deviceFile: fd, buttons: make(map[int]bool), axes: make(map[uint16]int32), mu: newDeviceMutex(options)}, nil
+}
+
+// CreateDial will create a fake dial (see CreateDial).
+func (f *Fake) CreateDial(opts ...DeviceOption) (Dial, error) {
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vDial{name: []byte("fake dial"), deviceFile: fd, mu: newDeviceMutex(options)}, nil
+}
+
+// CreateTouchRing will create a fake touch ring (see CreateTouchRing).
+func (f *Fake) CreateTouchRing(min int32, max int32, opts ...DeviceOption) (TouchRing, error) {
+	if min >= max {
+		return nil, fmt.Errorf("invalid ring range. Minimum %d must be less than maximum %d", min, max)
+	}
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vTouchRing{name: []byte("fake touch ring"), deviceFile: fd, min: min, max: max, mu: newDeviceMutex(options)}, nil
+}
+
+// CreateTouchScreen will create a fake touch screen (see CreateTouchScreen).
+func (f *Fake) CreateTouchScreen(minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (TouchScreen, error) {
+	if minX >= maxX || minY >= maxY {
+		return nil, fmt.Errorf("invalid screen area. Minimum values must be less than maximum values")
+	}
+	if slots <= 0 {
+		return nil, fmt.Errorf("%d is not a valid number of slots. Expected a positive value", slots)
+	}
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vTouchScreen{name: []byte("fake touch screen"), deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, slots: slots,
+		active: make(map[int]bool), mu: newDeviceMutex(options)}, nil
+}
+
+// CreateClickPad will create a fake click pad (see CreateClickPad).
+func (f *Fake) CreateClickPad(minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (ClickPad, error) {
+	if minX >= maxX || minY >= maxY {
+		return nil, fmt.Errorf("invalid pad area. Minimum values must be less than maximum values")
+	}
+	if slots <= 0 {
+		return nil, fmt.Errorf("%d is not a valid number of slots. Expected a positive value", slots)
+	}
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	vs := &vTouchScreen{name: []byte("fake click pad"), deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, slots: slots,
+		active: make(map[int]bool), countContacts: true, mu: newDeviceMutex(options)}
+	return &vClickPad{vs: vs, tools: make(map[int]ContactTool)}, nil
+}
+
+// CreatePen will create a fake pen (see CreatePen).
+func (f *Fake) CreatePen(minX int32, maxX int32, minY int32, maxY int32, maxPressure int32, opts ...DeviceOption) (Pen, error) {
+	if minX >= maxX || minY >= maxY {
+		return nil, fmt.Errorf("invalid tablet area. Minimum values must be less than maximum values")
+	}
+	if maxPressure <= 0 {
+		return nil, fmt.Errorf("%d is not a valid maximum pressure. Expected a positive value", maxPressure)
+	}
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vPen{name: []byte("fake pen"), deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, maxPressure: maxPressure,
+		mu: newDeviceMutex(options)}, nil
+}
+
+// CreateSwitchDevice will create a fake switch device (see CreateSwitchDevice).
+func (f *Fake) CreateSwitchDevice(switches []int, opts ...DeviceOption) (SwitchDevice, error) {
+	registered := make(map[int]bool)
+	for _, code := range switches {
+		registered[code] = true
+	}
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vSwitchDevice{name: []byte("fake switch device"), deviceFile: fd, switches: registered, mu: newDeviceMutex(options)}, nil
+}
+
+// CreateCustomDevice will create a fake device with arbitrary capabilities (see CreateFromCapabilities). Since the
+// kernel is not involved, events of any type may be sent to the device.
+func (f *Fake) CreateCustomDevice(opts ...DeviceOption) (CustomDevice, error) {
+	options := newDeviceOptions(opts)
+	fd := f.newDevice(options)
+	return &vCustomDevice{name: []byte("fake custom device"), deviceFile: fd, mu: newDeviceMutex(options)}, nil
+}
+
+// newDevice returns the device of a fake device, whose events are recorded by the Fake.
+func (f *Fake) newDevice(options deviceOptions) *device {
+	fd := newDevice(nil, options)
+	fd.fake = &fakeFile{fake: f}
+	return fd
+}
+
+// A fakeFile replaces the device file of a fake device. Events written to it are recorded by its Fake, until it is
+// closed.
+type fakeFile struct {
+	fake *Fake
+	// closed is set once the file is closed. It is accessed atomically.
+	closed int32
+}
+
+func (f *fakeFile) Write(buf []byte) (int, error) {
+	if atomic.LoadInt32(&f.closed) != 0 {
+		return 0, os.ErrClosed
+	}
+	return f.fake.record(buf)
+}
+
+func (f *fakeFile) Close() error {
+	if !atomic.CompareAndSwapInt32(&f.closed, 0, 1) {
+		return os.ErrClosed
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package uinput
+
+import (
+	"testing"
+)
+
+func TestFakeRecordsEventsOfAllDevicesInOrder(t *testing.T) {
+	fake := NewFake()
+
+	keyboard, err := fake.CreateKeyboard()
+	if err != nil {
+		t.Fatalf("Failed to create fake keyboard: %v", err)
+	}
+	defer keyboard.Close()
+	mouse, err := fake.CreateMouse()
+	if err != nil {
+		t.Fatalf("Failed to create fake mouse: %v", err)
+	}
+	defer mouse.Close()
+
+	if err := keyboard.KeyDown(KeyA); err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+	if err := mouse.Move(5, -3); err != nil {
+		t.Fatalf("Failed to move mouse: %v", err)
+	}
+	if err := keyboard.KeyUp(KeyA); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+
+	events := fake.Events()
+	expected := []Event{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evRel, Code: relX, Value: 5},
+		{Type: evSyn, Code: synReport},
+		{Type: evRel, Code: relY, Value: -3},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestFakeResetDiscardsEvents(t *testing.T) {
+	fake := NewFake()
+	dial, err := fake.CreateDial()
+	if err != nil {
+		t.Fatalf("Failed to create fake dial: %v", err)
+	}
+	defer dial.Close()
+
+	if err := dial.Turn(1); err != nil {
+		t.Fatalf("Failed to turn dial: %v", err)
+	}
+	fake.Reset()
+	if err := dial.Turn(-1); err != nil {
+		t.Fatalf("Failed to turn dial: %v", err)
+	}
+
+	events := fake.Events()
+	if len(events) != 2 || events[0] != (Event{Type: evRel, Code: relDial, Value: -1}) {
+		t.Fatalf("Expected only the events after the reset, but got %+v", events)
+	}
+}
+
+func TestFakeDevicesCloseWithoutKernel(t *testing.T) {
+	fake := NewFake()
+	screen, err := fake.CreateTouchScreen(0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create fake touch screen: %v", err)
+	}
+
+	closed := false
+	screen.OnClose(func() { closed = true })
+	if err := screen.Close(); err != nil {
+		t.Fatalf("Failed to close fake device: %v", err)
+	}
+	if !closed {
+		t.Fatalf("Expected close hooks to run for fake devices")
+	}
+	if err := screen.TouchDown(0, 10, 10); err == nil {
+		t.Fatalf("Expected writing to a closed fake device to fail")
+	}
+}
+
+func TestFakeValidatesParameters(t *testing.T) {
+	fake := NewFake()
+
+	if _, err := fake.CreateTouchRing(10, 0); err == nil {
+		t.Fatalf("Expected creation of a fake touch ring with an invalid range to fail")
+	}
+	if _, err := fake.CreatePen(0, 1024, 0, 768, 0); err == nil {
+		t.Fatalf("Expected creation of a fake pen with an invalid maximum pressure to fail")
+	}
+	if _, err := fake.CreateClickPad(0, 1024, 0, 768, 0); err == nil {
+		t.Fatalf("Expected creation of a fake click pad without slots to fail")
+	}
+}
+
+func TestFakeClickPadRecordsClicks(t *testing.T) {
+	fake := NewFake()
+	pad, err := fake.CreateClickPad(0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create fake click pad: %v", err)
+	}
+	defer pad.Close()
+
+	if err := pad.TouchDown(0, 100, 100); err != nil {
+		t.Fatalf("Failed to put down finger: %v", err)
+	}
+	if err := pad.Click(); err != nil {
+		t.Fatalf("Failed to click pad: %v", err)
+	}
+	if err := pad.TouchUp(0); err != nil {
+		t.Fatalf("Failed to lift finger: %v", err)
+	}
+
+	events := fake.Events()
+	var clicks, fingers int
+	for _, ev := range events {
+		if ev.Type == evKey && ev.Code == evMouseBtnLeft {
+			clicks++
+		}
+		if ev.Type == evKey && ev.Code == evBtnToolFinger {
+			fingers++
+		}
+	}
+	if clicks != 2 || fingers != 2 {
+		t.Fatalf("Expected a click of a single finger to be recorded, but got %+v", events)
+	}
+}
@@ -0,0 +1,212 @@
+package uinput
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// FFRumble is the force feedback effect type of a rumble effect (see FF_RUMBLE in input.h).
+const FFRumble = 0x50
+
+// the codes of the EV_FF events that change a setting of the device instead of playing an effect (see input.h)
+const (
+	ffGain       = 0x60
+	ffAutocenter = 0x61
+)
+
+// FFEventType specifies the kind of request an application made to the force feedback device.
+type FFEventType int
+
+const (
+	// FFUpload is sent if an application uploads a new effect or updates an existing one.
+	FFUpload FFEventType = iota + 1
+	// FFErase is sent if an application erases an effect that was uploaded before.
+	FFErase
+	// FFPlay is sent if an application starts playing an effect.
+	FFPlay
+	// FFStop is sent if an application stops playing an effect.
+	FFStop
+	// FFGain is sent if an application sets the overall strength of all effects (from 0 to 0xffff).
+	FFGain
+	// FFAutocenter is sent if an application sets the strength of the autocenter feature (from 0 to 0xffff).
+	FFAutocenter
+)
+
+// An FFEvent is a force feedback request made by an application. Effect is only set for FFUpload, whereas Count holds
+// the number of times the effect should be played for FFPlay. Value holds the new setting for FFGain and FFAutocenter,
+// which do not refer to an effect.
+type FFEvent struct {
+	Type     FFEventType
+	EffectID int16
+	Effect   FFEffect
+	Count    int32
+	Value    int32
+}
+
+// An FFEffect is a force feedback effect uploaded by an application. The magnitudes are only set for rumble effects
+// (Type is FFRumble), which is the only type of effect the gamepad supports.
+type FFEffect struct {
+	Type      uint16
+	ID        int16
+	Direction uint16
+
+	ReplayLength time.Duration
+	ReplayDelay  time.Duration
+
+	StrongMagnitude uint16
+	WeakMagnitude   uint16
+}
+
+// An FFHandler is invoked for each force feedback request made by an application (see WithForceFeedback). Note that
+// the handler is invoked from a separate goroutine and must not block for long, since the application waits for the
+// request to be handled.
+type FFHandler func(ev FFEvent)
+
+// ffEffectsMax is the number of effects an application may upload at the same time.
+const ffEffectsMax = 16
+
+// ffPollInterval is the time the force feedback loop waits for requests before checking whether it should stop.
+const ffPollInterval = 100 * time.Millisecond
+
+// translated to go from input.h. The union holding the effect specific parameters is at most as large as the periodic
+// effect, which ends with a pointer to custom data.
+type ffEffect struct {
+	Type            uint16
+	ID              int16
+	Direction       uint16
+	TriggerButton   uint16
+	TriggerInterval uint16
+	ReplayLength    uint16
+	ReplayDelay     uint16
+	_               uint16
+	Params          [24 + unsafe.Sizeof(uintptr(0))]byte
+}
+
+// translated to go from uinput.h
+type uinputFFUpload struct {
+	RequestID uint32
+	Retval    int32
+	Effect    ffEffect
+	Old       ffEffect
+}
+
+// translated to go from uinput.h
+type uinputFFErase struct {
+	RequestID uint32
+	Retval    int32
+	EffectID  uint32
+}
+
+func (e ffEffect) toFFEffect() FFEffect {
+	effect := FFEffect{
+		Type:         e.Type,
+		ID:           e.ID,
+		Direction:    e.Direction,
+		ReplayLength: time.Duration(e.ReplayLength) * time.Millisecond,
+		ReplayDelay:  time.Duration(e.ReplayDelay) * time.Millisecond,
+	}
+	if e.Type == FFRumble {
+		effect.StrongMagnitude = uint16(e.Params[0]) | uint16(e.Params[1])<<8
+		effect.WeakMagnitude = uint16(e.Params[2]) | uint16(e.Params[3])<<8
+	}
+	return effect
+}
+
+// ffLoop handles the force feedback requests sent to a device, until it is stopped.
+type ffLoop struct {
+	deviceFile *device
+	handler    FFHandler
+	done       chan struct{}
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+}
+
+func startFFLoop(deviceFile *device, handler FFHandler) *ffLoop {
+	l := &ffLoop{deviceFile: deviceFile, handler: handler, done: make(chan struct{})}
+	l.wg.Add(1)
+	go l.run()
+	return l
+}
+
+// stop stops handling requests and waits for the loop to finish. Calling stop again has no effect.
+func (l *ffLoop) stop() {
+	l.stopOnce.Do(func() {
+		close(l.done)
+		l.wg.Wait()
+	})
+}
+
+func (l *ffLoop) run() {
+	defer l.wg.Done()
+	for {
+		select {
+		case <-l.done:
+			return
+		default:
+		}
+
+		ev, ok, err := readEvent(l.deviceFile.File, ffPollInterval)
+		if err != nil {
+			return
+		}
+		if ok {
+			l.handle(ev)
+		}
+	}
+}
+
+func (l *ffLoop) handle(ev inputEvent) {
+	switch {
+	case ev.Type == evUinput && ev.Code == uiFFUpload:
+		effect, err := handleFFUpload(l.deviceFile.File, uint32(ev.Value))
+		if err == nil {
+			l.handler(FFEvent{Type: FFUpload, EffectID: effect.ID, Effect: effect})
+		}
+	case ev.Type == evUinput && ev.Code == uiFFErase:
+		id, err := handleFFErase(l.deviceFile.File, uint32(ev.Value))
+		if err == nil {
+			l.handler(FFEvent{Type: FFErase, EffectID: id})
+		}
+	case ev.Type == evFf && ev.Code == ffGain:
+		l.handler(FFEvent{Type: FFGain, Value: ev.Value})
+	case ev.Type == evFf && ev.Code == ffAutocenter:
+		l.handler(FFEvent{Type: FFAutocenter, Value: ev.Value})
+	case ev.Type == evFf && ev.Value > 0:
+		l.handler(FFEvent{Type: FFPlay, EffectID: int16(ev.Code), Count: ev.Value})
+	case ev.Type == evFf:
+		l.handler(FFEvent{Type: FFStop, EffectID: int16(ev.Code)})
+	}
+}
+
+// handleFFUpload fetches the uploaded effect and acknowledges the upload, which unblocks the uploading application.
+func handleFFUpload(deviceFile *os.File, requestID uint32) (FFEffect, error) {
+	upload := uinputFFUpload{RequestID: requestID}
+	err := ioctl(deviceFile, uiBeginFFUpload, uintptr(unsafe.Pointer(&upload)))
+	if err != nil {
+		return FFEffect{}, fmt.Errorf("failed to begin effect upload: %w", err)
+	}
+	upload.Retval = 0
+	err = ioctl(deviceFile, uiEndFFUpload, uintptr(unsafe.Pointer(&upload)))
+	if err != nil {
+		return FFEffect{}, fmt.Errorf("failed to end effect upload: %w", err)
+	}
+	return upload.Effect.toFFEffect(), nil
+}
+
+// handleFFErase fetches the id of the erased effect and acknowledges the request.
+func handleFFErase(deviceFile *os.File, requestID uint32) (int16, error) {
+	erase := uinputFFErase{RequestID: requestID}
+	err := ioctl(deviceFile, uiBeginFFErase, uintptr(unsafe.Pointer(&erase)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin effect erase: %w", err)
+	}
+	erase.Retval = 0
+	err = ioctl(deviceFile, uiEndFFErase, uintptr(unsafe.Pointer(&erase)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to end effect erase: %w", err)
+	}
+	return int16(erase.EffectID), nil
+}
@@ -0,0 +1,133 @@
+package uinput
+
+import (
+	"os"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestFFStructsMatchKernelLayout(t *testing.T) {
+	if unsafe.Sizeof(uintptr(0)) != 8 {
+		t.Skip("layout is only verified for 64 bit platforms")
+	}
+	if size := unsafe.Sizeof(ffEffect{}); size != 48 {
+		t.Fatalf("Expected ff_effect to be 48 bytes, but got %d\n", size)
+	}
+	if size := unsafe.Sizeof(uinputFFUpload{}); size != 104 {
+		t.Fatalf("Expected uinput_ff_upload to be 104 bytes, but got %d\n", size)
+	}
+	if uiBeginFFUpload != 0xc06855c8 || uiEndFFUpload != 0x406855c9 {
+		t.Fatalf("Unexpected upload ioctls: %#x, %#x\n", uiBeginFFUpload, uiEndFFUpload)
+	}
+}
+
+func TestRumbleEffectIsDecoded(t *testing.T) {
+	e := ffEffect{Type: FFRumble, ID: 3, ReplayLength: 500, ReplayDelay: 20}
+	e.Params[0], e.Params[1] = 0x34, 0x12
+	e.Params[2], e.Params[3] = 0xff, 0x00
+
+	effect := e.toFFEffect()
+	if effect.ID != 3 || effect.ReplayLength != 500*time.Millisecond || effect.ReplayDelay != 20*time.Millisecond {
+		t.Fatalf("Unexpected effect: %+v\n", effect)
+	}
+	if effect.StrongMagnitude != 0x1234 || effect.WeakMagnitude != 0xff {
+		t.Fatalf("Unexpected magnitudes: %#x, %#x\n", effect.StrongMagnitude, effect.WeakMagnitude)
+	}
+}
+
+func TestFFLoopReportsPlayAndStop(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe. Last error was: %s\n", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	events := make(chan FFEvent, 2)
+	l := startFFLoop(&device{File: r}, func(ev FFEvent) { events <- ev })
+	defer l.stop()
+
+	for _, iev := range []inputEvent{{Type: evFf, Code: 2, Value: 3}, {Type: evFf, Code: 2, Value: 0}} {
+		buf, err := inputEventToBuffer(iev)
+		if err != nil {
+			t.Fatalf("Failed to encode event. Last error was: %s\n", err)
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			t.Fatalf("Failed to write event. Last error was: %s\n", err)
+		}
+	}
+
+	for _, expected := range []FFEvent{{Type: FFPlay, EffectID: 2, Count: 3}, {Type: FFStop, EffectID: 2}} {
+		select {
+		case ev := <-events:
+			if ev != expected {
+				t.Fatalf("Expected %+v, but got %+v\n", expected, ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %+v\n", expected)
+		}
+	}
+}
+
+func TestFFLoopReportsSettings(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe. Last error was: %s\n", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	events := make(chan FFEvent, 2)
+	l := startFFLoop(&device{File: r}, func(ev FFEvent) { events <- ev })
+	defer l.stop()
+
+	for _, iev := range []inputEvent{{Type: evFf, Code: ffGain, Value: 0x8000}, {Type: evFf, Code: ffAutocenter, Value: 0}} {
+		buf, err := inputEventToBuffer(iev)
+		if err != nil {
+			t.Fatalf("Failed to encode event. Last error was: %s\n", err)
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			t.Fatalf("Failed to write event. Last error was: %s\n", err)
+		}
+	}
+
+	for _, expected := range []FFEvent{{Type: FFGain, Value: 0x8000}, {Type: FFAutocenter}} {
+		select {
+		case ev := <-events:
+			if ev != expected {
+				t.Fatalf("Expected %+v, but got %+v\n", expected, ev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for %+v\n", expected)
+		}
+	}
+}
+
+func TestGamepadWithForceFeedbackCanBeClosedTwice(t *testing.T) {
+	fd := NewFake().newDevice(newDeviceOptions(nil))
+	vg := &vGamepad{deviceFile: fd, ff: startFFLoop(fd, func(ev FFEvent) {}), buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+
+	err := vg.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+	err = vg.Close()
+	if err == nil {
+		t.Fatalf("Expected closing the device again to fail")
+	}
+}
+
+func TestGamepadWithForceFeedback(t *testing.T) {
+	vg, err := CreateGamepad("/dev/uinput", []byte("Rumbling gopher"), 0xDEAD, 0xBEEF,
+		WithForceFeedback(func(ev FFEvent) {}))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	err = vg.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+}
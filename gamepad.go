@@ -21,11 +21,51 @@ const (
 
 type HatAction int
 
+// DPadDirection specifies the direction of the d-pad, including the diagonals
+type DPadDirection int
+
+const (
+	DPadCentered DPadDirection = iota
+	DPadUp
+	DPadUpRight
+	DPadRight
+	DPadDownRight
+	DPadDown
+	DPadDownLeft
+	DPadLeft
+	DPadUpLeft
+)
+
+// dPadHatValues maps the directions of the d-pad to the values of the x and y hat axes.
+var dPadHatValues = map[DPadDirection][2]int32{
+	DPadCentered:  {0, 0},
+	DPadUp:        {0, -1},
+	DPadUpRight:   {1, -1},
+	DPadRight:     {1, 0},
+	DPadDownRight: {1, 1},
+	DPadDown:      {0, 1},
+	DPadDownLeft:  {-1, 1},
+	DPadLeft:      {-1, 0},
+	DPadUpLeft:    {-1, -1},
+}
+
 const (
 	Press HatAction = iota + 1
 	Release
 )
 
+// The axes of the gamepad, as reported by the device. These may be passed to CenterAxis.
+const (
+	AxisLeftStickX   uint16 = absX
+	AxisLeftStickY   uint16 = absY
+	AxisRightStickX  uint16 = absRX
+	AxisRightStickY  uint16 = absRY
+	AxisLeftTrigger  uint16 = absZ
+	AxisRightTrigger uint16 = absRZ
+	AxisHatX         uint16 = absHat0X
+	AxisHatY         uint16 = absHat0Y
+)
+
 // Gamepad is a hybrid key / absolute change event output device.
 // It used to enable a program to simulate gamepad input events.
 type Gamepad interface {
@@ -61,35 +101,168 @@ type Gamepad interface {
 	// HatRelease will issue a hat-release event in the given direction
 	HatRelease(direction HatDirection) error
 
+	// LeftTrigger moves the left trigger, from 0.0 (released) to 1.0 (fully pressed)
+	LeftTrigger(value float32) error
+	// RightTrigger moves the right trigger, from 0.0 (released) to 1.0 (fully pressed)
+	RightTrigger(value float32) error
+
+	// DPad moves the d-pad to the given direction (or back to the center) within a single frame
+	DPad(direction DPadDirection) error
+
+	// SetState will set the complete state of the gamepad within a single frame
+	SetState(state GamepadState) error
+
+	// CenterAxis will move the given axis (see AxisLeftStickX, etc.) back to its center position
+	CenterAxis(axis uint16) error
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// Batch returns an empty batch, which allows to send several events using a single write (see EventBatch).
+	Batch() *EventBatch
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
 	io.Closer
 }
 
+// GamepadState holds the complete state of a gamepad. Buttons holds the pressed state of the buttons, with each button
+// absent from the map being released. Stick values are normalized (-1.0:1.0), just like the values passed to the stick
+// functions. Trigger values range from 0.0 (released) to 1.0 (fully pressed), just like the values passed to the
+// trigger functions, and also press the trigger buttons of gamepads that report both (see DualShock4Profile). The hat
+// values are -1 (up / left), 0 (centered) or 1 (down / right).
+type GamepadState struct {
+	Buttons map[int]bool
+
+	LeftStickX  float32
+	LeftStickY  float32
+	RightStickX float32
+	RightStickY float32
+
+	LeftTrigger  float32
+	RightTrigger float32
+
+	HatX int32
+	HatY int32
+}
+
 type vGamepad struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *device
+	profile    *GamepadProfile
+	onClose    closeHooks
+	mu         deviceMutex
+	ff         *ffLoop
+
+	// the last values sent to the device
+	buttons map[int]bool
+	axes    map[uint16]int32
+}
+
+// gamepadButtons holds the buttons that are registered for the default gamepad device.
+var gamepadButtons = []int{
+	ButtonGamepad,
+
+	ButtonSouth,
+	ButtonEast,
+	ButtonNorth,
+	ButtonWest,
+
+	ButtonBumperLeft,
+	ButtonBumperRight,
+	ButtonTriggerLeft,
+	ButtonTriggerRight,
+	ButtonThumbLeft,
+	ButtonThumbRight,
+
+	ButtonSelect,
+	ButtonStart,
+
+	ButtonDpadUp,    // * * *
+	ButtonDpadDown,  // * These buttons can be used instead of the hat events.
+	ButtonDpadLeft,  // *
+	ButtonDpadRight, // * * *
+
+	ButtonMode,
+}
+
+// gamepadAxisRanges holds the ranges of the axes that are registered for the default gamepad device.
+var gamepadAxisRanges = map[uint16]AbsRange{
+	absX:     {Min: -MaximumAxisValue, Max: MaximumAxisValue},
+	absY:     {Min: -MaximumAxisValue, Max: MaximumAxisValue},
+	absZ:     {Min: -MaximumAxisValue, Max: MaximumAxisValue},
+	absRX:    {Min: -MaximumAxisValue, Max: MaximumAxisValue},
+	absRY:    {Min: -MaximumAxisValue, Max: MaximumAxisValue},
+	absRZ:    {Min: -MaximumAxisValue, Max: MaximumAxisValue},
+	absHat0X: {Min: -1, Max: 1},
+	absHat0Y: {Min: -1, Max: 1},
 }
 
 // CreateGamepad will create a new gamepad using the given uinput
 // device path of the uinput device.
-func CreateGamepad(path string, name []byte, vendor uint16, product uint16) (Gamepad, error) { // TODO: Consider moving this to a generic function that works for all devices
+func CreateGamepad(path string, name []byte, vendor uint16, product uint16, opts ...DeviceOption) (Gamepad, error) { // TODO: Consider moving this to a generic function that works for all devices
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
 	}
-	err = validateUinputName(name)
+	profile := defaultGamepadProfile()
+	profile.Vendor = vendor
+	profile.Product = product
+	return createGamepad(path, name, profile, opts)
+}
+
+// CreateGamepadFromFile will create a new gamepad just like CreateGamepad, but uses the given uinput device file, which
+// has been opened by the caller already. The gamepad takes ownership of the file (see CreateKeyboardFromFile).
+func CreateGamepadFromFile(file *os.File, name []byte, vendor uint16, product uint16, opts ...DeviceOption) (Gamepad, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	profile := defaultGamepadProfile()
+	profile.Vendor = vendor
+	profile.Product = product
+	device, err := createGamepad(file.Name(), name, profile, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createGamepad(path string, name []byte, profile *GamepadProfile, opts []DeviceOption) (Gamepad, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	fd, err := createVGamepadDevice(path, name, vendor, product)
+	fd, err := createVGamepadDevice(path, name, profile, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return vGamepad{name: name, deviceFile: fd}, nil
+	vg := &vGamepad{name: name, deviceFile: fd, profile: profile, buttons: make(map[int]bool), axes: make(map[uint16]int32), mu: newDeviceMutex(options)}
+	if options.ffHandler != nil {
+		vg.ff = startFFLoop(fd, options.ffHandler)
+	}
+	return vg, nil
+}
+
+// NewGamepad is the same as CreateGamepad, but takes the name as a string, which is truncated if it exceeds 80 bytes
+// (see WithNameTruncation).
+func NewGamepad(path string, name string, vendor uint16, product uint16, opts ...DeviceOption) (Gamepad, error) {
+	return CreateGamepad(path, []byte(name), vendor, product, withStringName(opts)...)
 }
 
-func (vg vGamepad) ButtonPress(key int) error {
+func (vg *vGamepad) ButtonPress(key int) error {
 	err := vg.ButtonDown(key)
 	if err != nil {
 		return err
@@ -101,31 +274,45 @@ func (vg vGamepad) ButtonPress(key int) error {
 	return nil
 }
 
-func (vg vGamepad) ButtonDown(key int) error {
-	return sendBtnEvent(vg.deviceFile, []int{key}, btnStatePressed)
+func (vg *vGamepad) ButtonDown(key int) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	err := sendBtnEvent(vg.deviceFile, []int{key}, btnStatePressed)
+	if err != nil {
+		return err
+	}
+	vg.buttons[key] = true
+	return nil
 }
 
-func (vg vGamepad) ButtonUp(key int) error {
-	return sendBtnEvent(vg.deviceFile, []int{key}, btnStateReleased)
+func (vg *vGamepad) ButtonUp(key int) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	err := sendBtnEvent(vg.deviceFile, []int{key}, btnStateReleased)
+	if err != nil {
+		return err
+	}
+	delete(vg.buttons, key)
+	return nil
 }
 
-func (vg vGamepad) LeftStickMoveX(value float32) error {
+func (vg *vGamepad) LeftStickMoveX(value float32) error {
 	return vg.sendStickAxisEvent(absX, value)
 }
 
-func (vg vGamepad) LeftStickMoveY(value float32) error {
+func (vg *vGamepad) LeftStickMoveY(value float32) error {
 	return vg.sendStickAxisEvent(absY, value)
 }
 
-func (vg vGamepad) RightStickMoveX(value float32) error {
+func (vg *vGamepad) RightStickMoveX(value float32) error {
 	return vg.sendStickAxisEvent(absRX, value)
 }
 
-func (vg vGamepad) RightStickMoveY(value float32) error {
+func (vg *vGamepad) RightStickMoveY(value float32) error {
 	return vg.sendStickAxisEvent(absRY, value)
 }
 
-func (vg vGamepad) RightStickMove(x, y float32) error {
+func (vg *vGamepad) RightStickMove(x, y float32) error {
 	values := map[uint16]float32{}
 	values[absRX] = x
 	values[absRY] = y
@@ -133,7 +320,7 @@ func (vg vGamepad) RightStickMove(x, y float32) error {
 	return vg.sendStickEvent(values)
 }
 
-func (vg vGamepad) LeftStickMove(x, y float32) error {
+func (vg *vGamepad) LeftStickMove(x, y float32) error {
 	values := map[uint16]float32{}
 	values[absX] = x
 	values[absY] = y
@@ -141,57 +328,171 @@ func (vg vGamepad) LeftStickMove(x, y float32) error {
 	return vg.sendStickEvent(values)
 }
 
-func (vg vGamepad) HatPress(direction HatDirection) error {
+func (vg *vGamepad) HatPress(direction HatDirection) error {
 	return vg.sendHatEvent(direction, Press)
 }
 
-func (vg vGamepad) HatRelease(direction HatDirection) error {
+func (vg *vGamepad) HatRelease(direction HatDirection) error {
 	return vg.sendHatEvent(direction, Release)
 }
 
-func (vg vGamepad) sendStickAxisEvent(absCode uint16, value float32) error {
-	ev := inputEvent{
-		Type:  evAbs,
-		Code:  absCode,
-		Value: denormalizeInput(value),
+// LeftTrigger moves the left trigger. Unlike the stick values, the value ranges from 0.0 (released) to 1.0 (fully
+// pressed), which is scaled to the full range of the trigger axis. Gamepads with digital triggers (see
+// SwitchProProfile) press the trigger button for values of 0.5 and above, and gamepads that report their triggers as
+// both axes and buttons (see DualShock4Profile) press the button along with moving the axis.
+func (vg *vGamepad) LeftTrigger(value float32) error {
+	return vg.sendTriggerEvent(absZ, ButtonTriggerLeft, value)
+}
+
+// RightTrigger moves the right trigger. The value ranges from 0.0 (released) to 1.0 (fully pressed), just like the
+// value passed to LeftTrigger.
+func (vg *vGamepad) RightTrigger(value float32) error {
+	return vg.sendTriggerEvent(absRZ, ButtonTriggerRight, value)
+}
+
+// DPad moves the d-pad to the given direction, using the hat axes. Both axes are updated within a single frame, so
+// that diagonals (e.g. the quarter-circle motions of fighting games) never pass through an unintended direction.
+func (vg *vGamepad) DPad(direction DPadDirection) error {
+	values, ok := dPadHatValues[direction]
+	if !ok {
+		return fmt.Errorf("failed to move d-pad. Direction %d is invalid", direction)
+	}
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	profile := vg.gamepadProfile()
+
+	changed := false
+	for i, axis := range []uint16{absHat0X, absHat0Y} {
+		if _, ok := profile.Axes[axis]; !ok {
+			return fmt.Errorf("failed to move d-pad. Hat axis %d is not supported by the gamepad", axis)
+		}
+		if vg.axes[axis] == values[i] {
+			continue
+		}
+		err := sendRawEvent(vg.deviceFile, evAbs, axis, values[i])
+		if err != nil {
+			return fmt.Errorf("failed to move d-pad: %w", err)
+		}
+		vg.axes[axis] = values[i]
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return syncEvents(vg.deviceFile)
+}
+
+func (vg *vGamepad) sendTriggerEvent(axis uint16, button int, value float32) error {
+	if value < 0 || value > 1 {
+		return fmt.Errorf("failed to move trigger. Value %v is not between 0 and 1", value)
+	}
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	profile := vg.gamepadProfile()
+
+	if _, ok := profile.Axes[axis]; !ok {
+		return vg.sendDigitalTriggerEvent(profile, button, value >= triggerThreshold)
+	}
+	axisValue, _ := profile.triggerValue(axis, value)
+	err := sendRawEvent(vg.deviceFile, evAbs, axis, axisValue)
+	if err != nil {
+		return fmt.Errorf("failed to move trigger: %w", err)
+	}
+	vg.axes[axis] = axisValue
+
+	pressed := value >= triggerThreshold
+	if profile.hasButton(button) && vg.buttons[button] != pressed {
+		state := int32(btnStateReleased)
+		if pressed {
+			state = btnStatePressed
+		}
+		err = sendRawEvent(vg.deviceFile, evKey, uint16(button), state)
+		if err != nil {
+			return fmt.Errorf("failed to press trigger button: %w", err)
+		}
+		vg.setButton(button, pressed)
+	}
+	return syncEvents(vg.deviceFile)
+}
+
+// sendDigitalTriggerEvent presses or releases the button of a trigger, for gamepads that lack an analog trigger axis.
+func (vg *vGamepad) sendDigitalTriggerEvent(profile *GamepadProfile, button int, pressed bool) error {
+	if !profile.hasButton(button) {
+		return fmt.Errorf("failed to move trigger. The gamepad has neither a trigger axis nor a trigger button")
+	}
+	if vg.buttons[button] == pressed {
+		return nil
+	}
+	state := btnStateReleased
+	if pressed {
+		state = btnStatePressed
+	}
+	err := sendBtnEvent(vg.deviceFile, []int{button}, state)
+	if err != nil {
+		return err
+	}
+	vg.setButton(button, pressed)
+	return nil
+}
+
+// setButton records the state of a button that was sent to the device.
+func (vg *vGamepad) setButton(button int, pressed bool) {
+	if pressed {
+		vg.buttons[button] = true
+	} else {
+		delete(vg.buttons, button)
 	}
+}
 
-	buf, err := inputEventToBuffer(ev)
+func (vg *vGamepad) sendStickAxisEvent(absCode uint16, value float32) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	axisValue, err := vg.gamepadProfile().axisValue(absCode, value)
 	if err != nil {
-		return fmt.Errorf("writing abs stick event failed: %v", err)
+		return err
+	}
+	ev := inputEvent{
+		Type:  evAbs,
+		Code:  absCode,
+		Value: axisValue,
 	}
 
-	_, err = vg.deviceFile.Write(buf)
+	err = writeEvent(vg.deviceFile, ev)
 	if err != nil {
-		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
+		return fmt.Errorf("failed to write abs stick event to device file: %w", err)
 	}
+	vg.axes[absCode] = ev.Value
 
 	return syncEvents(vg.deviceFile)
 }
 
-func (vg vGamepad) sendStickEvent(values map[uint16]float32) error {
+func (vg *vGamepad) sendStickEvent(values map[uint16]float32) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
 	for code, value := range values {
+		axisValue, err := vg.gamepadProfile().axisValue(code, value)
+		if err != nil {
+			return err
+		}
 		ev := inputEvent{
 			Type:  evAbs,
 			Code:  code,
-			Value: denormalizeInput(value),
-		}
-
-		buf, err := inputEventToBuffer(ev)
-		if err != nil {
-			return fmt.Errorf("writing abs stick event failed: %v", err)
+			Value: axisValue,
 		}
 
-		_, err = vg.deviceFile.Write(buf)
+		err = writeEvent(vg.deviceFile, ev)
 		if err != nil {
-			return fmt.Errorf("failed to write abs stick event to device file: %v", err)
+			return fmt.Errorf("failed to write abs stick event to device file: %w", err)
 		}
+		vg.axes[code] = ev.Value
 	}
 
 	return syncEvents(vg.deviceFile)
 }
 
-func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error {
+func (vg *vGamepad) sendHatEvent(direction HatDirection, action HatAction) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
 	var event uint16
 	var value int32
 
@@ -225,6 +526,9 @@ func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error
 	if action == Release {
 		value = 0
 	}
+	if _, ok := vg.gamepadProfile().Axes[event]; !ok {
+		return fmt.Errorf("failed to send hat event. Hat axis %d is not supported by the gamepad", event)
+	}
 
 	ev := inputEvent{
 		Type:  evAbs,
@@ -232,80 +536,213 @@ func (vg vGamepad) sendHatEvent(direction HatDirection, action HatAction) error
 		Value: value,
 	}
 
-	buf, err := inputEventToBuffer(ev)
+	err := writeEvent(vg.deviceFile, ev)
 	if err != nil {
-		return fmt.Errorf("writing abs stick event failed: %v", err)
+		return fmt.Errorf("failed to write abs stick event to device file: %w", err)
 	}
+	vg.axes[event] = value
 
-	_, err = vg.deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write abs stick event to device file: %v", err)
+	return syncEvents(vg.deviceFile)
+}
+
+// SetState will set the complete state of the gamepad. Only the buttons and axes that changed since the last event are
+// sent, followed by a single sync event. This matches the way a real controller reports its state and makes sure that
+// consumers never see a partially updated state.
+func (vg *vGamepad) SetState(state GamepadState) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	profile := vg.gamepadProfile()
+	registered := make(map[int]bool, len(profile.Buttons))
+	for _, button := range profile.Buttons {
+		registered[button] = true
+	}
+	for button := range state.Buttons {
+		if !registered[button] {
+			return fmt.Errorf("failed to set gamepad state. Button %d is not supported", button)
+		}
+	}
+	for _, hat := range []int32{state.HatX, state.HatY} {
+		if hat < -1 || hat > 1 {
+			return fmt.Errorf("failed to set gamepad state. Hat value %d is out of range", hat)
+		}
+	}
+	pressed := make(map[int]bool, len(state.Buttons))
+	for button, p := range state.Buttons {
+		pressed[button] = p
+	}
+	for _, trigger := range []struct {
+		axis   uint16
+		button int
+		value  float32
+	}{
+		{absZ, ButtonTriggerLeft, state.LeftTrigger},
+		{absRZ, ButtonTriggerRight, state.RightTrigger},
+	} {
+		if trigger.value < 0 || trigger.value > 1 {
+			return fmt.Errorf("failed to set gamepad state. Trigger value %v is not between 0 and 1", trigger.value)
+		}
+		// gamepads reporting their triggers as both axes and buttons press the buttons along with the axes
+		if _, ok := profile.Axes[trigger.axis]; ok && registered[trigger.button] && trigger.value >= triggerThreshold {
+			pressed[trigger.button] = true
+		}
+	}
+
+	var events []inputEvent
+	for _, button := range profile.Buttons {
+		if !registered[button] {
+			// skip buttons that share the same code (e.g. ButtonGamepad and ButtonSouth)
+			continue
+		}
+		delete(registered, button)
+		if pressed[button] != vg.buttons[button] {
+			value := int32(btnStateReleased)
+			if pressed[button] {
+				value = btnStatePressed
+			}
+			events = append(events, inputEvent{Type: evKey, Code: uint16(button), Value: value})
+		}
+	}
+	for _, axis := range []struct {
+		code  uint16
+		value float32
+	}{
+		{absX, state.LeftStickX},
+		{absY, state.LeftStickY},
+		{absRX, state.RightStickX},
+		{absRY, state.RightStickY},
+		{absZ, state.LeftTrigger},
+		{absRZ, state.RightTrigger},
+		{absHat0X, float32(state.HatX)},
+		{absHat0Y, float32(state.HatY)},
+	} {
+		if _, ok := profile.Axes[axis.code]; !ok {
+			// axes the gamepad lacks (e.g. the analog triggers of a Switch Pro controller) must be left alone
+			if axis.value != 0 {
+				return fmt.Errorf("failed to set gamepad state. Axis %d is not supported by the gamepad", axis.code)
+			}
+			continue
+		}
+		value := int32(axis.value)
+		switch axis.code {
+		case absZ, absRZ:
+			value, _ = profile.triggerValue(axis.code, axis.value)
+		case absHat0X, absHat0Y:
+		default:
+			value, _ = profile.axisValue(axis.code, axis.value)
+		}
+		if vg.axes[axis.code] != value {
+			events = append(events, inputEvent{Type: evAbs, Code: axis.code, Value: value})
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, ev := range events {
+		err := writeEvent(vg.deviceFile, ev)
+		if err != nil {
+			return fmt.Errorf("failed to write gamepad state event to device file: %w", err)
+		}
+
+		if ev.Type == evKey {
+			vg.setButton(int(ev.Code), ev.Value == btnStatePressed)
+		} else {
+			vg.axes[ev.Code] = ev.Value
+		}
 	}
 
 	return syncEvents(vg.deviceFile)
 }
 
-func (vg vGamepad) Close() error {
-	return closeDevice(vg.deviceFile)
+// CenterAxis will move the given axis to the center of its range, e.g. in order to reset a single stick after drift
+// has been detected. The center is the midpoint of the range the axis was registered with, which is 0 for all axes of
+// the default gamepad.
+func (vg *vGamepad) CenterAxis(axis uint16) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	r, ok := vg.gamepadProfile().Axes[axis]
+	if !ok {
+		return fmt.Errorf("failed to center axis. Axis %d is not supported by the gamepad", axis)
+	}
+
+	ev := inputEvent{
+		Type:  evAbs,
+		Code:  axis,
+		Value: r.Min + (r.Max-r.Min)/2,
+	}
+	err := writeEvent(vg.deviceFile, ev)
+	if err != nil {
+		return fmt.Errorf("failed to write abs event to device file: %w", err)
+	}
+	vg.axes[axis] = ev.Value
+
+	return syncEvents(vg.deviceFile)
 }
 
-func createVGamepadDevice(path string, name []byte, vendor uint16, product uint16) (fd *os.File, err error) {
-	// This array is needed to register the event keys for the gamepad device.
-	keys := []uint16{
-		ButtonGamepad,
+func (vg *vGamepad) Close() error {
+	if vg.ff != nil {
+		vg.ff.stop()
+	}
+	err := closeDevice(vg.deviceFile)
+	vg.onClose.run()
+	return err
+}
 
-		ButtonSouth,
-		ButtonEast,
-		ButtonNorth,
-		ButtonWest,
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vg *vGamepad) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	return sendRawEvent(vg.deviceFile, evType, code, value)
+}
 
-		ButtonBumperLeft,
-		ButtonBumperRight,
-		ButtonTriggerLeft,
-		ButtonTriggerRight,
-		ButtonThumbLeft,
-		ButtonThumbRight,
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vg *vGamepad) Sync() error {
+	vg.mu.Lock()
+	defer vg.mu.Unlock()
+	return syncEvents(vg.deviceFile)
+}
 
-		ButtonSelect,
-		ButtonStart,
+// Batch returns an empty batch of events for the device.
+func (vg *vGamepad) Batch() *EventBatch {
+	return newEventBatch(vg.deviceFile)
+}
 
-		ButtonDpadUp,    // * * *
-		ButtonDpadDown,  // * These buttons can be used instead of the hat events.
-		ButtonDpadLeft,  // *
-		ButtonDpadRight, // * * *
+// FetchSyspath will return the syspath to the device file.
+func (vg *vGamepad) FetchSyspath() (string, error) {
+	return lookupSyspath(vg.deviceFile.File, vg.name)
+}
 
-		ButtonMode,
-	}
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vg *vGamepad) EventNode() (string, error) {
+	return lookupEventNode(vg.deviceFile.File, vg.name)
+}
 
-	// absEvents is for the absolute events for the gamepad device.
-	absEvents := []uint16{
-		absX,
-		absY,
-		absZ,
-		absRX,
-		absRY,
-		absRZ,
-		absHat0X,
-		absHat0Y,
-	}
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vg *vGamepad) OnClose(callback func()) {
+	vg.onClose.add(callback)
+}
 
-	deviceFile, err := createDeviceFile(path)
+func createVGamepadDevice(path string, name []byte, profile *GamepadProfile, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create virtual gamepad device: %v", err)
+		return nil, fmt.Errorf("failed to create virtual gamepad device: %w", err)
 	}
 
 	// register button events
 	err = registerDevice(deviceFile, uintptr(evKey))
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register virtual gamepad device: %v", err)
+		return nil, fmt.Errorf("failed to register virtual gamepad device: %w", err)
 	}
 
-	for _, code := range keys {
+	for _, code := range profile.Buttons {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(code))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register key number %d: %v", code, err)
+			return nil, fmt.Errorf("failed to register key number %d: %w", code, err)
 		}
 	}
 
@@ -313,25 +750,49 @@ func createVGamepadDevice(path string, name []byte, vendor uint16, product uint1
 	err = registerDevice(deviceFile, uintptr(evAbs))
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register absolute event input device: %v", err)
+		return nil, fmt.Errorf("failed to register absolute event input device: %w", err)
 	}
 
-	for _, event := range absEvents {
+	for event := range profile.Axes {
 		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute event %v: %w", event, err)
 		}
 	}
 
-	return createUsbDevice(deviceFile,
-		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  vendor,
-				Product: product,
-				Version: 1}})
+	// register rumble effects, if force feedback is enabled
+	var effectsMax uint32
+	if options.ffHandler != nil {
+		err = registerDevice(deviceFile, uintptr(evFf))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register force feedback events: %w", err)
+		}
+		err = ioctl(deviceFile, uiSetFFBit, uintptr(FFRumble))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register rumble effect: %w", err)
+		}
+		effectsMax = ffEffectsMax
+	}
+
+	dev := uinputUserDev{
+		Name: toUinputName(name),
+		ID: inputID{
+			Bustype: uint16(options.busType),
+			Vendor:  profile.Vendor,
+			Product: profile.Product,
+			Version: options.version},
+		EffectsMax: effectsMax}
+	for axis, r := range profile.Axes {
+		dev.Absmin[axis] = r.Min
+		dev.Absmax[axis] = r.Max
+		dev.Absfuzz[axis] = r.Fuzz
+		dev.Absflat[axis] = r.Flat
+	}
+
+	return createUsbDevice(deviceFile, dev, options)
 }
 
 // Takes in a normalized value (-1.0:1.0) and return an event value
@@ -246,3 +246,304 @@ func TestGamepadMoveToFailsOnClosedDevice(t *testing.T) {
 		t.Fatalf("Expected error due to closed device, but no error was returned.")
 	}
 }
+
+func TestGamepadSetStateOnlySendsChanges(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vg := &vGamepad{deviceFile: &device{File: file}, buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+
+	// the released triggers report the minimum of their range, which is sent along with the first state
+	err := vg.SetState(GamepadState{Buttons: map[int]bool{ButtonSouth: true}, LeftStickX: 1, HatY: -1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+	err = vg.SetState(GamepadState{Buttons: map[int]bool{ButtonSouth: true}, LeftStickX: 0.5, HatY: -1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+	err = vg.SetState(GamepadState{Buttons: map[int]bool{ButtonSouth: true}, LeftStickX: 0.5, HatY: -1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: ButtonSouth, Value: btnStatePressed},
+		{Type: evAbs, Code: absX, Value: MaximumAxisValue},
+		{Type: evAbs, Code: absZ, Value: -MaximumAxisValue},
+		{Type: evAbs, Code: absRZ, Value: -MaximumAxisValue},
+		{Type: evAbs, Code: absHat0Y, Value: -1},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absX, Value: MaximumAxisValue / 2},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestGamepadSetStateFailsOnInvalidState(t *testing.T) {
+	vg := &vGamepad{buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+
+	err := vg.SetState(GamepadState{Buttons: map[int]bool{KeyA: true}})
+	if err == nil {
+		t.Fatalf("Expected SetState to fail due to an unsupported button, but got no error.")
+	}
+	err = vg.SetState(GamepadState{HatX: 2})
+	if err == nil {
+		t.Fatalf("Expected SetState to fail due to an invalid hat value, but got no error.")
+	}
+}
+
+func TestGamepadSetState(t *testing.T) {
+	vg, err := CreateGamepad("/dev/uinput", []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+	}
+	defer vg.Close()
+
+	err = vg.SetState(GamepadState{Buttons: map[int]bool{ButtonNorth: true, ButtonStart: true}, RightStickY: -1, RightTrigger: 1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state. Last error was: %s\n", err)
+	}
+	err = vg.SetState(GamepadState{})
+	if err != nil {
+		t.Fatalf("Failed to reset gamepad state. Last error was: %s\n", err)
+	}
+}
+
+func TestCenterAxisResetsSingleAxis(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vg := &vGamepad{deviceFile: &device{File: file}, buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+
+	err := vg.LeftStickMove(0.5, -0.5)
+	if err != nil {
+		t.Fatalf("Failed to move left stick: %v", err)
+	}
+	err = vg.CenterAxis(AxisLeftStickX)
+	if err != nil {
+		t.Fatalf("Failed to center axis: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 0},
+		{Type: evSyn, Code: synReport},
+	}
+	events = events[len(events)-len(expected):]
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+	if vg.axes[absY] != denormalizeInput(-0.5) {
+		t.Fatalf("Expected the y axis to remain at %d, but got %d", denormalizeInput(-0.5), vg.axes[absY])
+	}
+}
+
+func TestCenterAxisFailsOnUnknownAxis(t *testing.T) {
+	vg := &vGamepad{buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+	err := vg.CenterAxis(absWheel)
+	if err == nil {
+		t.Fatalf("Expected CenterAxis to fail for an unsupported axis, but got no error.")
+	}
+}
+
+func TestGamepadTriggers(t *testing.T) {
+	vg, events := newTestProfileGamepad(t, Xbox360Profile)
+
+	err := vg.LeftTrigger(1)
+	if err != nil {
+		t.Fatalf("Failed to move left trigger: %v", err)
+	}
+	err = vg.RightTrigger(0.5)
+	if err != nil {
+		t.Fatalf("Failed to move right trigger: %v", err)
+	}
+	err = vg.LeftTrigger(1.5)
+	if err == nil {
+		t.Fatalf("Expected LeftTrigger to fail due to an invalid value, but got no error.")
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: absZ, Value: 255},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absRZ, Value: 127},
+		{Type: evSyn, Code: synReport},
+	}
+	actual := events()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, actual[i])
+		}
+	}
+}
+
+func TestGamepadTriggersMatchSetState(t *testing.T) {
+	vg, events := newTestProfileGamepad(t, Xbox360Profile)
+
+	err := vg.LeftTrigger(0)
+	if err != nil {
+		t.Fatalf("Failed to move left trigger: %v", err)
+	}
+	err = vg.SetState(GamepadState{})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+	err = vg.SetState(GamepadState{RightTrigger: 1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+	err = vg.RightTrigger(1)
+	if err != nil {
+		t.Fatalf("Failed to move right trigger: %v", err)
+	}
+	err = vg.SetState(GamepadState{RightTrigger: 1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+	err = vg.SetState(GamepadState{RightTrigger: -0.5})
+	if err == nil {
+		t.Fatalf("Expected SetState to fail due to an invalid trigger value, but got no error.")
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: absZ, Value: 0},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absRZ, Value: 255},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absRZ, Value: 255},
+		{Type: evSyn, Code: synReport},
+	}
+	actual := events()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, actual[i])
+		}
+	}
+}
+
+func TestGamepadTriggersPressButtons(t *testing.T) {
+	vg, events := newTestProfileGamepad(t, DualShock4Profile)
+
+	err := vg.LeftTrigger(0.2)
+	if err != nil {
+		t.Fatalf("Failed to move left trigger: %v", err)
+	}
+	err = vg.LeftTrigger(1)
+	if err != nil {
+		t.Fatalf("Failed to move left trigger: %v", err)
+	}
+	if !vg.buttons[ButtonTriggerLeft] {
+		t.Fatalf("Expected the left trigger button to be pressed")
+	}
+	err = vg.SetState(GamepadState{})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+
+	var actual []inputEvent
+	for _, ev := range events() {
+		if ev.Type == evKey || ev.Code == absZ {
+			actual = append(actual, ev)
+		}
+	}
+	expected := []inputEvent{
+		{Type: evAbs, Code: absZ, Value: 51},
+		{Type: evAbs, Code: absZ, Value: 255},
+		{Type: evKey, Code: ButtonTriggerLeft, Value: btnStatePressed},
+		{Type: evKey, Code: ButtonTriggerLeft, Value: btnStateReleased},
+		{Type: evAbs, Code: absZ, Value: 0},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, actual[i])
+		}
+	}
+}
+
+func TestGamepadDigitalTriggers(t *testing.T) {
+	vg, events := newTestProfileGamepad(t, SwitchProProfile)
+
+	for _, value := range []float32{0.8, 1, 0.2} {
+		err := vg.LeftTrigger(value)
+		if err != nil {
+			t.Fatalf("Failed to move left trigger: %v", err)
+		}
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: ButtonTriggerLeft, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: ButtonTriggerLeft, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	actual := events()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, actual[i])
+		}
+	}
+}
+
+func TestGamepadDPadMovesDiagonallyInSingleFrame(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vg := &vGamepad{deviceFile: &device{File: file}, buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+
+	for _, direction := range []DPadDirection{DPadDown, DPadDownRight, DPadRight, DPadCentered} {
+		err := vg.DPad(direction)
+		if err != nil {
+			t.Fatalf("Failed to move d-pad: %v", err)
+		}
+	}
+	err := vg.DPad(DPadDirection(42))
+	if err == nil {
+		t.Fatalf("Expected DPad to fail due to an invalid direction, but got no error.")
+	}
+
+	expected := []inputEvent{
+		{Type: evAbs, Code: absHat0Y, Value: 1},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absHat0X, Value: 1},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absHat0Y, Value: 0},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absHat0X, Value: 0},
+		{Type: evSyn, Code: synReport},
+	}
+	actual := readTestEvents(t, file)
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(actual), actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, actual[i])
+		}
+	}
+}
+
+func TestCreateGamepadFromFileFailsOnNilFile(t *testing.T) {
+	_, err := CreateGamepadFromFile(nil, []byte("Test Gamepad"), 0xDEAD, 0xBEEF)
+	if err == nil {
+		t.Fatalf("Expected gamepad creation to fail due to a missing device file, but got no error.")
+	}
+}
@@ -0,0 +1,202 @@
+package uinput
+
+import "fmt"
+
+// GamepadProfile describes the capabilities and ids of a gamepad. Games and libraries like SDL look up their button
+// mappings by the vendor id, product id and version of a device (see SDL_GameControllerDB), so a virtual gamepad using
+// the profile of a popular controller is recognized out of the box. The profiles of some popular controllers are
+// predefined (see Xbox360Profile, DualShock4Profile and SwitchProProfile).
+type GamepadProfile struct {
+	Vendor  uint16
+	Product uint16
+	// Version and BusType are reported by the device, unless overridden using WithVersion and WithBusType. Zero
+	// values leave the defaults of the device options untouched.
+	Version uint16
+	BusType BusType
+
+	// Buttons holds the buttons of the gamepad (see ButtonSouth, etc.).
+	Buttons []int
+	// Axes holds the ranges of the axes of the gamepad (see AxisLeftStickX, etc.). Stick values, which are normalized
+	// (-1.0:1.0), are scaled to these ranges around their center, and trigger values (0.0:1.0) are scaled to these
+	// ranges upwards from their minimum. The hat axes must range from -1 to 1.
+	Axes map[uint16]AbsRange
+}
+
+// Xbox360Profile matches a wired Xbox 360 controller, as reported by the xpad driver. Its triggers are analog axes.
+var Xbox360Profile = GamepadProfile{
+	Vendor:  0x045e,
+	Product: 0x028e,
+	Version: 0x0114,
+	BusType: BusUsb,
+	Buttons: []int{
+		ButtonSouth, ButtonEast, ButtonNorth, ButtonWest,
+		ButtonBumperLeft, ButtonBumperRight,
+		ButtonSelect, ButtonStart, ButtonMode,
+		ButtonThumbLeft, ButtonThumbRight,
+	},
+	Axes: map[uint16]AbsRange{
+		absX:     {Min: -32768, Max: 32767, Fuzz: 16, Flat: 128},
+		absY:     {Min: -32768, Max: 32767, Fuzz: 16, Flat: 128},
+		absRX:    {Min: -32768, Max: 32767, Fuzz: 16, Flat: 128},
+		absRY:    {Min: -32768, Max: 32767, Fuzz: 16, Flat: 128},
+		absZ:     {Min: 0, Max: 255},
+		absRZ:    {Min: 0, Max: 255},
+		absHat0X: {Min: -1, Max: 1},
+		absHat0Y: {Min: -1, Max: 1},
+	},
+}
+
+// DualShock4Profile matches a DualShock 4 controller (second revision) connected using USB, as reported by the
+// hid-playstation driver. Its triggers are reported both as buttons and as analog axes, with the buttons being pressed
+// once a trigger is pressed halfway. Note that the touchpad and the
+// motion sensors of the controller are separate devices, which are not part of the profile.
+var DualShock4Profile = GamepadProfile{
+	Vendor:  0x054c,
+	Product: 0x09cc,
+	Version: 0x8111,
+	BusType: BusUsb,
+	Buttons: []int{
+		ButtonSouth, ButtonEast, ButtonNorth, ButtonWest,
+		ButtonBumperLeft, ButtonBumperRight, ButtonTriggerLeft, ButtonTriggerRight,
+		ButtonSelect, ButtonStart, ButtonMode,
+		ButtonThumbLeft, ButtonThumbRight,
+	},
+	Axes: map[uint16]AbsRange{
+		absX:     {Min: 0, Max: 255},
+		absY:     {Min: 0, Max: 255},
+		absRX:    {Min: 0, Max: 255},
+		absRY:    {Min: 0, Max: 255},
+		absZ:     {Min: 0, Max: 255},
+		absRZ:    {Min: 0, Max: 255},
+		absHat0X: {Min: -1, Max: 1},
+		absHat0Y: {Min: -1, Max: 1},
+	},
+}
+
+// SwitchProProfile matches a Nintendo Switch Pro controller, as reported by the hid-nintendo driver. Its triggers are
+// digital, so they have to be pressed using ButtonTriggerLeft and ButtonTriggerRight. BtnZ is the capture button.
+var SwitchProProfile = GamepadProfile{
+	Vendor:  0x057e,
+	Product: 0x2009,
+	Version: 0x8111,
+	BusType: BusUsb,
+	Buttons: []int{
+		ButtonSouth, ButtonEast, ButtonNorth, ButtonWest,
+		ButtonBumperLeft, ButtonBumperRight, ButtonTriggerLeft, ButtonTriggerRight,
+		ButtonSelect, ButtonStart, ButtonMode, BtnZ,
+		ButtonThumbLeft, ButtonThumbRight,
+	},
+	Axes: map[uint16]AbsRange{
+		absX:     {Min: -32767, Max: 32767, Fuzz: 250, Flat: 500},
+		absY:     {Min: -32767, Max: 32767, Fuzz: 250, Flat: 500},
+		absRX:    {Min: -32767, Max: 32767, Fuzz: 250, Flat: 500},
+		absRY:    {Min: -32767, Max: 32767, Fuzz: 250, Flat: 500},
+		absHat0X: {Min: -1, Max: 1},
+		absHat0Y: {Min: -1, Max: 1},
+	},
+}
+
+// CreateGamepadFromProfile will create a new gamepad using the given uinput device path of the uinput device. The
+// gamepad reports the ids and capabilities of the given profile.
+func CreateGamepadFromProfile(path string, name []byte, profile GamepadProfile, opts ...DeviceOption) (Gamepad, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := profile.validate()
+	if err != nil {
+		return nil, err
+	}
+	var profileOpts []DeviceOption
+	if profile.BusType != 0 {
+		profileOpts = append(profileOpts, WithBusType(profile.BusType))
+	}
+	if profile.Version != 0 {
+		profileOpts = append(profileOpts, WithVersion(profile.Version))
+	}
+	return createGamepad(path, name, p, append(profileOpts, opts...))
+}
+
+// NewGamepadFromProfile is the same as CreateGamepadFromProfile, but takes the name as a string, which is truncated if
+// it exceeds 80 bytes (see WithNameTruncation).
+func NewGamepadFromProfile(path string, name string, profile GamepadProfile, opts ...DeviceOption) (Gamepad, error) {
+	return CreateGamepadFromProfile(path, []byte(name), profile, withStringName(opts)...)
+}
+
+// defaultGamepadProfile returns the profile of a gamepad created using CreateGamepad.
+func defaultGamepadProfile() *GamepadProfile {
+	return &GamepadProfile{Buttons: gamepadButtons, Axes: gamepadAxisRanges}
+}
+
+// gamepadProfile returns the profile the gamepad was created with.
+func (vg *vGamepad) gamepadProfile() *GamepadProfile {
+	if vg.profile == nil {
+		return defaultGamepadProfile()
+	}
+	return vg.profile
+}
+
+// validate checks the buttons and axes of the profile and returns a copy of it, so that later changes to the profile
+// do not affect a gamepad created from it.
+func (p GamepadProfile) validate() (*GamepadProfile, error) {
+	if len(p.Buttons) == 0 {
+		return nil, fmt.Errorf("gamepad profile must define at least one button")
+	}
+	buttons := make([]int, len(p.Buttons))
+	for i, button := range p.Buttons {
+		if button < BtnMisc {
+			return nil, fmt.Errorf("gamepad profile button %d is not a button code", button)
+		}
+		buttons[i] = button
+	}
+	axes := make(map[uint16]AbsRange, len(p.Axes))
+	for axis, r := range p.Axes {
+		if int(axis) >= absSize {
+			return nil, fmt.Errorf("gamepad profile axis %d is out of range", axis)
+		}
+		if r.Min >= r.Max {
+			return nil, fmt.Errorf("gamepad profile axis %d has an invalid range (%d:%d)", axis, r.Min, r.Max)
+		}
+		if (axis == absHat0X || axis == absHat0Y) && (r.Min != -1 || r.Max != 1) {
+			return nil, fmt.Errorf("gamepad profile hat axis %d must range from -1 to 1", axis)
+		}
+		axes[axis] = r
+	}
+	p.Buttons = buttons
+	p.Axes = axes
+	return &p, nil
+}
+
+// axisValue scales a normalized value (-1.0:1.0) to the range of the given axis.
+func (p *GamepadProfile) axisValue(axis uint16, value float32) (int32, error) {
+	r, ok := p.Axes[axis]
+	if !ok {
+		return 0, fmt.Errorf("axis %d is not supported by the gamepad", axis)
+	}
+	center := (float64(r.Min) + float64(r.Max)) / 2
+	half := (float64(r.Max) - float64(r.Min)) / 2
+	return int32(center + float64(value)*half), nil
+}
+
+// triggerThreshold is the trigger value at which the button of a trigger is pressed.
+const triggerThreshold = 0.5
+
+// triggerValue scales a trigger value (0.0:1.0) to the range of the given axis, so that a released trigger reports the
+// minimum of the range.
+func (p *GamepadProfile) triggerValue(axis uint16, value float32) (int32, error) {
+	r, ok := p.Axes[axis]
+	if !ok {
+		return 0, fmt.Errorf("axis %d is not supported by the gamepad", axis)
+	}
+	return r.Min + int32(float64(value)*float64(r.Max-r.Min)), nil
+}
+
+// hasButton reports whether the profile contains the given button.
+func (p *GamepadProfile) hasButton(button int) bool {
+	for _, b := range p.Buttons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
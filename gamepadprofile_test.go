@@ -0,0 +1,100 @@
+package uinput
+
+import "testing"
+
+func newTestProfileGamepad(t *testing.T, profile GamepadProfile) (*vGamepad, func() []inputEvent) {
+	p, err := profile.validate()
+	if err != nil {
+		t.Fatalf("Failed to validate profile: %v", err)
+	}
+	file := createTestEventFile(t)
+	vg := &vGamepad{deviceFile: &device{File: file}, profile: p, buttons: make(map[int]bool), axes: make(map[uint16]int32)}
+	return vg, func() []inputEvent {
+		defer file.Close()
+		return readTestEvents(t, file)
+	}
+}
+
+func TestGamepadProfileScalesAxisValues(t *testing.T) {
+	vg, events := newTestProfileGamepad(t, DualShock4Profile)
+
+	err := vg.LeftStickMove(-1, 1)
+	if err != nil {
+		t.Fatalf("Failed to move left stick: %v", err)
+	}
+	err = vg.CenterAxis(AxisLeftStickX)
+	if err != nil {
+		t.Fatalf("Failed to center axis: %v", err)
+	}
+
+	expected := map[uint16]int32{absX: 127, absY: 255}
+	for _, ev := range events() {
+		if ev.Type == evAbs && ev.Code == absY && ev.Value != expected[absY] {
+			t.Fatalf("Expected y axis value %d, but got %d", expected[absY], ev.Value)
+		}
+	}
+	if vg.axes[absX] != expected[absX] {
+		t.Fatalf("Expected the x axis to be centered at %d, but got %d", expected[absX], vg.axes[absX])
+	}
+}
+
+func TestGamepadProfileRejectsMissingAxes(t *testing.T) {
+	vg, events := newTestProfileGamepad(t, SwitchProProfile)
+	defer events()
+
+	err := vg.SetState(GamepadState{LeftTrigger: 1})
+	if err == nil {
+		t.Fatalf("Expected SetState to fail due to the missing trigger axis, but got no error.")
+	}
+	err = vg.CenterAxis(AxisRightTrigger)
+	if err == nil {
+		t.Fatalf("Expected CenterAxis to fail due to the missing trigger axis, but got no error.")
+	}
+	err = vg.SetState(GamepadState{Buttons: map[int]bool{ButtonTriggerLeft: true, BtnZ: true}, RightStickX: 1})
+	if err != nil {
+		t.Fatalf("Failed to set gamepad state: %v", err)
+	}
+}
+
+func TestGamepadProfileValidation(t *testing.T) {
+	invalid := []GamepadProfile{
+		{Axes: map[uint16]AbsRange{absX: {Min: 0, Max: 255}}},
+		{Buttons: []int{KeyA}},
+		{Buttons: []int{ButtonSouth}, Axes: map[uint16]AbsRange{absX: {Min: 10, Max: 10}}},
+		{Buttons: []int{ButtonSouth}, Axes: map[uint16]AbsRange{absHat0X: {Min: 0, Max: 255}}},
+		{Buttons: []int{ButtonSouth}, Axes: map[uint16]AbsRange{absSize: {Min: 0, Max: 1}}},
+	}
+	for _, profile := range invalid {
+		if _, err := profile.validate(); err == nil {
+			t.Fatalf("Expected profile %+v to be invalid, but got no error.", profile)
+		}
+	}
+
+	profile := GamepadProfile{Buttons: []int{ButtonSouth}, Axes: map[uint16]AbsRange{absX: {Min: 0, Max: 255}}}
+	p, err := profile.validate()
+	if err != nil {
+		t.Fatalf("Failed to validate profile: %v", err)
+	}
+	profile.Buttons[0] = ButtonEast
+	profile.Axes[absX] = AbsRange{Min: 0, Max: 1}
+	if p.Buttons[0] != ButtonSouth || p.Axes[absX].Max != 255 {
+		t.Fatalf("Expected the validated profile to be a copy, but it was modified")
+	}
+}
+
+func TestCreateGamepadFromProfile(t *testing.T) {
+	for _, profile := range []GamepadProfile{Xbox360Profile, DualShock4Profile, SwitchProProfile} {
+		vg, err := NewGamepadFromProfile("/dev/uinput", "Test Gamepad", profile)
+		if err != nil {
+			t.Fatalf("Failed to create the virtual gamepad. Last error was: %s\n", err)
+		}
+		err = vg.LeftStickMove(0.5, -0.5)
+		if err != nil {
+			t.Fatalf("Failed to move left stick. Last error was: %s\n", err)
+		}
+		err = vg.Close()
+		if err != nil {
+			t.Fatalf("Failed to close device. Last error was: %s\n", err)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package uinput
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SwipeDirection is the direction the contacts of a swipe gesture move in.
+type SwipeDirection int
+
+const (
+	// SwipeUp moves the contacts towards the top of the screen.
+	SwipeUp SwipeDirection = iota
+	// SwipeDown moves the contacts towards the bottom of the screen.
+	SwipeDown
+	// SwipeLeft moves the contacts towards the left edge of the screen.
+	SwipeLeft
+	// SwipeRight moves the contacts towards the right edge of the screen.
+	SwipeRight
+)
+
+const (
+	// gestureSteps is the number of frames the movement of a gesture is split into.
+	gestureSteps = 20
+	// gestureInterval is the time between two frames of a gesture, which matches the report rate of common touch
+	// devices (100Hz). Gesture recognizers tend to refuse contacts that move too fast or in too large steps.
+	gestureInterval = 10 * time.Millisecond
+)
+
+type point struct {
+	x int32
+	y int32
+}
+
+// TwoFingerScroll will put down two contacts next to each other in the center of the screen, move both by the given
+// distance and lift them again. The gesture takes about 200ms.
+func (vs *vTouchScreen) TwoFingerScroll(dx int32, dy int32) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	start, end, err := vs.twoFingerScrollPoints(dx, dy)
+	if err != nil {
+		return err
+	}
+	return vs.gesture(start, end, nil)
+}
+
+// Pinch will put down two contacts in the center of the screen and move them apart (scale > 1, zooming in) or
+// together (scale < 1, zooming out), so that their final distance is the initial distance multiplied by scale. The
+// larger of both distances is half the width of the screen. The gesture takes about 200ms.
+func (vs *vTouchScreen) Pinch(scale float64) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	start, end, err := vs.pinchPoints(scale)
+	if err != nil {
+		return err
+	}
+	return vs.gesture(start, end, nil)
+}
+
+// Swipe will put down the given number of contacts in a row in the center of the screen, move them by a third of the
+// width (or height) of the screen in the given direction and lift them again. Desktops commonly use swipes of three or
+// four fingers in order to switch workspaces. The gesture takes about 200ms.
+func (vs *vTouchScreen) Swipe(fingers int, direction SwipeDirection) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	start, end, err := vs.swipePoints(fingers, direction)
+	if err != nil {
+		return err
+	}
+	return vs.gesture(start, end, nil)
+}
+
+// Rotate will put down two contacts opposite of each other around the center of the screen and turn them around the
+// center by the given angle in degrees (clockwise for positive angles). The distance between the contacts is half the
+// width (or height, whichever is smaller) of the screen. The gesture takes about 200ms.
+func (vs *vTouchScreen) Rotate(degrees float64) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	path, err := vs.rotatePath(degrees)
+	if err != nil {
+		return err
+	}
+	return vs.gesturePath(path, nil)
+}
+
+// twoFingerScrollPoints returns the start and end positions of the contacts of a two finger scroll.
+func (vs *vTouchScreen) twoFingerScrollPoints(dx int32, dy int32) ([]point, []point, error) {
+	if dx == 0 && dy == 0 {
+		return nil, nil, fmt.Errorf("failed to perform TwoFingerScroll. The scroll distance must not be zero")
+	}
+
+	cx, cy := vs.center()
+	spacing := vs.fingerSpacing()
+	start := []point{
+		{cx - spacing/2 - dx/2, cy - dy/2},
+		{cx + spacing/2 - dx/2, cy - dy/2},
+	}
+	end := []point{
+		{start[0].x + dx, start[0].y + dy},
+		{start[1].x + dx, start[1].y + dy},
+	}
+	return start, end, nil
+}
+
+// pinchPoints returns the start and end positions of the contacts of a pinch.
+func (vs *vTouchScreen) pinchPoints(scale float64) ([]point, []point, error) {
+	if scale <= 0 || math.IsInf(scale, 0) || math.IsNaN(scale) {
+		return nil, nil, fmt.Errorf("failed to perform Pinch. %v is not a valid scale. Expected a positive value", scale)
+	}
+
+	far := float64(vs.maxX-vs.minX) / 2
+	startDistance, endDistance := far/scale, far
+	if scale < 1 {
+		startDistance, endDistance = far, far*scale
+	}
+
+	cx, cy := vs.center()
+	start := []point{
+		{cx - roundToInt32(startDistance/2), cy},
+		{cx + roundToInt32(startDistance/2), cy},
+	}
+	end := []point{
+		{cx - roundToInt32(endDistance/2), cy},
+		{cx + roundToInt32(endDistance/2), cy},
+	}
+	return start, end, nil
+}
+
+// swipePoints returns the start and end positions of the contacts of a swipe.
+func (vs *vTouchScreen) swipePoints(fingers int, direction SwipeDirection) ([]point, []point, error) {
+	if fingers <= 0 {
+		return nil, nil, fmt.Errorf("failed to perform Swipe. %d is not a valid number of fingers. Expected a positive value", fingers)
+	}
+
+	var dx, dy int32
+	switch direction {
+	case SwipeUp:
+		dy = -(vs.maxY - vs.minY) / 3
+	case SwipeDown:
+		dy = (vs.maxY - vs.minY) / 3
+	case SwipeLeft:
+		dx = -(vs.maxX - vs.minX) / 3
+	case SwipeRight:
+		dx = (vs.maxX - vs.minX) / 3
+	default:
+		return nil, nil, fmt.Errorf("failed to perform Swipe. %d is not a valid direction", direction)
+	}
+
+	cx, cy := vs.center()
+	spacing := vs.fingerSpacing()
+	start := make([]point, fingers)
+	end := make([]point, fingers)
+	for i := range start {
+		x := cx + roundToInt32((float64(i)-float64(fingers-1)/2)*float64(spacing)) - dx/2
+		start[i] = point{x, cy - dy/2}
+		end[i] = point{x + dx, cy - dy/2 + dy}
+	}
+	return start, end, nil
+}
+
+// rotatePath returns the positions of the contacts of a rotation for each step of the gesture.
+func (vs *vTouchScreen) rotatePath(degrees float64) ([][]point, error) {
+	if degrees == 0 || math.IsInf(degrees, 0) || math.IsNaN(degrees) {
+		return nil, fmt.Errorf("failed to perform Rotate. %v is not a valid angle. Expected a non-zero value", degrees)
+	}
+
+	radius := float64(vs.maxX-vs.minX) / 4
+	if height := float64(vs.maxY-vs.minY) / 4; height < radius {
+		radius = height
+	}
+	cx, cy := vs.center()
+	path := make([][]point, gestureSteps+1)
+	for step := range path {
+		angle := degrees * math.Pi / 180 * float64(step) / gestureSteps
+		dx, dy := roundToInt32(radius*math.Cos(angle)), roundToInt32(radius*math.Sin(angle))
+		path[step] = []point{{cx - dx, cy - dy}, {cx + dx, cy + dy}}
+	}
+	return path, nil
+}
+
+// gesture puts down a contact at each of the given start positions, moves the contacts to their end positions in a
+// straight line and lifts them again (see gesturePath).
+func (vs *vTouchScreen) gesture(start []point, end []point, contact []inputEvent) error {
+	path := make([][]point, gestureSteps+1)
+	for step := range path {
+		progress := float64(step) / gestureSteps
+		path[step] = make([]point, len(start))
+		for i := range start {
+			path[step][i] = point{
+				start[i].x + roundToInt32(float64(end[i].x-start[i].x)*progress),
+				start[i].y + roundToInt32(float64(end[i].y-start[i].y)*progress),
+			}
+		}
+	}
+	return vs.gesturePath(path, contact)
+}
+
+// gesturePath puts down a contact at each of the positions of the first step of the given path (using the lowest
+// slots), moves the contacts along the remaining steps and lifts them again. All contacts are changed within the same
+// frame, just like a real multi-touch device would report them. The given events are added for each contact that is
+// put down (e.g. the tool of the contact on a ClickPad). The device needs to be locked by the caller for the whole of
+// the gesture.
+func (vs *vTouchScreen) gesturePath(path [][]point, contact []inputEvent) error {
+	if len(vs.active) != 0 {
+		return fmt.Errorf("failed to perform gesture. All contacts need to be lifted before")
+	}
+	start := path[0]
+	if len(start) > vs.slots {
+		return fmt.Errorf("failed to perform gesture. %d contacts are needed, but only %d slots are available", len(start), vs.slots)
+	}
+	for _, positions := range path {
+		for _, p := range positions {
+			err := vs.validatePosition(p.x, p.y)
+			if err != nil {
+				return fmt.Errorf("failed to perform gesture: %w", err)
+			}
+		}
+	}
+
+	f := vs.frame()
+	for slot, p := range start {
+		err := f.touchDown(slot, p.x, p.y)
+		if err != nil {
+			return err
+		}
+		f.events = append(f.events, contact...)
+	}
+	err := f.send()
+	if err != nil {
+		return vs.abortGesture(err)
+	}
+
+	for _, positions := range path[1:] {
+		time.Sleep(gestureInterval)
+		f = vs.frame()
+		for slot, p := range positions {
+			err = f.touchMove(slot, p.x, p.y)
+			if err != nil {
+				return vs.abortGesture(err)
+			}
+		}
+		err = f.send()
+		if err != nil {
+			return vs.abortGesture(err)
+		}
+	}
+
+	time.Sleep(gestureInterval)
+	return vs.liftAll()
+}
+
+// abortGesture tries to lift all contacts of a failed gesture, so that no contact remains on the screen, and returns
+// the error that caused the gesture to fail.
+func (vs *vTouchScreen) abortGesture(err error) error {
+	_ = vs.liftAll()
+	return err
+}
+
+// liftAll lifts all contacts on the screen within a single frame.
+func (vs *vTouchScreen) liftAll() error {
+	f := vs.frame()
+	for slot := 0; slot < vs.slots; slot++ {
+		if !vs.active[slot] {
+			continue
+		}
+		err := f.touchUp(slot)
+		if err != nil {
+			return err
+		}
+	}
+	if len(f.events) == 0 {
+		return nil
+	}
+	return f.send()
+}
+
+func (vs *vTouchScreen) center() (int32, int32) {
+	return vs.minX + (vs.maxX-vs.minX)/2, vs.minY + (vs.maxY-vs.minY)/2
+}
+
+// fingerSpacing returns the distance between adjacent contacts of a gesture, which is a tenth of the width of the
+// screen (roughly the width of a finger on a common touch pad).
+func (vs *vTouchScreen) fingerSpacing() int32 {
+	return (vs.maxX - vs.minX) / 10
+}
+
+func roundToInt32(v float64) int32 {
+	return int32(math.Round(v))
+}
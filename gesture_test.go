@@ -0,0 +1,182 @@
+package uinput
+
+import "testing"
+
+func newTestTouchScreen(t *testing.T, slots int) *vTouchScreen {
+	return &vTouchScreen{deviceFile: &device{File: createTestEventFile(t)}, maxX: 1000, maxY: 800, slots: slots, active: make(map[int]bool)}
+}
+
+// splitFrames splits the given events into frames terminated by a SYN_REPORT, dropping the sync events.
+func splitFrames(t *testing.T, events []inputEvent) [][]inputEvent {
+	var frames [][]inputEvent
+	var frame []inputEvent
+	for _, ev := range events {
+		if ev.Type == evSyn && ev.Code == synReport {
+			frames = append(frames, frame)
+			frame = nil
+			continue
+		}
+		frame = append(frame, ev)
+	}
+	if len(frame) != 0 {
+		t.Fatalf("Expected all events to be terminated by a sync, but got %v", frame)
+	}
+	return frames
+}
+
+// slotPositions returns the x positions reported per slot within the given frames.
+func slotPositions(frames [][]inputEvent) map[int32][]int32 {
+	positions := make(map[int32][]int32)
+	var slot int32
+	for _, frame := range frames {
+		for _, ev := range frame {
+			if ev.Type != evAbs {
+				continue
+			}
+			switch ev.Code {
+			case absMTSlot:
+				slot = ev.Value
+			case absMTPositionX:
+				positions[slot] = append(positions[slot], ev.Value)
+			}
+		}
+	}
+	return positions
+}
+
+func TestTwoFingerScrollMovesBothContactsWithinFrames(t *testing.T) {
+	vs := newTestTouchScreen(t, 2)
+	defer vs.deviceFile.Close()
+
+	err := vs.TwoFingerScroll(0, 200)
+	if err != nil {
+		t.Fatalf("Failed to perform gesture: %v", err)
+	}
+
+	frames := splitFrames(t, readTestEvents(t, vs.deviceFile.File))
+	if len(frames) != gestureSteps+2 {
+		t.Fatalf("Expected %d frames, but got %d", gestureSteps+2, len(frames))
+	}
+	var trackingIDs, lifted int
+	for _, ev := range frames[0] {
+		if ev.Code == absMTTrackingID && ev.Value >= 0 {
+			trackingIDs++
+		}
+	}
+	for _, ev := range frames[len(frames)-1] {
+		if ev.Code == absMTTrackingID && ev.Value == -1 {
+			lifted++
+		}
+	}
+	if trackingIDs != 2 || lifted != 2 {
+		t.Fatalf("Expected both contacts to be put down in the first and lifted in the last frame, but got %d and %d", trackingIDs, lifted)
+	}
+
+	var y []int32
+	var slot int32
+	for _, frame := range frames {
+		for _, ev := range frame {
+			if ev.Code == absMTSlot {
+				slot = ev.Value
+			}
+			if ev.Code == absMTPositionY && slot == 0 {
+				y = append(y, ev.Value)
+			}
+		}
+	}
+	if y[len(y)-1]-y[0] != 200 {
+		t.Fatalf("Expected contact to move by 200, but it moved from %d to %d", y[0], y[len(y)-1])
+	}
+	for i := 1; i < len(y); i++ {
+		if y[i]-y[i-1] > 200/gestureSteps+1 {
+			t.Fatalf("Expected contact to move in small steps, but it moved from %d to %d", y[i-1], y[i])
+		}
+	}
+	if len(vs.active) != 0 {
+		t.Fatalf("Expected all contacts to be lifted")
+	}
+}
+
+func TestPinchScalesDistanceBetweenContacts(t *testing.T) {
+	vs := newTestTouchScreen(t, 2)
+	defer vs.deviceFile.Close()
+
+	err := vs.Pinch(2)
+	if err != nil {
+		t.Fatalf("Failed to perform gesture: %v", err)
+	}
+
+	positions := slotPositions(splitFrames(t, readTestEvents(t, vs.deviceFile.File)))
+	first, second := positions[0], positions[1]
+	startDistance := second[0] - first[0]
+	endDistance := second[len(second)-1] - first[len(first)-1]
+	if startDistance != 250 || endDistance != 500 {
+		t.Fatalf("Expected distance to grow from 250 to 500, but got %d to %d", startDistance, endDistance)
+	}
+}
+
+func TestSwipeMovesAllContacts(t *testing.T) {
+	vs := newTestTouchScreen(t, 4)
+	defer vs.deviceFile.Close()
+
+	err := vs.Swipe(3, SwipeLeft)
+	if err != nil {
+		t.Fatalf("Failed to perform gesture: %v", err)
+	}
+
+	positions := slotPositions(splitFrames(t, readTestEvents(t, vs.deviceFile.File)))
+	if len(positions) != 3 {
+		t.Fatalf("Expected 3 contacts, but got %d", len(positions))
+	}
+	for slot, x := range positions {
+		if x[0]-x[len(x)-1] != 333 {
+			t.Fatalf("Expected contact in slot %d to move left by 333, but it moved from %d to %d", slot, x[0], x[len(x)-1])
+		}
+	}
+}
+
+func TestRotateTurnsContactsAroundCenter(t *testing.T) {
+	vs := newTestTouchScreen(t, 2)
+	defer vs.deviceFile.Close()
+
+	err := vs.Rotate(90)
+	if err != nil {
+		t.Fatalf("Failed to perform gesture: %v", err)
+	}
+
+	positions := slotPositions(splitFrames(t, readTestEvents(t, vs.deviceFile.File)))
+	first, second := positions[0], positions[1]
+	if first[0] != 300 || second[0] != 700 || first[len(first)-1] != 500 || second[len(second)-1] != 500 {
+		t.Fatalf("Expected contacts to turn from 300 and 700 to 500, but got %v and %v", first, second)
+	}
+}
+
+func TestGesturesFailOnInvalidUsage(t *testing.T) {
+	vs := newTestTouchScreen(t, 2)
+	defer vs.deviceFile.Close()
+
+	if err := vs.Swipe(3, SwipeUp); err == nil {
+		t.Fatalf("Expected swipe to fail if there are not enough slots")
+	}
+	if err := vs.Swipe(2, SwipeDirection(42)); err == nil {
+		t.Fatalf("Expected swipe to fail on an invalid direction")
+	}
+	if err := vs.Pinch(0); err == nil {
+		t.Fatalf("Expected pinch to fail on an invalid scale")
+	}
+	if err := vs.Rotate(0); err == nil {
+		t.Fatalf("Expected rotate to fail on an invalid angle")
+	}
+	if err := vs.TwoFingerScroll(0, 2000); err == nil {
+		t.Fatalf("Expected scroll to fail if it exceeds the screen area")
+	}
+	if err := vs.TouchDown(0, 10, 10); err != nil {
+		t.Fatalf("Failed to put down contact: %v", err)
+	}
+	if err := vs.TwoFingerScroll(0, 100); err == nil {
+		t.Fatalf("Expected scroll to fail while a contact is on the screen")
+	}
+	if len(readTestEvents(t, vs.deviceFile.File)) != 8 {
+		t.Fatalf("Expected failed gestures not to emit any events")
+	}
+}
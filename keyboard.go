@@ -1,9 +1,13 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
+	"time"
 )
 
 // A Keyboard is an key event output device. It is used to
@@ -21,112 +25,1274 @@ type Keyboard interface {
 	// The key can be any of the predefined keycodes from keycodes.go.
 	KeyUp(key int) error
 
+	// PressedKeys will return the keys currently held down by the device, in ascending order.
+	PressedKeys() []int
+
+	// IsPressed will report whether the given key is currently held down by the device.
+	IsPressed(key int) bool
+
+	// KeyPressAndWaitLED will issue a single key press and wait for the host to send back an LED event
+	// for the given LED (see LedNuml, LedCapsl, etc.). It returns false if no such event arrived within the timeout.
+	KeyPressAndWaitLED(key int, led int, timeout time.Duration) (bool, error)
+
+	// FetchLEDState will return the LED state (Num Lock, Caps Lock, Scroll Lock) last reported by the host.
+	FetchLEDState() (LEDState, error)
+
+	// EmitKeyEvents will send the given key events to the device and terminate them with a single sync event, which
+	// allows to send several key events within a single frame.
+	EmitKeyEvents(events []KeyRaw) error
+
+	// Type will type the given text, using the keyboard layout configured with WithLayout (LayoutUS by default).
+	Type(text string) error
+
+	// TypeWithLayout will type the given text, using the given keyboard layout.
+	TypeWithLayout(text string, layout Layout) error
+
+	// TypeRune will type a single character, using the keyboard layout configured with WithLayout.
+	TypeRune(r rune) error
+
+	// TypeHuman will type the given text with randomized timing and occasional corrected typos, like a human would.
+	TypeHuman(text string, opts ...TypingOption) error
+
+	// TypeCtx will type the given text just like Type, but stops typing once the given context is done.
+	TypeCtx(ctx context.Context, text string) error
+
+	// TypeToWindow will focus the window with the given title using the WindowFocuser set with WithWindowFocuser and
+	// type the given text.
+	TypeToWindow(windowTitle string, text string) error
+
+	// TypeVerified will type the given text character by character, reading each character back from the event node of
+	// the device and typing it again if it was not read back.
+	TypeVerified(text string) error
+
+	// TypeCompose will press the compose key followed by the given sequence of keys, in order to type a composed
+	// character (e.g. KeyApostrophe and KeyE for é).
+	TypeCompose(sequence ...int) error
+
+	// KeyCombo will press the given keys at once and release them in reverse order (e.g. KeyLeftctrl, KeyLeftalt,
+	// KeyDelete).
+	KeyCombo(keys ...int) error
+
+	// SwitchVT will issue the Ctrl+Alt+F<n> combination in order to switch to virtual terminal n (1-12).
+	SwitchVT(n int) error
+
+	// SelectWord will issue the shortcut that extends the selection to the end of the next word in the given context.
+	SelectWord(ctx EditingContext) error
+
+	// SelectToLineEnd will issue the shortcut that extends the selection to the end of the line in the given context.
+	SelectToLineEnd(ctx EditingContext) error
+
+	// MagicSysRq will issue the "magic SysRq" key sequence Alt+SysRq+<command> for the given command character.
+	MagicSysRq(command byte) error
+
+	// SetRepeat will set the delay before a held key starts repeating and the period between repeats, both in
+	// milliseconds. Key repeat needs to be enabled upon creation using WithKeyRepeat.
+	SetRepeat(delayMs int, periodMs int) error
+
+	// Grab will grab the event node of the device, so that its events will no longer be delivered to any other
+	// consumer until Ungrab is called.
+	Grab() error
+
+	// Ungrab will release a grab obtained by Grab.
+	Ungrab() error
+
+	// MeasureLatency will issue a single key press and return the time it took until the event could be read back from
+	// the event node of the device.
+	MeasureLatency(key int) (time.Duration, error)
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// Batch returns an empty batch, which allows to send several events using a single write (see EventBatch).
+	Batch() *EventBatch
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
 	io.Closer
 }
 
+// LEDState holds the state of the keyboard LEDs, as reported by the host.
+type LEDState struct {
+	NumLock    bool
+	CapsLock   bool
+	ScrollLock bool
+}
+
+// KeyRaw is a single key event, consisting of the key code and its value (0 for release, 1 for press and 2 for repeat).
+type KeyRaw struct {
+	Code  uint16
+	Value int32
+}
+
 type vKeyboard struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *device
+	options    deviceOptions
+	pressed    map[int]bool
+	eventFile  *os.File
+	leds       LEDState
+	onClose    closeHooks
+	mu         deviceMutex
 }
 
 // CreateKeyboard will create a new keyboard using the given uinput
-// device path of the uinput device.
-func CreateKeyboard(path string, name []byte) (Keyboard, error) {
+// device path of the uinput device. Optional device properties, like the bus type, may be set using DeviceOptions.
+func CreateKeyboard(path string, name []byte, opts ...DeviceOption) (Keyboard, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
 	}
-	err = validateUinputName(name)
+	return createKeyboard(path, name, opts)
+}
+
+// CreateKeyboardFromFile will create a new keyboard just like CreateKeyboard, but uses the given uinput device file,
+// which has been opened by the caller already (e.g. a file descriptor passed by a privileged helper). The keyboard
+// takes ownership of the file, which is closed once the keyboard is closed or if creating the keyboard fails.
+func CreateKeyboardFromFile(file *os.File, name []byte, opts ...DeviceOption) (Keyboard, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createKeyboard(file.Name(), name, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createKeyboard(path string, name []byte, opts []DeviceOption) (Keyboard, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	fd, err := createVKeyboardDevice(path, name)
+	err = validateKeys(options.keys)
 	if err != nil {
 		return nil, err
 	}
 
-	return vKeyboard{name: name, deviceFile: fd}, nil
+	fd, err := createVKeyboardDevice(path, name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	vk := &vKeyboard{name: name, deviceFile: fd, options: options, pressed: make(map[int]bool), mu: newDeviceMutex(options)}
+	if options.keyRepeat {
+		err = vk.SetRepeat(int(options.repeatDelay/time.Millisecond), int(options.repeatPeriod/time.Millisecond))
+		if err != nil {
+			_ = closeDevice(fd)
+			return nil, err
+		}
+	}
+	return vk, nil
+}
+
+// NewKeyboard is the same as CreateKeyboard, but takes the name as a string, which is truncated if it exceeds 80 bytes
+// (see WithNameTruncation).
+func NewKeyboard(path string, name string, opts ...DeviceOption) (Keyboard, error) {
+	return CreateKeyboard(path, []byte(name), withStringName(opts)...)
 }
 
 // KeyPress will issue a single key press (push down a key and then immediately release it).
-func (vk vKeyboard) KeyPress(key int) error {
+func (vk *vKeyboard) KeyPress(key int) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.keyPress(key)
+}
+
+// keyPress performs KeyPress without locking the device, so that composite operations are able to hold the lock
+// throughout.
+func (vk *vKeyboard) keyPress(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyPress. Code %d is not in range", key)
+		return sentinelErrorf(ErrKeyOutOfRange, "failed to perform KeyPress. Code %d is not in range", key)
+	}
+	accepted, err := vk.acceptKeyDown(vk.pressed, key)
+	if err != nil || !accepted {
+		return err
 	}
-	err := sendBtnEvent(vk.deviceFile, []int{key}, btnStatePressed)
+	err = vk.sendKeyEvent([]int{key}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the KeyDown event: %v", err)
+		return fmt.Errorf("failed to issue the KeyDown event: %w", err)
 	}
 
-	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
+	return vk.sendKeyEvent([]int{key}, btnStateReleased)
 }
 
 // KeyDown will send the key code passed (see keycodes.go for available keycodes). Note that unless a key release
 // event is sent to the device, the key will remain pressed and therefore input will continuously be generated. Therefore,
 // do not forget to call "KeyUp" afterwards.
-func (vk vKeyboard) KeyDown(key int) error {
+func (vk *vKeyboard) KeyDown(key int) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.keyDown(key)
+}
+
+// keyDown performs KeyDown without locking the device.
+func (vk *vKeyboard) keyDown(key int) error {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyDown. Code %d is not in range", key)
+		return sentinelErrorf(ErrKeyOutOfRange, "failed to perform KeyDown. Code %d is not in range", key)
 	}
-	return sendBtnEvent(vk.deviceFile, []int{key}, btnStatePressed)
+	accepted, err := vk.acceptKeyDown(vk.pressed, key)
+	if err != nil || !accepted {
+		return err
+	}
+	err = vk.sendKeyEvent([]int{key}, btnStatePressed)
+	if err != nil {
+		return err
+	}
+	vk.pressed[key] = true
+	return nil
 }
 
 // KeyUp will release the given key passed as a parameter (see keycodes.go for available keycodes). In most
 // cases it is recommended to call this function immediately after the "KeyDown" function in order to only issue a
 // single key press.
-func (vk vKeyboard) KeyUp(key int) error {
+func (vk *vKeyboard) KeyUp(key int) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.keyUp(key)
+}
+
+// keyUp performs KeyUp without locking the device.
+func (vk *vKeyboard) keyUp(key int) error {
+	if !keyCodeInRange(key) {
+		return sentinelErrorf(ErrKeyOutOfRange, "failed to perform KeyUp. Code %d is not in range", key)
+	}
+
+	err := vk.sendKeyEvent([]int{key}, btnStateReleased)
+	if err != nil {
+		return err
+	}
+	delete(vk.pressed, key)
+	return nil
+}
+
+// PressedKeys will return the keys that have been pressed using KeyDown or EmitKeyEvents, but not released yet, in
+// ascending order. Keys pressed using SendRawEvent or a Batch are not tracked.
+func (vk *vKeyboard) PressedKeys() []int {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	keys := make([]int, 0, len(vk.pressed))
+	for key := range vk.pressed {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// IsPressed will report whether the given key has been pressed using KeyDown or EmitKeyEvents, but not released yet.
+func (vk *vKeyboard) IsPressed(key int) bool {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.pressed[key]
+}
+
+// KeyPressAndWaitLED will issue a single key press and wait for the host to answer with an LED event for the given LED.
+// This is useful to test software that reacts to key events by changing the LED state (e.g. a daemon toggling NumLock).
+// LED events the host sent before the key press are applied to the LED state first, so that only an answer to the key
+// press is reported. Note that other events sent back by the host are discarded while waiting.
+func (vk *vKeyboard) KeyPressAndWaitLED(key int, led int, timeout time.Duration) (bool, error) {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+
+	err := vk.processHostEvents()
+	if err != nil {
+		return false, fmt.Errorf("failed to wait for LED event: %w", err)
+	}
+	err = vk.keyPress(key)
+	if err != nil {
+		return false, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ev, ok, err := readEvent(vk.deviceFile.File, time.Until(deadline))
+		if err != nil {
+			return false, fmt.Errorf("failed to wait for LED event: %w", err)
+		}
+		if !ok {
+			return false, nil
+		}
+		vk.applyHostEvent(ev)
+		if ev.Type == evLed && ev.Code == uint16(led) {
+			return true, nil
+		}
+	}
+}
+
+// FetchLEDState will process all LED events the host has sent to the device so far and return the resulting LED state,
+// which allows to track whether Caps Lock or Num Lock is active on the host. The host sends LED events whenever the
+// state changes, as well as once the device has been opened by the host. The state is all off until the host reported
+// otherwise.
+func (vk *vKeyboard) FetchLEDState() (LEDState, error) {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	err := vk.processHostEvents()
+	if err != nil {
+		return vk.leds, fmt.Errorf("failed to fetch LED state: %w", err)
+	}
+	return vk.leds, nil
+}
+
+// processHostEvents applies all events the host has sent to the device so far, without waiting for further events.
+// The device needs to be locked by the caller.
+func (vk *vKeyboard) processHostEvents() error {
+	for {
+		ev, ok, err := readEvent(vk.deviceFile.File, 0)
+		if err != nil || !ok {
+			return err
+		}
+		vk.applyHostEvent(ev)
+	}
+}
+
+// applyHostEvent updates the tracked state according to an event sent back to the device by the host.
+func (vk *vKeyboard) applyHostEvent(ev inputEvent) {
+	if ev.Type != evLed {
+		return
+	}
+	on := ev.Value != 0
+	switch ev.Code {
+	case LedNuml:
+		vk.leds.NumLock = on
+	case LedCapsl:
+		vk.leds.CapsLock = on
+	case LedScrolll:
+		vk.leds.ScrollLock = on
+	}
+}
+
+// EmitKeyEvents will send the given key events in the given order and terminate them with a single sync event, so that
+// they are reported as a single frame (e.g. two keys pressed and another one released at the same time). All key codes
+// are validated before any event is sent. Key presses are subject to the limit set by WithMaxSimultaneousKeys just like
+// KeyDown: either nothing is sent or the presses beyond the limit are dropped (see WithSilentKeyDrop).
+func (vk *vKeyboard) EmitKeyEvents(events []KeyRaw) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.emitKeyEvents(events)
+}
+
+// emitKeyEvents performs EmitKeyEvents without locking the device.
+func (vk *vKeyboard) emitKeyEvents(events []KeyRaw) error {
+	pressed := make(map[int]bool, len(vk.pressed))
+	for key := range vk.pressed {
+		pressed[key] = true
+	}
+
+	accepted := make([]KeyRaw, 0, len(events))
+	for _, ev := range events {
+		key := int(ev.Code)
+		if !keyCodeInRange(key) {
+			return sentinelErrorf(ErrKeyOutOfRange, "failed to perform EmitKeyEvents. Code %d is not in range", ev.Code)
+		}
+
+		if ev.Value == btnStateReleased {
+			delete(pressed, key)
+			accepted = append(accepted, ev)
+			continue
+		}
+		ok, err := vk.acceptKeyDown(pressed, key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			pressed[key] = true
+			accepted = append(accepted, ev)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil
+	}
+
+	for _, ev := range accepted {
+		err := vk.writeKeyEvent(ev.Code, ev.Value)
+		if err != nil {
+			return err
+		}
+
+		if ev.Value == btnStateReleased {
+			delete(vk.pressed, int(ev.Code))
+		} else {
+			vk.pressed[int(ev.Code)] = true
+		}
+	}
+	vk.preSyncDelay()
+	return syncEvents(vk.deviceFile)
+}
+
+// Type will type the given text by translating each character into key strokes, using the keyboard layout that was
+// configured upon creation (see WithLayout). Upper case letters and symbols are typed by holding down the respective
+// modifier (e.g. shift). All characters are translated before any event is sent, so nothing is typed if the text
+// contains a character that is not supported by the layout.
+func (vk *vKeyboard) Type(text string) error {
+	return vk.TypeWithLayout(text, vk.options.layout)
+}
+
+// TypeWithLayout will type the given text just like Type does, but uses the given layout instead of the one configured
+// upon creation. This is useful if the layout is switched at runtime.
+func (vk *vKeyboard) TypeWithLayout(text string, layout Layout) error {
+	return vk.typeWithLayout(context.Background(), text, layout)
+}
+
+// TypeCtx will type the given text just like Type does, but stops typing once the given context is done, in which case
+// the error of the context is returned. Since each character is released before the next one is typed, no keys are
+// left pressed when typing is stopped.
+func (vk *vKeyboard) TypeCtx(ctx context.Context, text string) error {
+	return vk.typeWithLayout(ctx, text, vk.options.layout)
+}
+
+// TypeRune will type a single character, using the keyboard layout configured with WithLayout. Characters that need a
+// modifier (like Shift for upper case letters or AltGr for @ on a German keyboard) are typed while holding down the
+// modifier, characters on dead keys are followed by a space.
+func (vk *vKeyboard) TypeRune(r rune) error {
+	if vk.options.layout == nil {
+		return fmt.Errorf("failed to perform TypeRune. The keyboard layout must not be nil")
+	}
+	shortcuts, err := vk.runeShortcuts("TypeRune", vk.options.layout, r)
+	if err != nil {
+		return err
+	}
+	return vk.typeShortcuts(context.Background(), shortcuts)
+}
+
+func (vk *vKeyboard) typeWithLayout(ctx context.Context, text string, layout Layout) error {
+	if layout == nil {
+		return fmt.Errorf("failed to perform Type. The keyboard layout must not be nil")
+	}
+	var shortcuts []shortcut
+	for _, r := range text {
+		s, err := vk.runeShortcuts("Type", layout, r)
+		if err != nil {
+			return err
+		}
+		shortcuts = append(shortcuts, s...)
+	}
+	return vk.typeShortcuts(ctx, shortcuts)
+}
+
+// runeShortcuts resolves the key strokes typing the given character using the layout. Characters the layout does not
+// support are typed as a Unicode entry sequence if enabled using WithUnicodeInput. The operation is used for the error
+// messages.
+func (vk *vKeyboard) runeShortcuts(operation string, layout Layout, r rune) ([]shortcut, error) {
+	if _, ok := layout.KeyStrokes(r); !ok && vk.options.unicodeInput {
+		return unicodeEntryShortcuts(operation, layout, r)
+	}
+	strokes, err := layoutStrokes(operation, layout, r)
+	if err != nil {
+		return nil, err
+	}
+	return strokeShortcuts(strokes), nil
+}
+
+// unicodeEntryShortcuts returns the sequence that enters the given character by its code point using IBus or GTK:
+// Ctrl+Shift+U, followed by the hexadecimal code point and a space to finish the entry. The digits are typed using the
+// layout, since they need a modifier on some layouts.
+func unicodeEntryShortcuts(operation string, layout Layout, r rune) ([]shortcut, error) {
+	shortcuts := []shortcut{{modifiers: []int{KeyLeftctrl, KeyLeftshift}, key: KeyU}}
+	for _, digit := range strconv.FormatInt(int64(r), 16) {
+		strokes, err := layoutStrokes(operation, layout, digit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to enter character %q by its code point: %w", r, err)
+		}
+		shortcuts = append(shortcuts, strokeShortcuts(strokes)...)
+	}
+	return append(shortcuts, shortcut{key: KeySpace}), nil
+}
+
+// layoutStrokes resolves the key strokes typing the given character using the layout, validating the keys of each
+// stroke.
+func layoutStrokes(operation string, layout Layout, r rune) ([]KeyStroke, error) {
+	strokes, ok := layout.KeyStrokes(r)
+	if !ok {
+		return nil, fmt.Errorf("failed to perform %s. Character %q is not supported by the keyboard layout", operation, r)
+	}
+	for _, stroke := range strokes {
+		if !keyCodeInRange(stroke.Key) || (stroke.Modifier != 0 && !keyCodeInRange(stroke.Modifier)) {
+			return nil, sentinelErrorf(ErrKeyOutOfRange, "failed to perform %s. Key stroke %+v for character %q is not in range", operation, stroke, r)
+		}
+	}
+	return strokes, nil
+}
+
+func strokeShortcuts(strokes []KeyStroke) []shortcut {
+	shortcuts := make([]shortcut, len(strokes))
+	for i, stroke := range strokes {
+		shortcuts[i].key = stroke.Key
+		if stroke.Modifier != 0 {
+			shortcuts[i].modifiers = []int{stroke.Modifier}
+		}
+	}
+	return shortcuts
+}
+
+// typeShortcuts presses the given shortcuts in order. The device is locked until all of them have been pressed, so that
+// the typed text is not interleaved with the events of concurrent calls.
+func (vk *vKeyboard) typeShortcuts(ctx context.Context, shortcuts []shortcut) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	for _, sc := range shortcuts {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+		if len(sc.modifiers) == 0 {
+			err = vk.keyPress(sc.key)
+		} else {
+			err = vk.pressShortcut(sc)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to type key %d: %w", sc.key, err)
+		}
+	}
+	return nil
+}
+
+// TypeCompose will press and release the compose key (KeyCompose, unless configured otherwise using WithComposeKey)
+// followed by each of the given keys, in order to type a composed character. For example, KeyApostrophe followed by
+// KeyE will result in é on most systems. All keys are validated before any event is sent.
+// Note that this requires a compose key to be configured in the OS (e.g. using the XKB option "compose:menu"), which
+// also determines the sequences that are available.
+func (vk *vKeyboard) TypeCompose(sequence ...int) error {
+	if len(sequence) == 0 {
+		return fmt.Errorf("failed to perform TypeCompose. The sequence must not be empty")
+	}
+	for _, key := range append([]int{vk.options.composeKey}, sequence...) {
+		if !keyCodeInRange(key) {
+			return sentinelErrorf(ErrKeyOutOfRange, "failed to perform TypeCompose. Code %d is not in range", key)
+		}
+	}
+
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	err := vk.keyPress(vk.options.composeKey)
+	if err != nil {
+		return fmt.Errorf("failed to press compose key: %w", err)
+	}
+	for _, key := range sequence {
+		err = vk.keyPress(key)
+		if err != nil {
+			return fmt.Errorf("failed to press key %d of compose sequence: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// KeyCombo will press the given keys in the given order within a single frame and release them in reverse order
+// within another frame, just like a key combination like Ctrl+Shift+T is usually performed. All keys are validated
+// before any event is sent.
+func (vk *vKeyboard) KeyCombo(keys ...int) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("failed to perform KeyCombo. At least one key is required")
+	}
+	press := make([]KeyRaw, len(keys))
+	release := make([]KeyRaw, len(keys))
+	for i, key := range keys {
+		if !keyCodeInRange(key) {
+			return sentinelErrorf(ErrKeyOutOfRange, "failed to perform KeyCombo. Code %d is not in range", key)
+		}
+		press[i] = KeyRaw{uint16(key), btnStatePressed}
+		release[len(keys)-1-i] = KeyRaw{uint16(key), btnStateReleased}
+	}
+
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	err := vk.emitKeyEvents(press)
+	if err != nil {
+		return fmt.Errorf("failed to press key combination: %w", err)
+	}
+	err = vk.emitKeyEvents(release)
+	if err != nil {
+		return fmt.Errorf("failed to release key combination: %w", err)
+	}
+	return nil
+}
+
+// SwitchVT will issue the Ctrl+Alt+F<n> combination that switches to the virtual terminal n, where n is a value between
+// 1 and 12. Ctrl, Alt and the function key are pressed within a single frame and released in reverse order.
+func (vk *vKeyboard) SwitchVT(n int) error {
+	if n < 1 || n > len(functionKeys) {
+		return fmt.Errorf("failed to perform SwitchVT. Terminal %d is out of range (1-%d)", n, len(functionKeys))
+	}
+	return vk.KeyCombo(KeyLeftctrl, KeyLeftalt, functionKeys[n-1])
+}
+
+// SelectWord will issue the shortcut that extends the selection to the end of the next word, which is Ctrl+Shift+Right
+// in most editors and Alt+Shift+Right on macOS. Selecting text using keyboard shortcuts is not possible in a terminal,
+// so an error is returned for EditingContextTerminal.
+func (vk *vKeyboard) SelectWord(ctx EditingContext) error {
+	shortcuts, err := editingShortcutsFor(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to perform SelectWord: %w", err)
+	}
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.pressShortcut(shortcuts.selectWord)
+}
+
+// SelectToLineEnd will issue the shortcut that extends the selection to the end of the line, which is Shift+End in most
+// editors and Meta+Shift+Right on macOS. Selecting text using keyboard shortcuts is not possible in a terminal, so an
+// error is returned for EditingContextTerminal.
+func (vk *vKeyboard) SelectToLineEnd(ctx EditingContext) error {
+	shortcuts, err := editingShortcutsFor(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to perform SelectToLineEnd: %w", err)
+	}
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return vk.pressShortcut(shortcuts.selectToLineEnd)
+}
+
+// SetRepeat will set the delay before a held key starts repeating and the period between repeats, both in milliseconds.
+// The repeat events are generated by the kernel, just like for a real keyboard. This fails, unless key repeat has been
+// enabled upon creation using WithKeyRepeat.
+func (vk *vKeyboard) SetRepeat(delayMs int, periodMs int) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	if !vk.options.keyRepeat {
+		return fmt.Errorf("failed to set key repeat. Key repeat needs to be enabled using WithKeyRepeat")
+	}
+	if delayMs <= 0 || periodMs <= 0 {
+		return fmt.Errorf("invalid key repeat delay %dms and period %dms. Expected positive values", delayMs, periodMs)
+	}
+
+	err := sendRawEvent(vk.deviceFile, evRep, repDelay, int32(delayMs))
+	if err != nil {
+		return fmt.Errorf("failed to set key repeat delay: %w", err)
+	}
+	err = sendRawEvent(vk.deviceFile, evRep, repPeriod, int32(periodMs))
+	if err != nil {
+		return fmt.Errorf("failed to set key repeat period: %w", err)
+	}
+	return syncEvents(vk.deviceFile)
+}
+
+// MagicSysRq will issue the "magic SysRq" sequence for the given command (e.g. 'h' to print the SysRq help to the
+// kernel log). Left Alt is held down, SysRq is pressed and the command key is pressed and released, before SysRq and
+// Alt are released again. Valid commands are the characters a-z and 0-9.
+// Note that the kernel will only act upon the sequence if sysrq is enabled (see /proc/sys/kernel/sysrq). Be careful
+// when issuing commands, as some of them will immediately reboot or power off the machine.
+func (vk *vKeyboard) MagicSysRq(command byte) error {
+	key, ok := sysRqCommandKeys[command]
+	if !ok {
+		return fmt.Errorf("failed to perform MagicSysRq. Command %q is not supported", command)
+	}
+
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	err := vk.keyDown(KeyLeftalt)
+	if err != nil {
+		return fmt.Errorf("failed to press alt key: %w", err)
+	}
+	err = vk.keyDown(KeySysrq)
+	if err != nil {
+		_ = vk.keyUp(KeyLeftalt)
+		return fmt.Errorf("failed to press sysrq key: %w", err)
+	}
+
+	err = vk.keyPress(key)
+	if err != nil {
+		err = fmt.Errorf("failed to press sysrq command key: %w", err)
+	}
+	if releaseErr := vk.keyUp(KeySysrq); releaseErr != nil && err == nil {
+		err = fmt.Errorf("failed to release sysrq key: %w", releaseErr)
+	}
+	if releaseErr := vk.keyUp(KeyLeftalt); releaseErr != nil && err == nil {
+		err = fmt.Errorf("failed to release alt key: %w", releaseErr)
+	}
+	return err
+}
+
+// Grab will exclusively grab the event node (/dev/input/eventX) of the device (see EVIOCGRAB). While the device is
+// grabbed, its events are only delivered to this package, which allows to read them back (e.g. for self-tests or
+// feedback loops) without other consumers stealing them. Note that this also means that other applications (including
+// X11 or Wayland compositors) will not see any of the events until Ungrab is called.
+func (vk *vKeyboard) Grab() error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	if vk.eventFile != nil {
+		return fmt.Errorf("failed to grab device. The device is already grabbed")
+	}
+	eventFile, err := grabEventNode(vk.deviceFile.File)
+	if err != nil {
+		return err
+	}
+	vk.eventFile = eventFile
+	return nil
+}
+
+// Ungrab will release the grab obtained by Grab, so that other consumers will receive the events of the device again.
+func (vk *vKeyboard) Ungrab() error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	if vk.eventFile == nil {
+		return fmt.Errorf("failed to release grab. The device is not grabbed")
+	}
+	eventFile := vk.eventFile
+	vk.eventFile = nil
+	return ungrabEventNode(eventFile)
+}
+
+// MeasureLatency will issue a single key press and measure the round-trip time from writing the key down event to the
+// device until reading it back from the event node of the device. The key down event is written within a single frame
+// once the rate limit of the device allows (see WithMaxEventRate), so that neither the pacing nor the release of the
+// key are part of the measurement. If the device is not grabbed already, it will be grabbed for the duration of the
+// measurement, so that the key press is not delivered to any other consumer.
+func (vk *vKeyboard) MeasureLatency(key int) (time.Duration, error) {
 	if !keyCodeInRange(key) {
-		return fmt.Errorf("failed to perform KeyUp. Code %d is not in range", key)
+		return 0, sentinelErrorf(ErrKeyOutOfRange, "failed to perform MeasureLatency. Code %d is not in range", key)
+	}
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	if vk.pressed[key] {
+		return 0, fmt.Errorf("failed to perform MeasureLatency. Key %d is held down already", key)
+	}
+	eventFile := vk.eventFile
+	if eventFile == nil {
+		var err error
+		eventFile, err = grabEventNode(vk.deviceFile.File)
+		if err != nil {
+			return 0, err
+		}
+		defer ungrabEventNode(eventFile)
 	}
 
-	return sendBtnEvent(vk.deviceFile, []int{key}, btnStateReleased)
+	var buf []byte
+	for _, ev := range keyEvents(uint16(key), btnStatePressed) {
+		buf = appendInputEvent(buf, ev)
+	}
+	buf = appendInputEvent(buf, inputEvent{Type: evSyn, Code: synReport})
+	err := prepareEvents(vk.deviceFile, buf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to issue the KeyDown event: %w", err)
+	}
+	start := time.Now()
+	_, err = writePreparedEvents(vk.deviceFile, buf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to issue the KeyDown event: %w", err)
+	}
+	latency, err := readBackKeyDown(eventFile, key, start)
+
+	releaseErr := vk.sendKeyEvent([]int{key}, btnStateReleased)
+	if err == nil && releaseErr != nil {
+		err = fmt.Errorf("failed to issue the KeyUp event: %w", releaseErr)
+	}
+	return latency, err
 }
 
-// Close will close the device and free resources.
+// readBackKeyDown reads events from the event node until the key down event of the given key is read back, and returns
+// the time that passed since the given start.
+func readBackKeyDown(eventFile *os.File, key int, start time.Time) (time.Duration, error) {
+	deadline := start.Add(latencyTimeout)
+	for {
+		ev, ok, err := readEvent(eventFile, time.Until(deadline))
+		if err != nil {
+			return 0, fmt.Errorf("failed to read back key event: %w", err)
+		}
+		if !ok {
+			return 0, fmt.Errorf("failed to read back key event within %v", latencyTimeout)
+		}
+		if ev.Type == evKey && ev.Code == uint16(key) && ev.Value == btnStatePressed {
+			return time.Since(start), nil
+		}
+	}
+}
+
+// TypeVerified will type the given text just like Type does, but reads each character back from the event node of the
+// device before typing the next one. A character whose key presses are not read back within a short timeout is typed
+// again, up to three times in total. This is slower than Type, but more reliable on systems that occasionally drop
+// events. The events are read from the grabbed event node if the device has been grabbed using Grab (in which case no
+// other consumer receives the text), otherwise the event node is opened for the duration of the call.
+// Note that a character whose events were delivered, but not read back in time, ends up being typed twice.
+func (vk *vKeyboard) TypeVerified(text string) error {
+	layout := vk.options.layout
+	if layout == nil {
+		return fmt.Errorf("failed to perform TypeVerified. The keyboard layout must not be nil")
+	}
+	var chars [][]shortcut
+	for _, r := range text {
+		s, err := vk.runeShortcuts("TypeVerified", layout, r)
+		if err != nil {
+			return err
+		}
+		chars = append(chars, s)
+	}
+
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	eventFile := vk.eventFile
+	if eventFile == nil {
+		var err error
+		eventFile, err = openEventNode(vk.deviceFile.File)
+		if err != nil {
+			return fmt.Errorf("failed to perform TypeVerified: %w", err)
+		}
+		defer eventFile.Close()
+	}
+
+	runes := []rune(text)
+	for i, shortcuts := range chars {
+		err := vk.typeVerifiedRune(eventFile, shortcuts)
+		if err != nil {
+			return fmt.Errorf("failed to type character %q: %w", runes[i], err)
+		}
+	}
+	return nil
+}
+
+// typeVerifiedRune types the shortcuts of a single character until their key presses are read back from the event
+// file.
+func (vk *vKeyboard) typeVerifiedRune(eventFile *os.File, shortcuts []shortcut) error {
+	var expected []uint16
+	for _, sc := range shortcuts {
+		for _, modifier := range sc.modifiers {
+			expected = append(expected, uint16(modifier))
+		}
+		expected = append(expected, uint16(sc.key))
+	}
+
+	for attempt := 1; ; attempt++ {
+		for _, sc := range shortcuts {
+			var err error
+			if len(sc.modifiers) == 0 {
+				err = vk.keyPress(sc.key)
+			} else {
+				err = vk.pressShortcut(sc)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		ok, err := readBackKeyPresses(eventFile, expected, verifyTimeout)
+		if err != nil || ok {
+			return err
+		}
+		if attempt == verifyAttempts {
+			return fmt.Errorf("key presses could not be read back after %d attempts", verifyAttempts)
+		}
+		// discard the rest of the failed attempt, so that it is not mistaken for the next one
+		err = drainEvents(eventFile)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readBackKeyPresses reads events from the event file until the given number of key presses has been read, and reports
+// whether they match the expected keys. Key presses that are missing after the timeout count as a mismatch.
+func readBackKeyPresses(eventFile *os.File, expected []uint16, timeout time.Duration) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	for i := 0; i < len(expected); {
+		ev, ok, err := readEvent(eventFile, time.Until(deadline))
+		if err != nil {
+			return false, fmt.Errorf("failed to read back key event: %w", err)
+		}
+		if !ok {
+			return false, nil
+		}
+		if ev.Type != evKey || ev.Value != btnStatePressed {
+			continue
+		}
+		if ev.Code != expected[i] {
+			return false, nil
+		}
+		i++
+	}
+	return true, nil
+}
+
+// drainEvents discards all events that are pending on the event file.
+func drainEvents(eventFile *os.File) error {
+	for {
+		_, ok, err := readEvent(eventFile, 0)
+		if err != nil {
+			return fmt.Errorf("failed to read back key event: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// Close will close the device and free resources. Keys that are still held down are released first, if enabled using
+// WithReleaseOnClose.
 // It's usually a good idea to use defer to call this function.
-func (vk vKeyboard) Close() error {
-	return closeDevice(vk.deviceFile)
+func (vk *vKeyboard) Close() error {
+	vk.mu.Lock()
+	if vk.eventFile != nil {
+		// the grab is released first, so that other consumers see the release of the pressed keys
+		_ = ungrabEventNode(vk.eventFile)
+		vk.eventFile = nil
+	}
+	var releaseErr error
+	if vk.options.releaseOnClose {
+		releaseErr = vk.releasePressed()
+	}
+	vk.mu.Unlock()
+	err := closeDevice(vk.deviceFile)
+	vk.onClose.run()
+	if err == nil {
+		err = releaseErr
+	}
+	return err
 }
 
-func createVKeyboardDevice(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+// releasePressed releases all keys that are currently held down within a single frame.
+func (vk *vKeyboard) releasePressed() error {
+	if len(vk.pressed) == 0 {
+		return nil
+	}
+	var keys []int
+	for key := range vk.pressed {
+		keys = append(keys, key)
+	}
+	// release modifiers last, just like a user would do
+	sort.Slice(keys, func(i, j int) bool {
+		if isModifierKey(keys[i]) != isModifierKey(keys[j]) {
+			return !isModifierKey(keys[i])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		delete(vk.pressed, key)
+	}
+	err := vk.sendKeyEvent(keys, btnStateReleased)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create virtual keyboard device: %v", err)
+		return fmt.Errorf("failed to release held keys: %w", err)
+	}
+	return nil
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vk *vKeyboard) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	return sendRawEvent(vk.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vk *vKeyboard) Sync() error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	vk.preSyncDelay()
+	return syncEvents(vk.deviceFile)
+}
+
+// Batch returns an empty batch of events for the device.
+func (vk *vKeyboard) Batch() *EventBatch {
+	return newEventBatch(vk.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vk *vKeyboard) OnClose(callback func()) {
+	vk.onClose.add(callback)
+}
+
+func createVKeyboardDevice(path string, name []byte, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual keyboard device: %w", err)
 	}
 
 	err = registerDevice(deviceFile, uintptr(evKey))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register virtual keyboard device: %v", err)
+		return nil, fmt.Errorf("failed to register virtual keyboard device: %w", err)
+	}
+
+	// register key events (all keys, unless restricted using WithKeys)
+	keys := options.keys
+	if keys == nil {
+		keys = make([]int, keyMax+1)
+		for i := range keys {
+			keys[i] = i
+		}
+	}
+	for _, key := range keys {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(key))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register key number %d: %w", key, err)
+		}
+	}
+
+	// register scan code events, which are sent along with media keys
+	err = registerDevice(deviceFile, uintptr(evMsc))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register misc events: %w", err)
+	}
+	err = ioctl(deviceFile, uiSetMscBit, uintptr(mscScan))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register scan code event: %w", err)
+	}
+
+	// register key repeat, so that the kernel repeats held keys
+	if options.keyRepeat {
+		err = registerDevice(deviceFile, uintptr(evRep))
+		if err != nil {
+			deviceFile.Close()
+			return nil, fmt.Errorf("failed to register key repeat: %w", err)
+		}
+	}
+
+	// register LED events, so that the host is able to report LED state changes back to the device
+	err = registerDevice(deviceFile, uintptr(evLed))
+	if err != nil {
+		deviceFile.Close()
+		return nil, fmt.Errorf("failed to register led events: %w", err)
 	}
 
-	// register key events
-	for i := 0; i <= keyMax; i++ {
-		err = ioctl(deviceFile, uiSetKeyBit, uintptr(i))
+	for _, led := range []int{LedNuml, LedCapsl, LedScrolll} {
+		err = ioctl(deviceFile, uiSetLedBit, uintptr(led))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register key number %d: %v", i, err)
+			return nil, fmt.Errorf("failed to register led number %d: %w", led, err)
 		}
 	}
 
 	return createUsbDevice(deviceFile,
 		uinputUserDev{
 			Name: toUinputName(name),
-			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  0x4711,
-				Product: 0x0815,
-				Version: 1}})
+			ID:   options.inputID(0x0815)}, options)
+}
+
+// pressShortcut holds down the modifiers of the shortcut in order, presses the key and releases the modifiers in
+// reverse order. The modifiers are released even if pressing the key failed. The caller needs to hold the lock of the
+// device.
+func (vk *vKeyboard) pressShortcut(sc shortcut) error {
+	for i, modifier := range sc.modifiers {
+		err := vk.keyDown(modifier)
+		if err != nil {
+			err = fmt.Errorf("failed to press modifier key %d: %w", modifier, err)
+			vk.releaseModifiers(sc.modifiers[:i])
+			return err
+		}
+	}
+
+	err := vk.keyPress(sc.key)
+	if err != nil {
+		err = fmt.Errorf("failed to press shortcut key %d: %w", sc.key, err)
+		vk.releaseModifiers(sc.modifiers)
+		return err
+	}
+
+	for i := len(sc.modifiers) - 1; i >= 0; i-- {
+		err = vk.keyUp(sc.modifiers[i])
+		if err != nil {
+			return fmt.Errorf("failed to release modifier key %d: %w", sc.modifiers[i], err)
+		}
+	}
+	return nil
+}
+
+// releaseModifiers releases the given modifiers in reverse order, ignoring any errors. The caller needs to hold the lock
+// of the device.
+func (vk *vKeyboard) releaseModifiers(modifiers []int) {
+	for i := len(modifiers) - 1; i >= 0; i-- {
+		_ = vk.keyUp(modifiers[i])
+	}
+}
+
+// sendKeyEvent sends the key events and terminates them with a sync event, after waiting for the delay configured
+// using WithPreSyncDelay.
+func (vk *vKeyboard) sendKeyEvent(keys []int, btnState int) error {
+	for _, key := range keys {
+		err := vk.writeKeyEvent(uint16(key), int32(btnState))
+		if err != nil {
+			return err
+		}
+	}
+	vk.preSyncDelay()
+	return syncEvents(vk.deviceFile)
+}
+
+// writeKeyEvent writes a single key event without terminating it. Media keys are preceded by the scan code (MSC_SCAN)
+// a real keyboard would report, since some desktop environments ignore media keys without it.
+func (vk *vKeyboard) writeKeyEvent(code uint16, value int32) error {
+	for _, ev := range keyEvents(code, value) {
+		err := writeEvent(vk.deviceFile, ev)
+		if err != nil {
+			return fmt.Errorf("writing key event structure to the device file failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// keyEvents returns the events that report a key event, which includes the scan code for media keys.
+func keyEvents(code uint16, value int32) []inputEvent {
+	events := []inputEvent{{Type: evKey, Code: code, Value: value}}
+	if usage, ok := consumerUsages[int(code)]; ok {
+		events = append([]inputEvent{{Type: evMsc, Code: mscScan, Value: usage}}, events...)
+	}
+	return events
+}
+
+func (vk *vKeyboard) preSyncDelay() {
+	if vk.options.preSyncDelay > 0 {
+		time.Sleep(vk.options.preSyncDelay)
+	}
 }
 
+// acceptKeyDown checks whether another key may be pressed in addition to the given pressed keys without exceeding the
+// limit of simultaneously pressed keys (see WithMaxSimultaneousKeys). Keys that exceed the limit are either refused with
+// an error or dropped silently.
+func (vk *vKeyboard) acceptKeyDown(pressed map[int]bool, key int) (bool, error) {
+	if vk.options.maxKeys <= 0 || isModifierKey(key) || pressed[key] {
+		return true, nil
+	}
+
+	held := 0
+	for k := range pressed {
+		if !isModifierKey(k) {
+			held++
+		}
+	}
+	if held < vk.options.maxKeys {
+		return true, nil
+	}
+
+	if vk.options.silentKeyDrop {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to press key %d. A maximum of %d keys may be held down simultaneously", key, vk.options.maxKeys)
+}
+
+// latencyTimeout is the maximum time MeasureLatency waits for an event to be read back.
+const latencyTimeout = time.Second
+
+const (
+	// verifyTimeout is the maximum time TypeVerified waits for the key presses of a character to be read back.
+	verifyTimeout = 100 * time.Millisecond
+	// verifyAttempts is the number of times TypeVerified types a character, until it gives up.
+	verifyAttempts = 3
+)
+
+// EditingContext determines the keyboard shortcuts used by the text editing helpers (e.g. SelectWord), since the
+// shortcuts differ between platforms and applications.
+type EditingContext int
+
+const (
+	// EditingContextDefault uses the shortcuts common to most editors on Linux and Windows.
+	EditingContextDefault EditingContext = iota
+	// EditingContextMac uses the shortcuts of macOS applications (with Meta acting as the command key).
+	EditingContextMac
+	// EditingContextTerminal is a terminal (emulator), where text cannot be selected using keyboard shortcuts.
+	EditingContextTerminal
+)
+
+// shortcut is a key that is pressed while holding down the given modifiers.
+type shortcut struct {
+	modifiers []int
+	key       int
+}
+
+type editingShortcuts struct {
+	selectWord      shortcut
+	selectToLineEnd shortcut
+}
+
+var editingShortcutsByContext = map[EditingContext]editingShortcuts{
+	EditingContextDefault: {
+		selectWord:      shortcut{[]int{KeyLeftctrl, KeyLeftshift}, KeyRight},
+		selectToLineEnd: shortcut{[]int{KeyLeftshift}, KeyEnd},
+	},
+	EditingContextMac: {
+		selectWord:      shortcut{[]int{KeyLeftalt, KeyLeftshift}, KeyRight},
+		selectToLineEnd: shortcut{[]int{KeyLeftmeta, KeyLeftshift}, KeyRight},
+	},
+}
+
+func editingShortcutsFor(ctx EditingContext) (editingShortcuts, error) {
+	if ctx == EditingContextTerminal {
+		return editingShortcuts{}, fmt.Errorf("text cannot be selected using keyboard shortcuts in a terminal")
+	}
+	shortcuts, ok := editingShortcutsByContext[ctx]
+	if !ok {
+		return editingShortcuts{}, fmt.Errorf("unknown editing context %d", ctx)
+	}
+	return shortcuts, nil
+}
+
+// consumerUsages maps the media keys to the usages of the HID consumer page (0x0c), which real keyboards report as
+// scan code along with the key event.
+var consumerUsages = map[int]int32{
+	KeyMute:         0xc00e2,
+	KeyVolumeup:     0xc00e9,
+	KeyVolumedown:   0xc00ea,
+	KeyPlaycd:       0xc00b0,
+	KeyPausecd:      0xc00b1,
+	KeyRecord:       0xc00b2,
+	KeyFastforward:  0xc00b3,
+	KeyRewind:       0xc00b4,
+	KeyNextsong:     0xc00b5,
+	KeyPrevioussong: 0xc00b6,
+	KeyStopcd:       0xc00b7,
+	KeyEjectcd:      0xc00b8,
+	KeyPlaypause:    0xc00cd,
+}
+
+// functionKeys holds the function keys F1 to F12 in order.
+var functionKeys = []int{KeyF1, KeyF2, KeyF3, KeyF4, KeyF5, KeyF6, KeyF7, KeyF8, KeyF9, KeyF10, KeyF11, KeyF12}
+
+// sysRqCommandKeys maps the SysRq command characters to the keys that need to be pressed to issue them.
+var sysRqCommandKeys = map[byte]int{
+	'0': Key0, '1': Key1, '2': Key2, '3': Key3, '4': Key4, '5': Key5, '6': Key6, '7': Key7, '8': Key8, '9': Key9,
+	'a': KeyA, 'b': KeyB, 'c': KeyC, 'd': KeyD, 'e': KeyE, 'f': KeyF, 'g': KeyG, 'h': KeyH, 'i': KeyI, 'j': KeyJ,
+	'k': KeyK, 'l': KeyL, 'm': KeyM, 'n': KeyN, 'o': KeyO, 'p': KeyP, 'q': KeyQ, 'r': KeyR, 's': KeyS, 't': KeyT,
+	'u': KeyU, 'v': KeyV, 'w': KeyW, 'x': KeyX, 'y': KeyY, 'z': KeyZ,
+}
+
+func isModifierKey(key int) bool {
+	switch key {
+	case KeyLeftctrl, KeyRightctrl, KeyLeftshift, KeyRightshift, KeyLeftalt, KeyRightalt, KeyLeftmeta, KeyRightmeta:
+		return true
+	}
+	return false
+}
+
+// validateKeys checks the keys passed using WithKeys. A nil slice means that all keys are registered.
+func validateKeys(keys []int) error {
+	if keys == nil {
+		return nil
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("at least one key needs to be registered")
+	}
+	for _, key := range keys {
+		if !keyCodeInRange(key) {
+			return sentinelErrorf(ErrKeyOutOfRange, "key code %d is not in range", key)
+		}
+	}
+	return nil
+}
+
+// keyMax is the highest key supported by the keyboard api. Codes above it are buttons or keys that are registered
+// using other device types.
+const keyMax = KeyMicmute
+
 func keyCodeInRange(key int) bool {
 	return key >= keyReserved && key <= keyMax
 }
 
-func (vk vKeyboard) FetchSyspath() (string, error) {
-	return fetchSyspath(vk.deviceFile)
+func (vk *vKeyboard) FetchSyspath() (string, error) {
+	return lookupSyspath(vk.deviceFile.File, vk.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vk *vKeyboard) EventNode() (string, error) {
+	return lookupEventNode(vk.deviceFile.File, vk.name)
 }
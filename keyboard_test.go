@@ -1,10 +1,15 @@
 package uinput
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // This test will confirm that basic key events are working.
@@ -192,3 +197,1226 @@ func TestKeyboardSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestKeyboardWithI8042BusType(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Internal Keyboard"), WithBusType(BusI8042))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyPress(Key1)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+}
+
+func TestKeyPressAndWaitLED(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test LED Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	before, err := vk.FetchLEDState()
+	if err != nil {
+		t.Fatalf("Failed to fetch LED state. Last error was: %s\n", err)
+	}
+
+	toggled, err := vk.KeyPressAndWaitLED(KeyNumlock, LedNuml, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for LED event. Last error was: %s\n", err)
+	}
+	if !toggled {
+		// whether the host actually toggles the LED depends on the environment
+		t.Skip("Host did not answer the key press with an LED event")
+	}
+	after, err := vk.FetchLEDState()
+	if err != nil {
+		t.Fatalf("Failed to fetch LED state. Last error was: %s\n", err)
+	}
+	if after.NumLock == before.NumLock {
+		t.Fatalf("Expected NumLock state to change from %v, but it did not", before.NumLock)
+	}
+
+	// restore the previous NumLock state
+	toggled, err = vk.KeyPressAndWaitLED(KeyNumlock, LedNuml, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for LED event. Last error was: %s\n", err)
+	}
+	if !toggled {
+		t.Fatalf("Expected host to answer the second key press with an LED event")
+	}
+	restored, err := vk.FetchLEDState()
+	if err != nil {
+		t.Fatalf("Failed to fetch LED state. Last error was: %s\n", err)
+	}
+	if restored.NumLock != before.NumLock {
+		t.Fatalf("Expected NumLock state to be restored to %v, but got %v", before.NumLock, restored.NumLock)
+	}
+}
+
+func TestMaxSimultaneousKeysRefusesExcessKeys(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions([]DeviceOption{WithMaxSimultaneousKeys(2)}), pressed: map[int]bool{KeyA: true, KeyS: true}}
+
+	accepted, err := vk.acceptKeyDown(vk.pressed, KeyD)
+	if err == nil || accepted {
+		t.Fatalf("Expected third key to be refused, but it was accepted")
+	}
+
+	accepted, err = vk.acceptKeyDown(vk.pressed, KeyLeftshift)
+	if err != nil || !accepted {
+		t.Fatalf("Expected modifier key to be accepted, but got error: %v", err)
+	}
+
+	accepted, err = vk.acceptKeyDown(vk.pressed, KeyA)
+	if err != nil || !accepted {
+		t.Fatalf("Expected already held key to be accepted, but got error: %v", err)
+	}
+}
+
+func TestMaxSimultaneousKeysIgnoresModifiers(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions([]DeviceOption{WithMaxSimultaneousKeys(1)}), pressed: map[int]bool{KeyLeftctrl: true, KeyLeftalt: true}}
+
+	accepted, err := vk.acceptKeyDown(vk.pressed, KeyDelete)
+	if err != nil || !accepted {
+		t.Fatalf("Expected key to be accepted, but got error: %v", err)
+	}
+}
+
+func TestMaxSimultaneousKeysSilentDrop(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions([]DeviceOption{WithMaxSimultaneousKeys(1), WithSilentKeyDrop(true)}), pressed: map[int]bool{KeyA: true}}
+
+	accepted, err := vk.acceptKeyDown(vk.pressed, KeyS)
+	if err != nil {
+		t.Fatalf("Expected key to be dropped silently, but got error: %v", err)
+	}
+	if accepted {
+		t.Fatalf("Expected key to be dropped, but it was accepted")
+	}
+}
+
+func TestKeyComboRespectsMaxSimultaneousKeys(t *testing.T) {
+	fake := NewFake()
+	vk, err := fake.CreateKeyboard(WithMaxSimultaneousKeys(2))
+	if err != nil {
+		t.Fatalf("Failed to create fake keyboard: %v", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyCombo(KeyLeftctrl, KeyA, KeyS, KeyD)
+	if err == nil {
+		t.Fatalf("Expected key combination exceeding the limit to be refused")
+	}
+	if events := fake.Events(); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+
+	err = vk.EmitKeyEvents([]KeyRaw{{KeyA, btnStatePressed}, {KeyS, btnStatePressed}, {KeyD, btnStatePressed}})
+	if err == nil {
+		t.Fatalf("Expected key events exceeding the limit to be refused")
+	}
+	if keys := vk.PressedKeys(); len(keys) != 0 {
+		t.Fatalf("Expected no keys to be pressed, but got %v", keys)
+	}
+}
+
+func TestKeyComboSilentlyDropsExcessKeys(t *testing.T) {
+	fake := NewFake()
+	vk, err := fake.CreateKeyboard(WithMaxSimultaneousKeys(2), WithSilentKeyDrop(true))
+	if err != nil {
+		t.Fatalf("Failed to create fake keyboard: %v", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyCombo(KeyLeftctrl, KeyA, KeyS, KeyD)
+	if err != nil {
+		t.Fatalf("Expected excess key to be dropped silently, but got error: %v", err)
+	}
+	for _, ev := range fake.Events() {
+		if ev.Type == evKey && ev.Code == KeyD && ev.Value == btnStatePressed {
+			t.Fatalf("Expected press of excess key to be dropped, but it was sent: %+v", fake.Events())
+		}
+	}
+	if keys := vk.PressedKeys(); len(keys) != 0 {
+		t.Fatalf("Expected all keys to be released, but got %v", keys)
+	}
+}
+
+func TestKeyboardWithMaxSimultaneousKeys(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Ghosting Keyboard"), WithMaxSimultaneousKeys(2))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	for _, key := range []int{KeyA, KeyS} {
+		err = vk.KeyDown(key)
+		if err != nil {
+			t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+		}
+	}
+
+	err = vk.KeyDown(KeyD)
+	if err == nil {
+		t.Fatalf("Expected key down to fail due to the key limit, but got no error.")
+	}
+
+	for _, key := range []int{KeyA, KeyS} {
+		err = vk.KeyUp(key)
+		if err != nil {
+			t.Fatalf("Failed to send key up event. Last error was: %s\n", err)
+		}
+	}
+
+	err = vk.KeyDown(KeyD)
+	if err != nil {
+		t.Fatalf("Failed to send key down event. Last error was: %s\n", err)
+	}
+	_ = vk.KeyUp(KeyD)
+}
+
+func TestMagicSysRqFailsOnUnsupportedCommand(t *testing.T) {
+	vk := &vKeyboard{pressed: make(map[int]bool)}
+	err := vk.MagicSysRq('?')
+	if err == nil {
+		t.Fatalf("Expected MagicSysRq to fail due to an unsupported command, but got no error.")
+	}
+}
+
+func TestMagicSysRq(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test SysRq Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	// 'h' only prints the SysRq help to the kernel log, which makes it the only safe command to test with
+	err = vk.MagicSysRq('h')
+	if err != nil {
+		t.Fatalf("Failed to issue SysRq sequence. Last error was: %s\n", err)
+	}
+}
+
+func TestEmitKeyEventsSendsSingleFrame(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.EmitKeyEvents([]KeyRaw{{KeyA, 1}, {KeyS, 1}, {KeyD, 0}})
+	if err != nil {
+		t.Fatalf("Failed to emit key events: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: 1},
+		{Type: evKey, Code: KeyS, Value: 1},
+		{Type: evKey, Code: KeyD, Value: 0},
+		{Type: evSyn, Code: synReport, Value: 0},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+	if !vk.pressed[KeyA] || !vk.pressed[KeyS] || vk.pressed[KeyD] {
+		t.Fatalf("Unexpected pressed keys: %v", vk.pressed)
+	}
+}
+
+func TestEmitKeyEventsValidatesAllCodesFirst(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.EmitKeyEvents([]KeyRaw{{KeyA, 1}, {keyMax + 1, 1}})
+	if err == nil {
+		t.Fatalf("Expected EmitKeyEvents to fail due to invalid key code, but got no error.")
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+}
+
+func TestTypeComposeUsesConfiguredComposeKey(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithComposeKey(KeyRightalt)}), pressed: make(map[int]bool)}
+
+	err := vk.TypeCompose(KeyApostrophe, KeyE)
+	if err != nil {
+		t.Fatalf("Failed to type compose sequence: %v", err)
+	}
+
+	var pressed []uint16
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evKey && ev.Value == btnStatePressed {
+			pressed = append(pressed, ev.Code)
+		}
+	}
+	expected := []uint16{KeyRightalt, KeyApostrophe, KeyE}
+	if fmt.Sprint(pressed) != fmt.Sprint(expected) {
+		t.Fatalf("Expected keys %v to be pressed, but got %v", expected, pressed)
+	}
+}
+
+func TestTypeComposeFailsOnInvalidSequence(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.TypeCompose()
+	if err == nil {
+		t.Fatalf("Expected TypeCompose to fail due to an empty sequence, but got no error.")
+	}
+	err = vk.TypeCompose(KeyE, keyMax+1)
+	if err == nil {
+		t.Fatalf("Expected TypeCompose to fail due to invalid key code, but got no error.")
+	}
+}
+
+func TestKeyboardGrab(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Grab Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.Ungrab()
+	if err == nil {
+		t.Fatalf("Expected Ungrab to fail on a device that is not grabbed, but got no error.")
+	}
+
+	err = vk.Grab()
+	if err != nil {
+		t.Fatalf("Failed to grab device. Last error was: %s\n", err)
+	}
+	err = vk.Grab()
+	if err == nil {
+		t.Fatalf("Expected Grab to fail on a device that is already grabbed, but got no error.")
+	}
+
+	err = vk.Ungrab()
+	if err != nil {
+		t.Fatalf("Failed to release grab. Last error was: %s\n", err)
+	}
+}
+
+func TestPreSyncDelayDelaysSync(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	delay := 20 * time.Millisecond
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithPreSyncDelay(delay)}), pressed: make(map[int]bool)}
+
+	start := time.Now()
+	err := vk.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key press: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*delay {
+		t.Fatalf("Expected key press (two syncs) to take at least %v, but it took %v", 2*delay, elapsed)
+	}
+}
+
+func TestKeyboardWithPreSyncDelay(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Delayed Keyboard"), WithPreSyncDelay(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyPress(Key1)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+}
+
+func TestSwitchVTSendsChord(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.SwitchVT(12)
+	if err != nil {
+		t.Fatalf("Failed to switch VT: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStatePressed},
+		{Type: evKey, Code: KeyLeftalt, Value: btnStatePressed},
+		{Type: evKey, Code: KeyF12, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyF12, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftalt, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestSwitchVTFailsOnInvalidTerminal(t *testing.T) {
+	vk := &vKeyboard{pressed: make(map[int]bool)}
+	for _, n := range []int{0, 13} {
+		err := vk.SwitchVT(n)
+		if err == nil {
+			t.Fatalf("Expected SwitchVT(%d) to fail, but got no error.", n)
+		}
+	}
+}
+
+func TestMeasureLatency(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Latency Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	latency, err := vk.MeasureLatency(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to measure latency. Last error was: %s\n", err)
+	}
+	if latency <= 0 || latency > latencyTimeout {
+		t.Fatalf("Expected a latency between 0 and %v, but got %v", latencyTimeout, latency)
+	}
+}
+
+func TestMeasureLatencyFailsOnInvalidKey(t *testing.T) {
+	vk := &vKeyboard{pressed: make(map[int]bool)}
+	_, err := vk.MeasureLatency(-1)
+	if err == nil {
+		t.Fatalf("Expected MeasureLatency to fail due to invalid key code, but got no error.")
+	}
+}
+
+func TestMeasureLatencyOnlyTimesKeyDown(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	// the event node reports the key down event right away
+	_, err = w.Write(AppendEvent(nil, evKey, KeyA, btnStatePressed))
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to write event: %v", err)
+	}
+
+	delay := 100 * time.Millisecond
+	vk := &vKeyboard{deviceFile: &device{File: file}, eventFile: r, pressed: make(map[int]bool),
+		options: newDeviceOptions([]DeviceOption{WithPreSyncDelay(delay)})}
+	latency, err := vk.MeasureLatency(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to measure latency: %v", err)
+	}
+	if latency >= delay {
+		t.Fatalf("Expected the latency to exclude the release of the key, but got %v", latency)
+	}
+
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	events := readTestEvents(t, file)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+// newEchoKeyboard returns a keyboard whose events are echoed to its event node (a pipe), except for the key presses that
+// the given drop function refuses, along with a function closing the files of the keyboard.
+func newEchoKeyboard(t *testing.T, drop func(Event) bool) (*vKeyboard, func()) {
+	file := createTestEventFile(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	options := newDeviceOptions([]DeviceOption{WithEventObserver(func(ev Event) {
+		if ev.Type == evKey && ev.Value == btnStatePressed && drop(ev) {
+			return
+		}
+		_, _ = w.Write(AppendEvent(nil, ev.Type, ev.Code, ev.Value))
+	})})
+	fd := newDevice(file, options)
+	closeFiles := func() {
+		_ = file.Close()
+		_ = r.Close()
+		_ = w.Close()
+	}
+	return &vKeyboard{deviceFile: fd, eventFile: r, options: options, pressed: make(map[int]bool)}, closeFiles
+}
+
+func TestTypeVerifiedRetypesDroppedCharacters(t *testing.T) {
+	dropped := false
+	vk, closeFiles := newEchoKeyboard(t, func(ev Event) bool {
+		if ev.Code == KeyB && !dropped {
+			dropped = true
+			return true
+		}
+		return false
+	})
+	defer closeFiles()
+
+	err := vk.TypeVerified("aBc")
+	if err != nil {
+		t.Fatalf("Failed to type text: %v", err)
+	}
+	var presses []uint16
+	for _, ev := range readTestEvents(t, vk.deviceFile.File) {
+		if ev.Type == evKey && ev.Value == btnStatePressed {
+			presses = append(presses, ev.Code)
+		}
+	}
+	expected := []uint16{KeyA, KeyLeftshift, KeyB, KeyLeftshift, KeyB, KeyC}
+	if fmt.Sprint(presses) != fmt.Sprint(expected) {
+		t.Fatalf("Expected the dropped character to be typed again (%v), but got %v", expected, presses)
+	}
+}
+
+func TestTypeVerifiedFailsIfCharacterIsNeverReadBack(t *testing.T) {
+	vk, closeFiles := newEchoKeyboard(t, func(ev Event) bool { return ev.Code == KeyB })
+	defer closeFiles()
+
+	err := vk.TypeVerified("abc")
+	if err == nil {
+		t.Fatalf("Expected TypeVerified to fail, but got no error.")
+	}
+	if text := typedText(t, readTestEvents(t, vk.deviceFile.File)); text != "abbb" {
+		t.Fatalf("Expected the character to be typed %d times, but got %q", verifyAttempts, text)
+	}
+}
+
+func TestMeasureLatencyFailsOnHeldKey(t *testing.T) {
+	vk := &vKeyboard{pressed: map[int]bool{KeyA: true}}
+	_, err := vk.MeasureLatency(KeyA)
+	if err == nil {
+		t.Fatalf("Expected MeasureLatency to fail due to a held key, but got no error.")
+	}
+}
+
+func TestSelectToLineEndSendsShortcut(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.SelectToLineEnd(EditingContextDefault)
+	if err != nil {
+		t.Fatalf("Failed to select to line end: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyEnd, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyEnd, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestSelectWordReleasesModifiersInReverseOrder(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.SelectWord(EditingContextMac)
+	if err != nil {
+		t.Fatalf("Failed to select word: %v", err)
+	}
+
+	var keys []inputEvent
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evKey {
+			keys = append(keys, ev)
+		}
+	}
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftalt, Value: btnStatePressed},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyRight, Value: btnStatePressed},
+		{Type: evKey, Code: KeyRight, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftalt, Value: btnStateReleased},
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %d key events, but got %d: %+v", len(expected), len(keys), keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, keys[i])
+		}
+	}
+	if len(vk.pressed) != 0 {
+		t.Fatalf("Expected all keys to be released, but got %v", vk.pressed)
+	}
+}
+
+func TestSelectionFailsInTerminalContext(t *testing.T) {
+	vk := &vKeyboard{pressed: make(map[int]bool)}
+	err := vk.SelectWord(EditingContextTerminal)
+	if err == nil {
+		t.Fatalf("Expected SelectWord to fail in a terminal context, but got no error.")
+	}
+	err = vk.SelectToLineEnd(EditingContextTerminal)
+	if err == nil {
+		t.Fatalf("Expected SelectToLineEnd to fail in a terminal context, but got no error.")
+	}
+	err = vk.SelectWord(EditingContext(42))
+	if err == nil {
+		t.Fatalf("Expected SelectWord to fail for an unknown context, but got no error.")
+	}
+}
+
+func TestTypeUsesLayout(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.Type("Hi")
+	if err != nil {
+		t.Fatalf("Failed to type text: %v", err)
+	}
+
+	var keys []inputEvent
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evKey {
+			keys = append(keys, ev)
+		}
+	}
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyH, Value: btnStatePressed},
+		{Type: evKey, Code: KeyH, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evKey, Code: KeyI, Value: btnStatePressed},
+		{Type: evKey, Code: KeyI, Value: btnStateReleased},
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %d key events, but got %d: %+v", len(expected), len(keys), keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, keys[i])
+		}
+	}
+}
+
+func TestTypeFailsOnUnsupportedCharacter(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.Type("café")
+	if err == nil {
+		t.Fatalf("Expected Type to fail due to an unsupported character, but got no error.")
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+}
+
+func TestTypeWithCustomLayout(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	layout := KeyMap{'x': {{Key: KeyY}}}
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithLayout(layout)}), pressed: make(map[int]bool)}
+
+	err := vk.Type("x")
+	if err != nil {
+		t.Fatalf("Failed to type text: %v", err)
+	}
+	events := readTestEvents(t, file)
+	if len(events) == 0 || events[0].Code != KeyY {
+		t.Fatalf("Expected the custom layout to be used, but got %+v", events)
+	}
+}
+
+func TestKeyComboReleasesInReverseOrder(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.KeyCombo(KeyLeftctrl, KeyLeftshift, KeyT)
+	if err != nil {
+		t.Fatalf("Failed to send key combination: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStatePressed},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyT, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyT, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestKeyComboFailsOnInvalidKeys(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	if err := vk.KeyCombo(); err == nil {
+		t.Fatalf("Expected KeyCombo to fail without keys, but got no error.")
+	}
+	if err := vk.KeyCombo(KeyLeftctrl, -1); err == nil {
+		t.Fatalf("Expected KeyCombo to fail due to invalid key code, but got no error.")
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+}
+
+func TestKeyboardWithSelectedKeys(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Media Keyboard"), WithKeys([]int{KeyVolumeup, KeyVolumedown}))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.KeyPress(KeyVolumeup)
+	if err != nil {
+		t.Fatalf("Failed to send key press. Last error was: %s\n", err)
+	}
+}
+
+func TestValidateKeys(t *testing.T) {
+	if err := validateKeys(nil); err != nil {
+		t.Fatalf("Expected all keys to be accepted by default, but got: %v", err)
+	}
+	if err := validateKeys([]int{KeyA, KeyB}); err != nil {
+		t.Fatalf("Expected valid keys to be accepted, but got: %v", err)
+	}
+	if err := validateKeys([]int{}); err == nil {
+		t.Fatalf("Expected an empty set of keys to be refused, but got no error.")
+	}
+	if err := validateKeys([]int{KeyA, keyMax + 1}); err == nil {
+		t.Fatalf("Expected an invalid key to be refused, but got no error.")
+	}
+}
+
+func TestMediaKeysAreSentWithScanCode(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	err := vk.KeyPress(KeyVolumeup)
+	if err != nil {
+		t.Fatalf("Failed to send key press: %v", err)
+	}
+	err = vk.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Failed to send key press: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evMsc, Code: mscScan, Value: 0xc00e9},
+		{Type: evKey, Code: KeyVolumeup, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evMsc, Code: mscScan, Value: 0xc00e9},
+		{Type: evKey, Code: KeyVolumeup, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestFetchLEDStateTracksHostEvents(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	vk := &vKeyboard{deviceFile: &device{File: r}, pressed: make(map[int]bool)}
+
+	for _, ev := range []inputEvent{
+		{Type: evLed, Code: LedNuml, Value: 1},
+		{Type: evLed, Code: LedCapsl, Value: 1},
+		{Type: evSyn, Code: synReport},
+		{Type: evLed, Code: LedNuml, Value: 0},
+	} {
+		buf, err := inputEventToBuffer(ev)
+		if err != nil {
+			t.Fatalf("Failed to encode event: %v", err)
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+
+	state, err := vk.FetchLEDState()
+	if err != nil {
+		t.Fatalf("Failed to fetch LED state: %v", err)
+	}
+	expected := LEDState{CapsLock: true}
+	if state != expected {
+		t.Fatalf("Expected LED state %+v, but got %+v", expected, state)
+	}
+
+	state, err = vk.FetchLEDState()
+	if err != nil || state != expected {
+		t.Fatalf("Expected LED state to remain %+v without new events, but got %+v (error: %v)", expected, state, err)
+	}
+}
+
+func TestKeyboardSendRawEventAndSync(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.SendRawEvent(evMsc, mscScan, 0x70004)
+	if err != nil {
+		t.Fatalf("Failed to send raw event: %v", err)
+	}
+	err = vk.Sync()
+	if err != nil {
+		t.Fatalf("Failed to sync: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evMsc, Code: mscScan, Value: 0x70004},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestKeyboardWaitsUntilReady(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Ready Keyboard"), WithWaitUntilReady(2*time.Second))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	node, err := vk.EventNode()
+	if err != nil {
+		t.Fatalf("Failed to fetch event node. Last error was: %s\n", err)
+	}
+	if !strings.HasPrefix(node, "/dev/input/event") {
+		t.Fatalf("Expected an event node, but got %s\n", node)
+	}
+}
+
+func TestSetRepeatSendsRepeatEvents(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	options := newDeviceOptions([]DeviceOption{WithKeyRepeat(250*time.Millisecond, 33*time.Millisecond)})
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: options, pressed: make(map[int]bool)}
+
+	err := vk.SetRepeat(500, 20)
+	if err != nil {
+		t.Fatalf("Failed to set key repeat: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evRep, Code: repDelay, Value: 500},
+		{Type: evRep, Code: repPeriod, Value: 20},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestSetRepeatRequiresKeyRepeat(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+	err := vk.SetRepeat(250, 33)
+	if err == nil {
+		t.Fatalf("Expected SetRepeat to fail without WithKeyRepeat")
+	}
+}
+
+func TestKeyboardWithKeyRepeat(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Repeat Keyboard"), WithKeyRepeat(250*time.Millisecond, 33*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	err = vk.SetRepeat(400, 40)
+	if err != nil {
+		t.Fatalf("Failed to set key repeat. Last error was: %s\n", err)
+	}
+}
+
+func TestTypeCtxStopsWhenCancelled(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := vk.TypeCtx(ctx, "hello")
+	if err != context.Canceled {
+		t.Fatalf("Expected typing to stop with %v, but got %v", context.Canceled, err)
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+}
+
+func TestCloseReleasesHeldKeys(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	options := newDeviceOptions([]DeviceOption{WithReleaseOnClose(true)})
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: options, pressed: make(map[int]bool)}
+
+	err := vk.KeyDown(KeyLeftctrl)
+	if err != nil {
+		t.Fatalf("Failed to send key down: %v", err)
+	}
+	err = vk.KeyDown(KeyC)
+	if err != nil {
+		t.Fatalf("Failed to send key down: %v", err)
+	}
+	_ = vk.Close()
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyC, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftctrl, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != 4+len(expected) {
+		t.Fatalf("Expected %d events, but got %+v", 4+len(expected), events)
+	}
+	for i, ev := range events[4:] {
+		if ev != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, ev)
+		}
+	}
+	if len(vk.pressed) != 0 {
+		t.Fatalf("Expected no keys to be held after closing, but got %v", vk.pressed)
+	}
+}
+
+func TestCloseKeepsHeldKeysByDefault(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.KeyDown(KeyC)
+	if err != nil {
+		t.Fatalf("Failed to send key down: %v", err)
+	}
+	_ = vk.Close()
+
+	if events := readTestEvents(t, file); len(events) != 2 {
+		t.Fatalf("Expected only the key press to be sent, but got %+v", events)
+	}
+}
+
+func TestConcurrentKeyPressesDoNotInterleave(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	var wg sync.WaitGroup
+	for _, key := range []int{KeyA, KeyB, KeyC, KeyD} {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				err := vk.KeyPress(key)
+				if err != nil {
+					t.Errorf("Failed to press key: %v", err)
+					return
+				}
+			}
+		}(key)
+	}
+	wg.Wait()
+
+	events := readTestEvents(t, file)
+	if len(events) != 4*50*4 {
+		t.Fatalf("Expected %d events, but got %d", 4*50*4, len(events))
+	}
+	for i := 0; i < len(events); i += 4 {
+		press, release := events[i], events[i+2]
+		if press.Type != evKey || release.Code != press.Code || events[i+1].Type != evSyn || events[i+3].Type != evSyn {
+			t.Fatalf("Expected a complete key press at position %d, but got %+v", i, events[i:i+4])
+		}
+	}
+}
+
+func TestConcurrentKeyComboDoesNotInterleaveWithType(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	// the delay before each sync gives the other goroutine a chance to interfere
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithPreSyncDelay(time.Millisecond)}), pressed: make(map[int]bool)}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := vk.Type("Ab"); err != nil {
+				t.Errorf("Failed to type text: %v", err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := vk.KeyCombo(KeyLeftctrl, KeyC); err != nil {
+				t.Errorf("Failed to press key combination: %v", err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	// Type("Ab") and KeyCombo both leave no key pressed, so the set of held keys must be empty between them
+	var held []uint16
+	var typed, combos int
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type != evKey {
+			continue
+		}
+		if ev.Value == btnStatePressed {
+			held = append(held, ev.Code)
+			continue
+		}
+		if len(held) == 0 || held[len(held)-1] != ev.Code {
+			t.Fatalf("Expected key %d to be released in reverse order, but %v are held", ev.Code, held)
+		}
+		held = held[:len(held)-1]
+		if len(held) != 0 {
+			continue
+		}
+		switch ev.Code {
+		case KeyLeftshift:
+			typed++
+		case KeyB:
+			typed++
+		case KeyLeftctrl:
+			combos++
+		default:
+			t.Fatalf("Unexpected key %d released last", ev.Code)
+		}
+		if ev.Code == KeyLeftctrl && typed%2 != 0 {
+			t.Fatalf("Expected the key combination not to interleave with typed text")
+		}
+	}
+	if typed != 40 || combos != 20 {
+		t.Fatalf("Expected 40 typed characters and 20 key combinations, but got %d and %d", typed, combos)
+	}
+}
+
+func TestKeyboardErrorsMatchSentinels(t *testing.T) {
+	file := createTestEventFile(t)
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.KeyPress(-1)
+	if !errors.Is(err, ErrKeyOutOfRange) {
+		t.Fatalf("Expected an error matching ErrKeyOutOfRange, but got %v", err)
+	}
+
+	_ = file.Close()
+	err = vk.KeyDown(KeyA)
+	if !errors.Is(err, ErrDeviceClosed) {
+		t.Fatalf("Expected an error matching ErrDeviceClosed, but got %v", err)
+	}
+}
+
+func TestPressedKeysAreTracked(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, pressed: make(map[int]bool)}
+
+	for _, key := range []int{KeyLeftshift, KeyA} {
+		if err := vk.KeyDown(key); err != nil {
+			t.Fatalf("Failed to press key %d: %v", key, err)
+		}
+	}
+	if err := vk.EmitKeyEvents([]KeyRaw{{Code: KeyB, Value: btnStatePressed}, {Code: KeyA, Value: btnStateReleased}}); err != nil {
+		t.Fatalf("Failed to emit key events: %v", err)
+	}
+
+	keys := vk.PressedKeys()
+	if len(keys) != 2 || keys[0] != KeyLeftshift || keys[1] != KeyB {
+		t.Fatalf("Expected KeyLeftshift and KeyB to be pressed, but got %v", keys)
+	}
+	if !vk.IsPressed(KeyB) || vk.IsPressed(KeyA) {
+		t.Fatalf("Expected KeyB to be pressed and KeyA to be released")
+	}
+
+	if err := vk.KeyUp(KeyLeftshift); err != nil {
+		t.Fatalf("Failed to release key: %v", err)
+	}
+	if vk.IsPressed(KeyLeftshift) {
+		t.Fatalf("Expected KeyLeftshift to be released")
+	}
+}
+
+func TestTypeRuneUsesAltGrAndDeadKeys(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithLayout(LayoutDE)}), pressed: make(map[int]bool)}
+
+	for _, r := range "@^" {
+		if err := vk.TypeRune(r); err != nil {
+			t.Fatalf("Failed to type %q: %v", r, err)
+		}
+	}
+
+	var keys []inputEvent
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evKey {
+			keys = append(keys, ev)
+		}
+	}
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyRightalt, Value: btnStatePressed},
+		{Type: evKey, Code: KeyQ, Value: btnStatePressed},
+		{Type: evKey, Code: KeyQ, Value: btnStateReleased},
+		{Type: evKey, Code: KeyRightalt, Value: btnStateReleased},
+		{Type: evKey, Code: KeyGrave, Value: btnStatePressed},
+		{Type: evKey, Code: KeyGrave, Value: btnStateReleased},
+		{Type: evKey, Code: KeySpace, Value: btnStatePressed},
+		{Type: evKey, Code: KeySpace, Value: btnStateReleased},
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected %d key events, but got %d: %+v", len(expected), len(keys), keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, keys[i])
+		}
+	}
+}
+
+func TestTypeRuneFailsOnUnsupportedCharacter(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	expected := `failed to perform TypeRune. Character 'é' is not supported by the keyboard layout`
+	err := vk.TypeRune('é')
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTypeEntersUnsupportedCharactersByCodePoint(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithUnicodeInput(true)}), pressed: make(map[int]bool)}
+
+	err := vk.Type("é")
+	if err != nil {
+		t.Fatalf("Failed to type text: %v", err)
+	}
+
+	var pressed []uint16
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evKey && ev.Value == btnStatePressed {
+			pressed = append(pressed, ev.Code)
+		}
+	}
+	// é is U+00E9
+	expected := []uint16{KeyLeftctrl, KeyLeftshift, KeyU, KeyE, Key9, KeySpace}
+	if len(pressed) != len(expected) {
+		t.Fatalf("Expected key presses %v, but got %v", expected, pressed)
+	}
+	for i := range expected {
+		if pressed[i] != expected[i] {
+			t.Fatalf("Expected key presses %v, but got %v", expected, pressed)
+		}
+	}
+	if len(vk.PressedKeys()) != 0 {
+		t.Fatalf("Expected all keys to be released, but got %v", vk.PressedKeys())
+	}
+}
+
+func TestUnicodeInputIsDisabledByDefault(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+	if err := vk.Type("😀"); err == nil {
+		t.Fatalf("Expected Type to fail for an unsupported character without Unicode input")
+	}
+}
+
+func TestCreateKeyboardFromFileFailsOnNilFile(t *testing.T) {
+	_, err := CreateKeyboardFromFile(nil, []byte("Test Keyboard"))
+	if err == nil {
+		t.Fatalf("Expected keyboard creation to fail due to a missing device file, but got no error.")
+	}
+}
+
+func TestCreateKeyboardFromFileUsesGivenFile(t *testing.T) {
+	file := createTestEventFile(t)
+	_, err := CreateKeyboardFromFile(file, []byte("Test Keyboard"))
+	if err == nil {
+		t.Fatalf("Expected keyboard creation to fail, since the file is no uinput device, but got no error.")
+	}
+	if _, err = file.Write([]byte{0}); err == nil {
+		t.Fatalf("Expected the file to be closed after the keyboard creation failed")
+	}
+}
@@ -1,274 +1,996 @@
+// Code generated by mkkeycodes.go from input-event-codes.h; DO NOT EDIT.
+
 package uinput
 
-// the constants that are defined here relate 1:1 to the constants defined in input.h and represent actual
-// key codes that can be triggered as key events
+// the constants that are defined here relate 1:1 to the KEY_* constants defined in input-event-codes.h
+// and represent the key codes that can be triggered as key events
 const (
-	keyReserved         = 0
-	KeyEsc              = 1
-	Key1                = 2
-	Key2                = 3
-	Key3                = 4
-	Key4                = 5
-	Key5                = 6
-	Key6                = 7
-	Key7                = 8
-	Key8                = 9
-	Key9                = 10
-	Key0                = 11
-	KeyMinus            = 12
-	KeyEqual            = 13
-	KeyBackspace        = 14
-	KeyTab              = 15
-	KeyQ                = 16
-	KeyW                = 17
-	KeyE                = 18
-	KeyR                = 19
-	KeyT                = 20
-	KeyY                = 21
-	KeyU                = 22
-	KeyI                = 23
-	KeyO                = 24
-	KeyP                = 25
-	KeyLeftbrace        = 26
-	KeyRightbrace       = 27
-	KeyEnter            = 28
-	KeyLeftctrl         = 29
-	KeyA                = 30
-	KeyS                = 31
-	KeyD                = 32
-	KeyF                = 33
-	KeyG                = 34
-	KeyH                = 35
-	KeyJ                = 36
-	KeyK                = 37
-	KeyL                = 38
-	KeySemicolon        = 39
-	KeyApostrophe       = 40
-	KeyGrave            = 41
-	KeyLeftshift        = 42
-	KeyBackslash        = 43
-	KeyZ                = 44
-	KeyX                = 45
-	KeyC                = 46
-	KeyV                = 47
-	KeyB                = 48
-	KeyN                = 49
-	KeyM                = 50
-	KeyComma            = 51
-	KeyDot              = 52
-	KeySlash            = 53
-	KeyRightshift       = 54
-	KeyKpasterisk       = 55
-	KeyLeftalt          = 56
-	KeySpace            = 57
-	KeyCapslock         = 58
-	KeyF1               = 59
-	KeyF2               = 60
-	KeyF3               = 61
-	KeyF4               = 62
-	KeyF5               = 63
-	KeyF6               = 64
-	KeyF7               = 65
-	KeyF8               = 66
-	KeyF9               = 67
-	KeyF10              = 68
-	KeyNumlock          = 69
-	KeyScrolllock       = 70
-	KeyKp7              = 71
-	KeyKp8              = 72
-	KeyKp9              = 73
-	KeyKpminus          = 74
-	KeyKp4              = 75
-	KeyKp5              = 76
-	KeyKp6              = 77
-	KeyKpplus           = 78
-	KeyKp1              = 79
-	KeyKp2              = 80
-	KeyKp3              = 81
-	KeyKp0              = 82
-	KeyKpdot            = 83
-	KeyZenkakuhankaku   = 85
-	Key102Nd            = 86
-	KeyF11              = 87
-	KeyF12              = 88
-	KeyRo               = 89
-	KeyKatakana         = 90
-	KeyHiragana         = 91
-	KeyHenkan           = 92
-	KeyKatakanahiragana = 93
-	KeyMuhenkan         = 94
-	KeyKpjpcomma        = 95
-	KeyKpenter          = 96
-	KeyRightctrl        = 97
-	KeyKpslash          = 98
-	KeySysrq            = 99
-	KeyRightalt         = 100
-	KeyLinefeed         = 101
-	KeyHome             = 102
-	KeyUp               = 103
-	KeyPageup           = 104
-	KeyLeft             = 105
-	KeyRight            = 106
-	KeyEnd              = 107
-	KeyDown             = 108
-	KeyPagedown         = 109
-	KeyInsert           = 110
-	KeyDelete           = 111
-	KeyMacro            = 112
-	KeyMute             = 113
-	KeyVolumedown       = 114
-	KeyVolumeup         = 115
-	KeyPower            = 116 /*ScSystemPowerDown*/
-	KeyKpequal          = 117
-	KeyKpplusminus      = 118
-	KeyPause            = 119
-	KeyScale            = 120 /*AlCompizScale(Expose)*/
-	KeyKpcomma          = 121
-	KeyHangeul          = 122
-	KeyHanja            = 123
-	KeyYen              = 124
-	KeyLeftmeta         = 125
-	KeyRightmeta        = 126
-	KeyCompose          = 127
-	KeyStop             = 128 /*AcStop*/
-	KeyAgain            = 129
-	KeyProps            = 130 /*AcProperties*/
-	KeyUndo             = 131 /*AcUndo*/
-	KeyFront            = 132
-	KeyCopy             = 133 /*AcCopy*/
-	KeyOpen             = 134 /*AcOpen*/
-	KeyPaste            = 135 /*AcPaste*/
-	KeyFind             = 136 /*AcSearch*/
-	KeyCut              = 137 /*AcCut*/
-	KeyHelp             = 138 /*AlIntegratedHelpCenter*/
-	KeyMenu             = 139 /*Menu(ShowMenu)*/
-	KeyCalc             = 140 /*AlCalculator*/
-	KeySetup            = 141
-	KeySleep            = 142 /*ScSystemSleep*/
-	KeyWakeup           = 143 /*SystemWakeUp*/
-	KeyFile             = 144 /*AlLocalMachineBrowser*/
-	KeySendfile         = 145
-	KeyDeletefile       = 146
-	KeyXfer             = 147
-	KeyProg1            = 148
-	KeyProg2            = 149
-	KeyWww              = 150 /*AlInternetBrowser*/
-	KeyMsdos            = 151
-	KeyCoffee           = 152 /*AlTerminalLock/Screensaver*/
-	KeyDirection        = 153
-	KeyCyclewindows     = 154
-	KeyMail             = 155
-	KeyBookmarks        = 156 /*AcBookmarks*/
-	KeyComputer         = 157
-	KeyBack             = 158 /*AcBack*/
-	KeyForward          = 159 /*AcForward*/
-	KeyClosecd          = 160
-	KeyEjectcd          = 161
-	KeyEjectclosecd     = 162
-	KeyNextsong         = 163
-	KeyPlaypause        = 164
-	KeyPrevioussong     = 165
-	KeyStopcd           = 166
-	KeyRecord           = 167
-	KeyRewind           = 168
-	KeyPhone            = 169 /*MediaSelectTelephone*/
-	KeyIso              = 170
-	KeyConfig           = 171 /*AlConsumerControlConfiguration*/
-	KeyHomepage         = 172 /*AcHome*/
-	KeyRefresh          = 173 /*AcRefresh*/
-	KeyExit             = 174 /*AcExit*/
-	KeyMove             = 175
-	KeyEdit             = 176
-	KeyScrollup         = 177
-	KeyScrolldown       = 178
-	KeyKpleftparen      = 179
-	KeyKprightparen     = 180
-	KeyNew              = 181 /*AcNew*/
-	KeyRedo             = 182 /*AcRedo/Repeat*/
-	KeyF13              = 183
-	KeyF14              = 184
-	KeyF15              = 185
-	KeyF16              = 186
-	KeyF17              = 187
-	KeyF18              = 188
-	KeyF19              = 189
-	KeyF20              = 190
-	KeyF21              = 191
-	KeyF22              = 192
-	KeyF23              = 193
-	KeyF24              = 194
-	KeyPlaycd           = 200
-	KeyPausecd          = 201
-	KeyProg3            = 202
-	KeyProg4            = 203
-	KeyDashboard        = 204 /*AlDashboard*/
-	KeySuspend          = 205
-	KeyClose            = 206 /*AcClose*/
-	KeyPlay             = 207
-	KeyFastforward      = 208
-	KeyBassboost        = 209
-	KeyPrint            = 210 /*AcPrint*/
-	KeyHp               = 211
-	KeyCamera           = 212
-	KeySound            = 213
-	KeyQuestion         = 214
-	KeyEmail            = 215
-	KeyChat             = 216
-	KeySearch           = 217
-	KeyConnect          = 218
-	KeyFinance          = 219 /*AlCheckbook/Finance*/
-	KeySport            = 220
-	KeyShop             = 221
-	KeyAlterase         = 222
-	KeyCancel           = 223 /*AcCancel*/
-	KeyBrightnessdown   = 224
-	KeyBrightnessup     = 225
-	KeyMedia            = 226
-	KeySwitchvideomode  = 227 /*CycleBetweenAvailableVideo */
-	KeyKbdillumtoggle   = 228
-	KeyKbdillumdown     = 229
-	KeyKbdillumup       = 230
-	KeySend             = 231 /*AcSend*/
-	KeyReply            = 232 /*AcReply*/
-	KeyForwardmail      = 233 /*AcForwardMsg*/
-	KeySave             = 234 /*AcSave*/
-	KeyDocuments        = 235
-	KeyBattery          = 236
-	KeyBluetooth        = 237
-	KeyWlan             = 238
-	KeyUwb              = 239
-	KeyUnknown          = 240
-	KeyVideoNext        = 241 /*DriveNextVideoSource*/
-	KeyVideoPrev        = 242 /*DrivePreviousVideoSource*/
-	KeyBrightnessCycle  = 243 /*BrightnessUp,AfterMaxIsMin*/
-	KeyBrightnessZero   = 244 /*BrightnessOff,UseAmbient*/
-	KeyDisplayOff       = 245 /*DisplayDeviceToOffState*/
-	KeyWimax            = 246
-	KeyRfkill           = 247 /*KeyThatControlsAllRadios*/
-	KeyMicmute          = 248 /*Mute/UnmuteTheMicrophone*/
-	keyMax              = 248 // highest key currently defined in this keyboard api
-
-	ButtonGamepad = 0x130
+	keyReserved                = 0
+	KeyEsc                     = 1
+	Key1                       = 2
+	Key2                       = 3
+	Key3                       = 4
+	Key4                       = 5
+	Key5                       = 6
+	Key6                       = 7
+	Key7                       = 8
+	Key8                       = 9
+	Key9                       = 10
+	Key0                       = 11
+	KeyMinus                   = 12
+	KeyEqual                   = 13
+	KeyBackspace               = 14
+	KeyTab                     = 15
+	KeyQ                       = 16
+	KeyW                       = 17
+	KeyE                       = 18
+	KeyR                       = 19
+	KeyT                       = 20
+	KeyY                       = 21
+	KeyU                       = 22
+	KeyI                       = 23
+	KeyO                       = 24
+	KeyP                       = 25
+	KeyLeftbrace               = 26
+	KeyRightbrace              = 27
+	KeyEnter                   = 28
+	KeyLeftctrl                = 29
+	KeyA                       = 30
+	KeyS                       = 31
+	KeyD                       = 32
+	KeyF                       = 33
+	KeyG                       = 34
+	KeyH                       = 35
+	KeyJ                       = 36
+	KeyK                       = 37
+	KeyL                       = 38
+	KeySemicolon               = 39
+	KeyApostrophe              = 40
+	KeyGrave                   = 41
+	KeyLeftshift               = 42
+	KeyBackslash               = 43
+	KeyZ                       = 44
+	KeyX                       = 45
+	KeyC                       = 46
+	KeyV                       = 47
+	KeyB                       = 48
+	KeyN                       = 49
+	KeyM                       = 50
+	KeyComma                   = 51
+	KeyDot                     = 52
+	KeySlash                   = 53
+	KeyRightshift              = 54
+	KeyKpasterisk              = 55
+	KeyLeftalt                 = 56
+	KeySpace                   = 57
+	KeyCapslock                = 58
+	KeyF1                      = 59
+	KeyF2                      = 60
+	KeyF3                      = 61
+	KeyF4                      = 62
+	KeyF5                      = 63
+	KeyF6                      = 64
+	KeyF7                      = 65
+	KeyF8                      = 66
+	KeyF9                      = 67
+	KeyF10                     = 68
+	KeyNumlock                 = 69
+	KeyScrolllock              = 70
+	KeyKp7                     = 71
+	KeyKp8                     = 72
+	KeyKp9                     = 73
+	KeyKpminus                 = 74
+	KeyKp4                     = 75
+	KeyKp5                     = 76
+	KeyKp6                     = 77
+	KeyKpplus                  = 78
+	KeyKp1                     = 79
+	KeyKp2                     = 80
+	KeyKp3                     = 81
+	KeyKp0                     = 82
+	KeyKpdot                   = 83
+	KeyZenkakuhankaku          = 85
+	Key102Nd                   = 86
+	KeyF11                     = 87
+	KeyF12                     = 88
+	KeyRo                      = 89
+	KeyKatakana                = 90
+	KeyHiragana                = 91
+	KeyHenkan                  = 92
+	KeyKatakanahiragana        = 93
+	KeyMuhenkan                = 94
+	KeyKpjpcomma               = 95
+	KeyKpenter                 = 96
+	KeyRightctrl               = 97
+	KeyKpslash                 = 98
+	KeySysrq                   = 99
+	KeyRightalt                = 100
+	KeyLinefeed                = 101
+	KeyHome                    = 102
+	KeyUp                      = 103
+	KeyPageup                  = 104
+	KeyLeft                    = 105
+	KeyRight                   = 106
+	KeyEnd                     = 107
+	KeyDown                    = 108
+	KeyPagedown                = 109
+	KeyInsert                  = 110
+	KeyDelete                  = 111
+	KeyMacro                   = 112
+	KeyMute                    = 113
+	KeyVolumedown              = 114
+	KeyVolumeup                = 115
+	KeyPower                   = 116 // SC System Power Down
+	KeyKpequal                 = 117
+	KeyKpplusminus             = 118
+	KeyPause                   = 119
+	KeyScale                   = 120 // AL Compiz Scale (Expose)
+	KeyKpcomma                 = 121
+	KeyHangeul                 = 122
+	KeyHanguel                 = KeyHangeul
+	KeyHanja                   = 123
+	KeyYen                     = 124
+	KeyLeftmeta                = 125
+	KeyRightmeta               = 126
+	KeyCompose                 = 127
+	KeyStop                    = 128 // AC Stop
+	KeyAgain                   = 129
+	KeyProps                   = 130 // AC Properties
+	KeyUndo                    = 131 // AC Undo
+	KeyFront                   = 132
+	KeyCopy                    = 133 // AC Copy
+	KeyOpen                    = 134 // AC Open
+	KeyPaste                   = 135 // AC Paste
+	KeyFind                    = 136 // AC Search
+	KeyCut                     = 137 // AC Cut
+	KeyHelp                    = 138 // AL Integrated Help Center
+	KeyMenu                    = 139 // Menu (show menu)
+	KeyCalc                    = 140 // AL Calculator
+	KeySetup                   = 141
+	KeySleep                   = 142 // SC System Sleep
+	KeyWakeup                  = 143 // System Wake Up
+	KeyFile                    = 144 // AL Local Machine Browser
+	KeySendfile                = 145
+	KeyDeletefile              = 146
+	KeyXfer                    = 147
+	KeyProg1                   = 148
+	KeyProg2                   = 149
+	KeyWww                     = 150 // AL Internet Browser
+	KeyMsdos                   = 151
+	KeyCoffee                  = 152 // AL Terminal Lock/Screensaver
+	KeyScreenlock              = KeyCoffee
+	KeyRotateDisplay           = 153 // Display orientation for e.g. tablets
+	KeyDirection               = KeyRotateDisplay
+	KeyCyclewindows            = 154
+	KeyMail                    = 155
+	KeyBookmarks               = 156 // AC Bookmarks
+	KeyComputer                = 157
+	KeyBack                    = 158 // AC Back
+	KeyForward                 = 159 // AC Forward
+	KeyClosecd                 = 160
+	KeyEjectcd                 = 161
+	KeyEjectclosecd            = 162
+	KeyNextsong                = 163
+	KeyPlaypause               = 164
+	KeyPrevioussong            = 165
+	KeyStopcd                  = 166
+	KeyRecord                  = 167
+	KeyRewind                  = 168
+	KeyPhone                   = 169 // Media Select Telephone
+	KeyIso                     = 170
+	KeyConfig                  = 171 // AL Consumer Control Configuration
+	KeyHomepage                = 172 // AC Home
+	KeyRefresh                 = 173 // AC Refresh
+	KeyExit                    = 174 // AC Exit
+	KeyMove                    = 175
+	KeyEdit                    = 176
+	KeyScrollup                = 177
+	KeyScrolldown              = 178
+	KeyKpleftparen             = 179
+	KeyKprightparen            = 180
+	KeyNew                     = 181 // AC New
+	KeyRedo                    = 182 // AC Redo/Repeat
+	KeyF13                     = 183
+	KeyF14                     = 184
+	KeyF15                     = 185
+	KeyF16                     = 186
+	KeyF17                     = 187
+	KeyF18                     = 188
+	KeyF19                     = 189
+	KeyF20                     = 190
+	KeyF21                     = 191
+	KeyF22                     = 192
+	KeyF23                     = 193
+	KeyF24                     = 194
+	KeyPlaycd                  = 200
+	KeyPausecd                 = 201
+	KeyProg3                   = 202
+	KeyProg4                   = 203
+	KeyAllApplications         = 204 // AC Desktop Show All Applications
+	KeyDashboard               = KeyAllApplications
+	KeySuspend                 = 205
+	KeyClose                   = 206 // AC Close
+	KeyPlay                    = 207
+	KeyFastforward             = 208
+	KeyBassboost               = 209
+	KeyPrint                   = 210 // AC Print
+	KeyHp                      = 211
+	KeyCamera                  = 212
+	KeySound                   = 213
+	KeyQuestion                = 214
+	KeyEmail                   = 215
+	KeyChat                    = 216
+	KeySearch                  = 217
+	KeyConnect                 = 218
+	KeyFinance                 = 219 // AL Checkbook/Finance
+	KeySport                   = 220
+	KeyShop                    = 221
+	KeyAlterase                = 222
+	KeyCancel                  = 223 // AC Cancel
+	KeyBrightnessdown          = 224
+	KeyBrightnessup            = 225
+	KeyMedia                   = 226
+	KeySwitchvideomode         = 227 // Cycle between available video
+	KeyKbdillumtoggle          = 228
+	KeyKbdillumdown            = 229
+	KeyKbdillumup              = 230
+	KeySend                    = 231 // AC Send
+	KeyReply                   = 232 // AC Reply
+	KeyForwardmail             = 233 // AC Forward Msg
+	KeySave                    = 234 // AC Save
+	KeyDocuments               = 235
+	KeyBattery                 = 236
+	KeyBluetooth               = 237
+	KeyWlan                    = 238
+	KeyUwb                     = 239
+	KeyUnknown                 = 240
+	KeyVideoNext               = 241 // drive next video source
+	KeyVideoPrev               = 242 // drive previous video source
+	KeyBrightnessCycle         = 243 // brightness up, after max is min
+	KeyBrightnessAuto          = 244 // Set Auto Brightness: manual
+	KeyBrightnessZero          = KeyBrightnessAuto
+	KeyDisplayOff              = 245 // display device to off state
+	KeyWwan                    = 246 // Wireless WAN (LTE, UMTS, GSM, etc.)
+	KeyWimax                   = KeyWwan
+	KeyRfkill                  = 247 // Key that controls all radios
+	KeyMicmute                 = 248 // Mute / unmute the microphone
+	KeyOk                      = 0x160
+	KeySelect                  = 0x161
+	KeyGoto                    = 0x162
+	KeyClear                   = 0x163
+	KeyPower2                  = 0x164
+	KeyOption                  = 0x165
+	KeyInformation             = 0x166 // AL OEM Features/Tips/Tutorial
+	KeyTime                    = 0x167
+	KeyVendor                  = 0x168
+	KeyArchive                 = 0x169
+	KeyProgram                 = 0x16a // Media Select Program Guide
+	KeyChannel                 = 0x16b
+	KeyFavorites               = 0x16c
+	KeyEpg                     = 0x16d
+	KeyPvr                     = 0x16e // Media Select Home
+	KeyMhp                     = 0x16f
+	KeyLanguage                = 0x170
+	KeyTitle                   = 0x171
+	KeySubtitle                = 0x172
+	KeyAngle                   = 0x173
+	KeyFullScreen              = 0x174 // AC View Toggle
+	KeyZoom                    = KeyFullScreen
+	KeyMode                    = 0x175
+	KeyKeyboard                = 0x176
+	KeyAspectRatio             = 0x177 // HUTRR37: Aspect
+	KeyScreen                  = KeyAspectRatio
+	KeyPc                      = 0x178 // Media Select Computer
+	KeyTv                      = 0x179 // Media Select TV
+	KeyTv2                     = 0x17a // Media Select Cable
+	KeyVcr                     = 0x17b // Media Select VCR
+	KeyVcr2                    = 0x17c // VCR Plus
+	KeySat                     = 0x17d // Media Select Satellite
+	KeySat2                    = 0x17e
+	KeyCd                      = 0x17f // Media Select CD
+	KeyTape                    = 0x180 // Media Select Tape
+	KeyRadio                   = 0x181
+	KeyTuner                   = 0x182 // Media Select Tuner
+	KeyPlayer                  = 0x183
+	KeyText                    = 0x184
+	KeyDvd                     = 0x185 // Media Select DVD
+	KeyAux                     = 0x186
+	KeyMp3                     = 0x187
+	KeyAudio                   = 0x188 // AL Audio Browser
+	KeyVideo                   = 0x189 // AL Movie Browser
+	KeyDirectory               = 0x18a
+	KeyList                    = 0x18b
+	KeyMemo                    = 0x18c // Media Select Messages
+	KeyCalendar                = 0x18d
+	KeyRed                     = 0x18e
+	KeyGreen                   = 0x18f
+	KeyYellow                  = 0x190
+	KeyBlue                    = 0x191
+	KeyChannelup               = 0x192 // Channel Increment
+	KeyChanneldown             = 0x193 // Channel Decrement
+	KeyFirst                   = 0x194
+	KeyLast                    = 0x195 // Recall Last
+	KeyAb                      = 0x196
+	KeyNext                    = 0x197
+	KeyRestart                 = 0x198
+	KeySlow                    = 0x199
+	KeyShuffle                 = 0x19a
+	KeyBreak                   = 0x19b
+	KeyPrevious                = 0x19c
+	KeyDigits                  = 0x19d
+	KeyTeen                    = 0x19e
+	KeyTwen                    = 0x19f
+	KeyVideophone              = 0x1a0 // Media Select Video Phone
+	KeyGames                   = 0x1a1 // Media Select Games
+	KeyZoomin                  = 0x1a2 // AC Zoom In
+	KeyZoomout                 = 0x1a3 // AC Zoom Out
+	KeyZoomreset               = 0x1a4 // AC Zoom
+	KeyWordprocessor           = 0x1a5 // AL Word Processor
+	KeyEditor                  = 0x1a6 // AL Text Editor
+	KeySpreadsheet             = 0x1a7 // AL Spreadsheet
+	KeyGraphicseditor          = 0x1a8 // AL Graphics Editor
+	KeyPresentation            = 0x1a9 // AL Presentation App
+	KeyDatabase                = 0x1aa // AL Database App
+	KeyNews                    = 0x1ab // AL Newsreader
+	KeyVoicemail               = 0x1ac // AL Voicemail
+	KeyAddressbook             = 0x1ad // AL Contacts/Address Book
+	KeyMessenger               = 0x1ae // AL Instant Messaging
+	KeyDisplaytoggle           = 0x1af // Turn display (LCD) on and off
+	KeyBrightnessToggle        = KeyDisplaytoggle
+	KeySpellcheck              = 0x1b0 // AL Spell Check
+	KeyLogoff                  = 0x1b1 // AL Logoff
+	KeyDollar                  = 0x1b2
+	KeyEuro                    = 0x1b3
+	KeyFrameback               = 0x1b4 // Consumer - transport controls
+	KeyFrameforward            = 0x1b5
+	KeyContextMenu             = 0x1b6 // GenDesc - system context menu
+	KeyMediaRepeat             = 0x1b7 // Consumer - transport control
+	Key10channelsup            = 0x1b8 // 10 channels up (10+)
+	Key10channelsdown          = 0x1b9 // 10 channels down (10-)
+	KeyImages                  = 0x1ba // AL Image Browser
+	KeyNotificationCenter      = 0x1bc // Show/hide the notification center
+	KeyPickupPhone             = 0x1bd // Answer incoming call
+	KeyHangupPhone             = 0x1be // Decline incoming call
+	KeyLinkPhone               = 0x1bf // AL Phone Syncing
+	KeyDelEol                  = 0x1c0
+	KeyDelEos                  = 0x1c1
+	KeyInsLine                 = 0x1c2
+	KeyDelLine                 = 0x1c3
+	KeyFn                      = 0x1d0
+	KeyFnEsc                   = 0x1d1
+	KeyFnF1                    = 0x1d2
+	KeyFnF2                    = 0x1d3
+	KeyFnF3                    = 0x1d4
+	KeyFnF4                    = 0x1d5
+	KeyFnF5                    = 0x1d6
+	KeyFnF6                    = 0x1d7
+	KeyFnF7                    = 0x1d8
+	KeyFnF8                    = 0x1d9
+	KeyFnF9                    = 0x1da
+	KeyFnF10                   = 0x1db
+	KeyFnF11                   = 0x1dc
+	KeyFnF12                   = 0x1dd
+	KeyFn1                     = 0x1de
+	KeyFn2                     = 0x1df
+	KeyFnD                     = 0x1e0
+	KeyFnE                     = 0x1e1
+	KeyFnF                     = 0x1e2
+	KeyFnS                     = 0x1e3
+	KeyFnB                     = 0x1e4
+	KeyFnRightShift            = 0x1e5
+	KeyBrlDot1                 = 0x1f1
+	KeyBrlDot2                 = 0x1f2
+	KeyBrlDot3                 = 0x1f3
+	KeyBrlDot4                 = 0x1f4
+	KeyBrlDot5                 = 0x1f5
+	KeyBrlDot6                 = 0x1f6
+	KeyBrlDot7                 = 0x1f7
+	KeyBrlDot8                 = 0x1f8
+	KeyBrlDot9                 = 0x1f9
+	KeyBrlDot10                = 0x1fa
+	KeyNumeric0                = 0x200 // used by phones, remote controls,
+	KeyNumeric1                = 0x201 // and other keypads
+	KeyNumeric2                = 0x202
+	KeyNumeric3                = 0x203
+	KeyNumeric4                = 0x204
+	KeyNumeric5                = 0x205
+	KeyNumeric6                = 0x206
+	KeyNumeric7                = 0x207
+	KeyNumeric8                = 0x208
+	KeyNumeric9                = 0x209
+	KeyNumericStar             = 0x20a
+	KeyNumericPound            = 0x20b
+	KeyNumericA                = 0x20c // Phone key A - HUT Telephony 0xb9
+	KeyNumericB                = 0x20d
+	KeyNumericC                = 0x20e
+	KeyNumericD                = 0x20f
+	KeyCameraFocus             = 0x210
+	KeyWpsButton               = 0x211 // WiFi Protected Setup key
+	KeyTouchpadToggle          = 0x212 // Request switch touchpad on or off
+	KeyTouchpadOn              = 0x213
+	KeyTouchpadOff             = 0x214
+	KeyCameraZoomin            = 0x215
+	KeyCameraZoomout           = 0x216
+	KeyCameraUp                = 0x217
+	KeyCameraDown              = 0x218
+	KeyCameraLeft              = 0x219
+	KeyCameraRight             = 0x21a
+	KeyAttendantOn             = 0x21b
+	KeyAttendantOff            = 0x21c
+	KeyAttendantToggle         = 0x21d // Attendant call on or off
+	KeyLightsToggle            = 0x21e // Reading light on or off
+	KeyAlsToggle               = 0x230 // Ambient light sensor
+	KeyRotateLockToggle        = 0x231 // Display rotation lock
+	KeyRefreshRateToggle       = 0x232 // Display refresh rate toggle
+	KeyButtonconfig            = 0x240 // AL Button Configuration
+	KeyTaskmanager             = 0x241 // AL Task/Project Manager
+	KeyJournal                 = 0x242 // AL Log/Journal/Timecard
+	KeyControlpanel            = 0x243 // AL Control Panel
+	KeyAppselect               = 0x244 // AL Select Task/Application
+	KeyScreensaver             = 0x245 // AL Screen Saver
+	KeyVoicecommand            = 0x246 // Listening Voice Command
+	KeyAssistant               = 0x247 // AL Context-aware desktop assistant
+	KeyKbdLayoutNext           = 0x248 // AC Next Keyboard Layout Select
+	KeyEmojiPicker             = 0x249 // Show/hide emoji picker (HUTRR101)
+	KeyDictate                 = 0x24a // Start or Stop Voice Dictation Session (HUTRR99)
+	KeyBrightnessMin           = 0x250 // Set Brightness to Minimum
+	KeyKbdinputassistPrev      = 0x260
+	KeyKbdinputassistNext      = 0x261
+	KeyKbdinputassistPrevgroup = 0x262
+	KeyKbdinputassistNextgroup = 0x263
+	KeyKbdinputassistAccept    = 0x264
+	KeyKbdinputassistCancel    = 0x265
+	KeyRightUp                 = 0x266
+	KeyRightDown               = 0x267
+	KeyLeftUp                  = 0x268
+	KeyLeftDown                = 0x269
+	KeyRootMenu                = 0x26a // Show Device's Root Menu
+	KeyMediaTopMenu            = 0x26b
+	KeyNumeric11               = 0x26c
+	KeyNumeric12               = 0x26d
+	KeyAudioDesc               = 0x26e
+	Key3dMode                  = 0x26f
+	KeyNextFavorite            = 0x270
+	KeyStopRecord              = 0x271
+	KeyPauseRecord             = 0x272
+	KeyVod                     = 0x273 // Video on Demand
+	KeyUnmute                  = 0x274
+	KeyFastreverse             = 0x275
+	KeySlowreverse             = 0x276
+	KeyData                    = 0x277
+	KeyOnscreenKeyboard        = 0x278
+	KeyPrivacyScreenToggle     = 0x279
+	KeySelectiveScreenshot     = 0x27a
+	KeyNextElement             = 0x27b
+	KeyPreviousElement         = 0x27c
+	KeyAutopilotEngageToggle   = 0x27d
+	KeyMarkWaypoint            = 0x27e
+	KeySos                     = 0x27f
+	KeyNavChart                = 0x280
+	KeyFishingChart            = 0x281
+	KeySingleRangeRadar        = 0x282
+	KeyDualRangeRadar          = 0x283
+	KeyRadarOverlay            = 0x284
+	KeyTraditionalSonar        = 0x285
+	KeyClearvuSonar            = 0x286
+	KeySidevuSonar             = 0x287
+	KeyNavInfo                 = 0x288
+	KeyBrightnessMenu          = 0x289
+	KeyMacro1                  = 0x290
+	KeyMacro2                  = 0x291
+	KeyMacro3                  = 0x292
+	KeyMacro4                  = 0x293
+	KeyMacro5                  = 0x294
+	KeyMacro6                  = 0x295
+	KeyMacro7                  = 0x296
+	KeyMacro8                  = 0x297
+	KeyMacro9                  = 0x298
+	KeyMacro10                 = 0x299
+	KeyMacro11                 = 0x29a
+	KeyMacro12                 = 0x29b
+	KeyMacro13                 = 0x29c
+	KeyMacro14                 = 0x29d
+	KeyMacro15                 = 0x29e
+	KeyMacro16                 = 0x29f
+	KeyMacro17                 = 0x2a0
+	KeyMacro18                 = 0x2a1
+	KeyMacro19                 = 0x2a2
+	KeyMacro20                 = 0x2a3
+	KeyMacro21                 = 0x2a4
+	KeyMacro22                 = 0x2a5
+	KeyMacro23                 = 0x2a6
+	KeyMacro24                 = 0x2a7
+	KeyMacro25                 = 0x2a8
+	KeyMacro26                 = 0x2a9
+	KeyMacro27                 = 0x2aa
+	KeyMacro28                 = 0x2ab
+	KeyMacro29                 = 0x2ac
+	KeyMacro30                 = 0x2ad
+	KeyMacroRecordStart        = 0x2b0
+	KeyMacroRecordStop         = 0x2b1
+	KeyMacroPresetCycle        = 0x2b2
+	KeyMacroPreset1            = 0x2b3
+	KeyMacroPreset2            = 0x2b4
+	KeyMacroPreset3            = 0x2b5
+	KeyKbdLcdMenu1             = 0x2b8
+	KeyKbdLcdMenu2             = 0x2b9
+	KeyKbdLcdMenu3             = 0x2ba
+	KeyKbdLcdMenu4             = 0x2bb
+	KeyKbdLcdMenu5             = 0x2bc
+	KeyMinInteresting          = KeyMute
+)
 
-	ButtonSouth = 0x130 // A / X
-	ButtonEast  = 0x131 // X / Square
-	ButtonNorth = 0x133 // Y / Triangle
-	ButtonWest  = 0x134 // B / Circle
+// the constants that are defined here relate 1:1 to the BTN_* constants defined in input-event-codes.h
+// and represent the button codes that can be triggered as key events
+const (
+	BtnMisc           = 0x100
+	Btn0              = 0x100
+	Btn1              = 0x101
+	Btn2              = 0x102
+	Btn3              = 0x103
+	Btn4              = 0x104
+	Btn5              = 0x105
+	Btn6              = 0x106
+	Btn7              = 0x107
+	Btn8              = 0x108
+	Btn9              = 0x109
+	BtnMouse          = 0x110
+	BtnLeft           = 0x110
+	BtnRight          = 0x111
+	BtnMiddle         = 0x112
+	BtnSide           = 0x113
+	BtnExtra          = 0x114
+	BtnForward        = 0x115
+	BtnBack           = 0x116
+	BtnTask           = 0x117
+	BtnJoystick       = 0x120
+	BtnTrigger        = 0x120
+	BtnThumb          = 0x121
+	BtnThumb2         = 0x122
+	BtnTop            = 0x123
+	BtnTop2           = 0x124
+	BtnPinkie         = 0x125
+	BtnBase           = 0x126
+	BtnBase2          = 0x127
+	BtnBase3          = 0x128
+	BtnBase4          = 0x129
+	BtnBase5          = 0x12a
+	BtnBase6          = 0x12b
+	BtnDead           = 0x12f
+	BtnGamepad        = 0x130
+	BtnSouth          = 0x130
+	BtnA              = BtnSouth
+	BtnEast           = 0x131
+	BtnB              = BtnEast
+	BtnC              = 0x132
+	BtnNorth          = 0x133
+	BtnX              = BtnNorth
+	BtnWest           = 0x134
+	BtnY              = BtnWest
+	BtnZ              = 0x135
+	BtnTl             = 0x136
+	BtnTr             = 0x137
+	BtnTl2            = 0x138
+	BtnTr2            = 0x139
+	BtnSelect         = 0x13a
+	BtnStart          = 0x13b
+	BtnMode           = 0x13c
+	BtnThumbl         = 0x13d
+	BtnThumbr         = 0x13e
+	BtnDigi           = 0x140
+	BtnToolPen        = 0x140
+	BtnToolRubber     = 0x141
+	BtnToolBrush      = 0x142
+	BtnToolPencil     = 0x143
+	BtnToolAirbrush   = 0x144
+	BtnToolFinger     = 0x145
+	BtnToolMouse      = 0x146
+	BtnToolLens       = 0x147
+	BtnToolQuinttap   = 0x148 // Five fingers on trackpad
+	BtnStylus3        = 0x149
+	BtnTouch          = 0x14a
+	BtnStylus         = 0x14b
+	BtnStylus2        = 0x14c
+	BtnToolDoubletap  = 0x14d
+	BtnToolTripletap  = 0x14e
+	BtnToolQuadtap    = 0x14f // Four fingers on trackpad
+	BtnWheel          = 0x150
+	BtnGearDown       = 0x150
+	BtnGearUp         = 0x151
+	BtnDpadUp         = 0x220
+	BtnDpadDown       = 0x221
+	BtnDpadLeft       = 0x222
+	BtnDpadRight      = 0x223
+	BtnTriggerHappy   = 0x2c0
+	BtnTriggerHappy1  = 0x2c0
+	BtnTriggerHappy2  = 0x2c1
+	BtnTriggerHappy3  = 0x2c2
+	BtnTriggerHappy4  = 0x2c3
+	BtnTriggerHappy5  = 0x2c4
+	BtnTriggerHappy6  = 0x2c5
+	BtnTriggerHappy7  = 0x2c6
+	BtnTriggerHappy8  = 0x2c7
+	BtnTriggerHappy9  = 0x2c8
+	BtnTriggerHappy10 = 0x2c9
+	BtnTriggerHappy11 = 0x2ca
+	BtnTriggerHappy12 = 0x2cb
+	BtnTriggerHappy13 = 0x2cc
+	BtnTriggerHappy14 = 0x2cd
+	BtnTriggerHappy15 = 0x2ce
+	BtnTriggerHappy16 = 0x2cf
+	BtnTriggerHappy17 = 0x2d0
+	BtnTriggerHappy18 = 0x2d1
+	BtnTriggerHappy19 = 0x2d2
+	BtnTriggerHappy20 = 0x2d3
+	BtnTriggerHappy21 = 0x2d4
+	BtnTriggerHappy22 = 0x2d5
+	BtnTriggerHappy23 = 0x2d6
+	BtnTriggerHappy24 = 0x2d7
+	BtnTriggerHappy25 = 0x2d8
+	BtnTriggerHappy26 = 0x2d9
+	BtnTriggerHappy27 = 0x2da
+	BtnTriggerHappy28 = 0x2db
+	BtnTriggerHappy29 = 0x2dc
+	BtnTriggerHappy30 = 0x2dd
+	BtnTriggerHappy31 = 0x2de
+	BtnTriggerHappy32 = 0x2df
+	BtnTriggerHappy33 = 0x2e0
+	BtnTriggerHappy34 = 0x2e1
+	BtnTriggerHappy35 = 0x2e2
+	BtnTriggerHappy36 = 0x2e3
+	BtnTriggerHappy37 = 0x2e4
+	BtnTriggerHappy38 = 0x2e5
+	BtnTriggerHappy39 = 0x2e6
+	BtnTriggerHappy40 = 0x2e7
+)
 
-	ButtonBumperLeft   = 0x136 // L1
-	ButtonBumperRight  = 0x137 // R1
-	ButtonTriggerLeft  = 0x138 // L2
-	ButtonTriggerRight = 0x139 // R2
-	ButtonThumbLeft    = 0x13d // L3
-	ButtonThumbRight   = 0x13e // R3
+// the constants that are defined here relate 1:1 to the REL_* constants defined in input-event-codes.h
+// and represent the relative axes that can be reported as relative events
+const (
+	RelX           = 0x00
+	RelY           = 0x01
+	RelZ           = 0x02
+	RelRx          = 0x03
+	RelRy          = 0x04
+	RelRz          = 0x05
+	RelHwheel      = 0x06
+	RelDial        = 0x07
+	RelWheel       = 0x08
+	RelMisc        = 0x09
+	RelReserved    = 0x0a
+	RelWheelHiRes  = 0x0b
+	RelHwheelHiRes = 0x0c
+)
 
-	ButtonSelect = 0x13a
-	ButtonStart  = 0x13b
+// the constants that are defined here relate 1:1 to the ABS_* constants defined in input-event-codes.h
+// and represent the absolute axes that can be reported as absolute events
+const (
+	AbsX             = 0x00
+	AbsY             = 0x01
+	AbsZ             = 0x02
+	AbsRx            = 0x03
+	AbsRy            = 0x04
+	AbsRz            = 0x05
+	AbsThrottle      = 0x06
+	AbsRudder        = 0x07
+	AbsWheel         = 0x08
+	AbsGas           = 0x09
+	AbsBrake         = 0x0a
+	AbsHat0x         = 0x10
+	AbsHat0y         = 0x11
+	AbsHat1x         = 0x12
+	AbsHat1y         = 0x13
+	AbsHat2x         = 0x14
+	AbsHat2y         = 0x15
+	AbsHat3x         = 0x16
+	AbsHat3y         = 0x17
+	AbsPressure      = 0x18
+	AbsDistance      = 0x19
+	AbsTiltX         = 0x1a
+	AbsTiltY         = 0x1b
+	AbsToolWidth     = 0x1c
+	AbsVolume        = 0x20
+	AbsProfile       = 0x21
+	AbsMisc          = 0x28
+	AbsReserved      = 0x2e
+	AbsMtSlot        = 0x2f // MT slot being modified
+	AbsMtTouchMajor  = 0x30 // Major axis of touching ellipse
+	AbsMtTouchMinor  = 0x31 // Minor axis (omit if circular)
+	AbsMtWidthMajor  = 0x32 // Major axis of approaching ellipse
+	AbsMtWidthMinor  = 0x33 // Minor axis (omit if circular)
+	AbsMtOrientation = 0x34 // Ellipse orientation
+	AbsMtPositionX   = 0x35 // Center X touch position
+	AbsMtPositionY   = 0x36 // Center Y touch position
+	AbsMtToolType    = 0x37 // Type of touching device
+	AbsMtBlobId      = 0x38 // Group a set of packets as a blob
+	AbsMtTrackingId  = 0x39 // Unique ID of initiated contact
+	AbsMtPressure    = 0x3a // Pressure on contact area
+	AbsMtDistance    = 0x3b // Contact hover distance
+	AbsMtToolX       = 0x3c // Center X tool position
+	AbsMtToolY       = 0x3d // Center Y tool position
+)
 
-	ButtonDpadUp    = 0x220
-	ButtonDpadDown  = 0x221
-	ButtonDpadLeft  = 0x222
-	ButtonDpadRight = 0x223
+// the constants that are defined here relate 1:1 to the SW_* constants defined in input-event-codes.h
+// and represent the switches that can be reported as switch events
+const (
+	SwLid                = 0x00        // set = lid shut
+	SwTabletMode         = 0x01        // set = tablet mode
+	SwHeadphoneInsert    = 0x02        // set = inserted
+	SwRfkillAll          = 0x03        // rfkill master switch, type "any"
+	SwRadio              = SwRfkillAll // deprecated
+	SwMicrophoneInsert   = 0x04        // set = inserted
+	SwDock               = 0x05        // set = plugged into dock
+	SwLineoutInsert      = 0x06        // set = inserted
+	SwJackPhysicalInsert = 0x07        // set = mechanical switch set
+	SwVideooutInsert     = 0x08        // set = inserted
+	SwCameraLensCover    = 0x09        // set = lens covered
+	SwKeypadSlide        = 0x0a        // set = keypad slide out
+	SwFrontProximity     = 0x0b        // set = front proximity sensor active
+	SwRotateLock         = 0x0c        // set = rotate locked/disabled
+	SwLineinInsert       = 0x0d        // set = inserted
+	SwMuteDevice         = 0x0e        // set = device disabled
+	SwPenInserted        = 0x0f        // set = pen inserted
+	SwMachineCover       = 0x10        // set = cover closed
+)
 
-	ButtonMode = 0x13c // This is the special button that usually bears the Xbox or Playstation logo
+// the constants that are defined here relate 1:1 to the LED_* constants defined in input-event-codes.h
+// and represent the LED states a host may send back to a device
+const (
+	LedNuml     = 0x00
+	LedCapsl    = 0x01
+	LedScrolll  = 0x02
+	LedCompose  = 0x03
+	LedKana     = 0x04
+	LedSleep    = 0x05
+	LedSuspend  = 0x06
+	LedMute     = 0x07
+	LedMisc     = 0x08
+	LedMail     = 0x09
+	LedCharging = 0x0a
 )
+
+// keyInfos lists the keyboard keys along with their names, sorted by code.
+var keyInfos = []KeyInfo{
+	{KeyEsc, "KEY_ESC"},
+	{Key1, "KEY_1"},
+	{Key2, "KEY_2"},
+	{Key3, "KEY_3"},
+	{Key4, "KEY_4"},
+	{Key5, "KEY_5"},
+	{Key6, "KEY_6"},
+	{Key7, "KEY_7"},
+	{Key8, "KEY_8"},
+	{Key9, "KEY_9"},
+	{Key0, "KEY_0"},
+	{KeyMinus, "KEY_MINUS"},
+	{KeyEqual, "KEY_EQUAL"},
+	{KeyBackspace, "KEY_BACKSPACE"},
+	{KeyTab, "KEY_TAB"},
+	{KeyQ, "KEY_Q"},
+	{KeyW, "KEY_W"},
+	{KeyE, "KEY_E"},
+	{KeyR, "KEY_R"},
+	{KeyT, "KEY_T"},
+	{KeyY, "KEY_Y"},
+	{KeyU, "KEY_U"},
+	{KeyI, "KEY_I"},
+	{KeyO, "KEY_O"},
+	{KeyP, "KEY_P"},
+	{KeyLeftbrace, "KEY_LEFTBRACE"},
+	{KeyRightbrace, "KEY_RIGHTBRACE"},
+	{KeyEnter, "KEY_ENTER"},
+	{KeyLeftctrl, "KEY_LEFTCTRL"},
+	{KeyA, "KEY_A"},
+	{KeyS, "KEY_S"},
+	{KeyD, "KEY_D"},
+	{KeyF, "KEY_F"},
+	{KeyG, "KEY_G"},
+	{KeyH, "KEY_H"},
+	{KeyJ, "KEY_J"},
+	{KeyK, "KEY_K"},
+	{KeyL, "KEY_L"},
+	{KeySemicolon, "KEY_SEMICOLON"},
+	{KeyApostrophe, "KEY_APOSTROPHE"},
+	{KeyGrave, "KEY_GRAVE"},
+	{KeyLeftshift, "KEY_LEFTSHIFT"},
+	{KeyBackslash, "KEY_BACKSLASH"},
+	{KeyZ, "KEY_Z"},
+	{KeyX, "KEY_X"},
+	{KeyC, "KEY_C"},
+	{KeyV, "KEY_V"},
+	{KeyB, "KEY_B"},
+	{KeyN, "KEY_N"},
+	{KeyM, "KEY_M"},
+	{KeyComma, "KEY_COMMA"},
+	{KeyDot, "KEY_DOT"},
+	{KeySlash, "KEY_SLASH"},
+	{KeyRightshift, "KEY_RIGHTSHIFT"},
+	{KeyKpasterisk, "KEY_KPASTERISK"},
+	{KeyLeftalt, "KEY_LEFTALT"},
+	{KeySpace, "KEY_SPACE"},
+	{KeyCapslock, "KEY_CAPSLOCK"},
+	{KeyF1, "KEY_F1"},
+	{KeyF2, "KEY_F2"},
+	{KeyF3, "KEY_F3"},
+	{KeyF4, "KEY_F4"},
+	{KeyF5, "KEY_F5"},
+	{KeyF6, "KEY_F6"},
+	{KeyF7, "KEY_F7"},
+	{KeyF8, "KEY_F8"},
+	{KeyF9, "KEY_F9"},
+	{KeyF10, "KEY_F10"},
+	{KeyNumlock, "KEY_NUMLOCK"},
+	{KeyScrolllock, "KEY_SCROLLLOCK"},
+	{KeyKp7, "KEY_KP7"},
+	{KeyKp8, "KEY_KP8"},
+	{KeyKp9, "KEY_KP9"},
+	{KeyKpminus, "KEY_KPMINUS"},
+	{KeyKp4, "KEY_KP4"},
+	{KeyKp5, "KEY_KP5"},
+	{KeyKp6, "KEY_KP6"},
+	{KeyKpplus, "KEY_KPPLUS"},
+	{KeyKp1, "KEY_KP1"},
+	{KeyKp2, "KEY_KP2"},
+	{KeyKp3, "KEY_KP3"},
+	{KeyKp0, "KEY_KP0"},
+	{KeyKpdot, "KEY_KPDOT"},
+	{KeyZenkakuhankaku, "KEY_ZENKAKUHANKAKU"},
+	{Key102Nd, "KEY_102ND"},
+	{KeyF11, "KEY_F11"},
+	{KeyF12, "KEY_F12"},
+	{KeyRo, "KEY_RO"},
+	{KeyKatakana, "KEY_KATAKANA"},
+	{KeyHiragana, "KEY_HIRAGANA"},
+	{KeyHenkan, "KEY_HENKAN"},
+	{KeyKatakanahiragana, "KEY_KATAKANAHIRAGANA"},
+	{KeyMuhenkan, "KEY_MUHENKAN"},
+	{KeyKpjpcomma, "KEY_KPJPCOMMA"},
+	{KeyKpenter, "KEY_KPENTER"},
+	{KeyRightctrl, "KEY_RIGHTCTRL"},
+	{KeyKpslash, "KEY_KPSLASH"},
+	{KeySysrq, "KEY_SYSRQ"},
+	{KeyRightalt, "KEY_RIGHTALT"},
+	{KeyLinefeed, "KEY_LINEFEED"},
+	{KeyHome, "KEY_HOME"},
+	{KeyUp, "KEY_UP"},
+	{KeyPageup, "KEY_PAGEUP"},
+	{KeyLeft, "KEY_LEFT"},
+	{KeyRight, "KEY_RIGHT"},
+	{KeyEnd, "KEY_END"},
+	{KeyDown, "KEY_DOWN"},
+	{KeyPagedown, "KEY_PAGEDOWN"},
+	{KeyInsert, "KEY_INSERT"},
+	{KeyDelete, "KEY_DELETE"},
+	{KeyMacro, "KEY_MACRO"},
+	{KeyMute, "KEY_MUTE"},
+	{KeyVolumedown, "KEY_VOLUMEDOWN"},
+	{KeyVolumeup, "KEY_VOLUMEUP"},
+	{KeyPower, "KEY_POWER"},
+	{KeyKpequal, "KEY_KPEQUAL"},
+	{KeyKpplusminus, "KEY_KPPLUSMINUS"},
+	{KeyPause, "KEY_PAUSE"},
+	{KeyScale, "KEY_SCALE"},
+	{KeyKpcomma, "KEY_KPCOMMA"},
+	{KeyHangeul, "KEY_HANGEUL"},
+	{KeyHanja, "KEY_HANJA"},
+	{KeyYen, "KEY_YEN"},
+	{KeyLeftmeta, "KEY_LEFTMETA"},
+	{KeyRightmeta, "KEY_RIGHTMETA"},
+	{KeyCompose, "KEY_COMPOSE"},
+	{KeyStop, "KEY_STOP"},
+	{KeyAgain, "KEY_AGAIN"},
+	{KeyProps, "KEY_PROPS"},
+	{KeyUndo, "KEY_UNDO"},
+	{KeyFront, "KEY_FRONT"},
+	{KeyCopy, "KEY_COPY"},
+	{KeyOpen, "KEY_OPEN"},
+	{KeyPaste, "KEY_PASTE"},
+	{KeyFind, "KEY_FIND"},
+	{KeyCut, "KEY_CUT"},
+	{KeyHelp, "KEY_HELP"},
+	{KeyMenu, "KEY_MENU"},
+	{KeyCalc, "KEY_CALC"},
+	{KeySetup, "KEY_SETUP"},
+	{KeySleep, "KEY_SLEEP"},
+	{KeyWakeup, "KEY_WAKEUP"},
+	{KeyFile, "KEY_FILE"},
+	{KeySendfile, "KEY_SENDFILE"},
+	{KeyDeletefile, "KEY_DELETEFILE"},
+	{KeyXfer, "KEY_XFER"},
+	{KeyProg1, "KEY_PROG1"},
+	{KeyProg2, "KEY_PROG2"},
+	{KeyWww, "KEY_WWW"},
+	{KeyMsdos, "KEY_MSDOS"},
+	{KeyCoffee, "KEY_COFFEE"},
+	{KeyRotateDisplay, "KEY_ROTATE_DISPLAY"},
+	{KeyCyclewindows, "KEY_CYCLEWINDOWS"},
+	{KeyMail, "KEY_MAIL"},
+	{KeyBookmarks, "KEY_BOOKMARKS"},
+	{KeyComputer, "KEY_COMPUTER"},
+	{KeyBack, "KEY_BACK"},
+	{KeyForward, "KEY_FORWARD"},
+	{KeyClosecd, "KEY_CLOSECD"},
+	{KeyEjectcd, "KEY_EJECTCD"},
+	{KeyEjectclosecd, "KEY_EJECTCLOSECD"},
+	{KeyNextsong, "KEY_NEXTSONG"},
+	{KeyPlaypause, "KEY_PLAYPAUSE"},
+	{KeyPrevioussong, "KEY_PREVIOUSSONG"},
+	{KeyStopcd, "KEY_STOPCD"},
+	{KeyRecord, "KEY_RECORD"},
+	{KeyRewind, "KEY_REWIND"},
+	{KeyPhone, "KEY_PHONE"},
+	{KeyIso, "KEY_ISO"},
+	{KeyConfig, "KEY_CONFIG"},
+	{KeyHomepage, "KEY_HOMEPAGE"},
+	{KeyRefresh, "KEY_REFRESH"},
+	{KeyExit, "KEY_EXIT"},
+	{KeyMove, "KEY_MOVE"},
+	{KeyEdit, "KEY_EDIT"},
+	{KeyScrollup, "KEY_SCROLLUP"},
+	{KeyScrolldown, "KEY_SCROLLDOWN"},
+	{KeyKpleftparen, "KEY_KPLEFTPAREN"},
+	{KeyKprightparen, "KEY_KPRIGHTPAREN"},
+	{KeyNew, "KEY_NEW"},
+	{KeyRedo, "KEY_REDO"},
+	{KeyF13, "KEY_F13"},
+	{KeyF14, "KEY_F14"},
+	{KeyF15, "KEY_F15"},
+	{KeyF16, "KEY_F16"},
+	{KeyF17, "KEY_F17"},
+	{KeyF18, "KEY_F18"},
+	{KeyF19, "KEY_F19"},
+	{KeyF20, "KEY_F20"},
+	{KeyF21, "KEY_F21"},
+	{KeyF22, "KEY_F22"},
+	{KeyF23, "KEY_F23"},
+	{KeyF24, "KEY_F24"},
+	{KeyPlaycd, "KEY_PLAYCD"},
+	{KeyPausecd, "KEY_PAUSECD"},
+	{KeyProg3, "KEY_PROG3"},
+	{KeyProg4, "KEY_PROG4"},
+	{KeyAllApplications, "KEY_ALL_APPLICATIONS"},
+	{KeySuspend, "KEY_SUSPEND"},
+	{KeyClose, "KEY_CLOSE"},
+	{KeyPlay, "KEY_PLAY"},
+	{KeyFastforward, "KEY_FASTFORWARD"},
+	{KeyBassboost, "KEY_BASSBOOST"},
+	{KeyPrint, "KEY_PRINT"},
+	{KeyHp, "KEY_HP"},
+	{KeyCamera, "KEY_CAMERA"},
+	{KeySound, "KEY_SOUND"},
+	{KeyQuestion, "KEY_QUESTION"},
+	{KeyEmail, "KEY_EMAIL"},
+	{KeyChat, "KEY_CHAT"},
+	{KeySearch, "KEY_SEARCH"},
+	{KeyConnect, "KEY_CONNECT"},
+	{KeyFinance, "KEY_FINANCE"},
+	{KeySport, "KEY_SPORT"},
+	{KeyShop, "KEY_SHOP"},
+	{KeyAlterase, "KEY_ALTERASE"},
+	{KeyCancel, "KEY_CANCEL"},
+	{KeyBrightnessdown, "KEY_BRIGHTNESSDOWN"},
+	{KeyBrightnessup, "KEY_BRIGHTNESSUP"},
+	{KeyMedia, "KEY_MEDIA"},
+	{KeySwitchvideomode, "KEY_SWITCHVIDEOMODE"},
+	{KeyKbdillumtoggle, "KEY_KBDILLUMTOGGLE"},
+	{KeyKbdillumdown, "KEY_KBDILLUMDOWN"},
+	{KeyKbdillumup, "KEY_KBDILLUMUP"},
+	{KeySend, "KEY_SEND"},
+	{KeyReply, "KEY_REPLY"},
+	{KeyForwardmail, "KEY_FORWARDMAIL"},
+	{KeySave, "KEY_SAVE"},
+	{KeyDocuments, "KEY_DOCUMENTS"},
+	{KeyBattery, "KEY_BATTERY"},
+	{KeyBluetooth, "KEY_BLUETOOTH"},
+	{KeyWlan, "KEY_WLAN"},
+	{KeyUwb, "KEY_UWB"},
+	{KeyUnknown, "KEY_UNKNOWN"},
+	{KeyVideoNext, "KEY_VIDEO_NEXT"},
+	{KeyVideoPrev, "KEY_VIDEO_PREV"},
+	{KeyBrightnessCycle, "KEY_BRIGHTNESS_CYCLE"},
+	{KeyBrightnessAuto, "KEY_BRIGHTNESS_AUTO"},
+	{KeyDisplayOff, "KEY_DISPLAY_OFF"},
+	{KeyWwan, "KEY_WWAN"},
+	{KeyRfkill, "KEY_RFKILL"},
+	{KeyMicmute, "KEY_MICMUTE"},
+}
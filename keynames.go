@@ -0,0 +1,108 @@
+package uinput
+
+//go:generate go run mkkeycodes.go -o keycodes.go /usr/include/linux/input-event-codes.h
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyInfo describes a single keycode along with its name as defined in input-event-codes.h (e.g. "KEY_ESC").
+type KeyInfo struct {
+	Code int
+	Name string
+}
+
+// AllKeys returns all keyboard keycodes defined in keycodes.go along with their names, sorted by code.
+// The returned slice is a copy and may be modified by the caller.
+func AllKeys() []KeyInfo {
+	keys := make([]KeyInfo, len(keyInfos))
+	copy(keys, keyInfos)
+	return keys
+}
+
+// KeyCodeOf returns the keycode of the key with the given name as defined in input-event-codes.h (e.g. "KEY_LEFTCTRL").
+// The name is matched case-insensitively and the "KEY_" prefix may be omitted.
+func KeyCodeOf(name string) (int, error) {
+	code, ok := keyCodes[strings.TrimPrefix(strings.ToUpper(name), "KEY_")]
+	if !ok {
+		return 0, fmt.Errorf("unknown key %q", name)
+	}
+	return code, nil
+}
+
+// KeyName returns the name of the given keycode as defined in input-event-codes.h (e.g. "KEY_LEFTCTRL"), or an empty
+// string if the keycode is unknown.
+func KeyName(code int) string {
+	return keyNames[code]
+}
+
+// keyAliases are the common names of keys that may be used in key combinations, in addition to the names of the keys
+// without the "KEY_" prefix. Modifiers refer to the left one of both keys.
+var keyAliases = map[string]int{
+	"CTRL":    KeyLeftctrl,
+	"CONTROL": KeyLeftctrl,
+	"SHIFT":   KeyLeftshift,
+	"ALT":     KeyLeftalt,
+	"ALTGR":   KeyRightalt,
+	"SUPER":   KeyLeftmeta,
+	"META":    KeyLeftmeta,
+	"WIN":     KeyLeftmeta,
+	"ESCAPE":  KeyEsc,
+	"RETURN":  KeyEnter,
+	"DEL":     KeyDelete,
+	"INS":     KeyInsert,
+	"PGUP":    KeyPageup,
+	"PGDN":    KeyPagedown,
+}
+
+// ParseKeyCombo parses a key combination like "ctrl+shift+t" into its keys, in the given order, as expected by
+// Keyboard.KeyCombo. Keys are given by their names without the "KEY_" prefix (e.g. "f5" or "leftctrl") or by common
+// aliases like ctrl, shift, alt, altgr and super, all matched case-insensitively.
+func ParseKeyCombo(combo string) ([]int, error) {
+	if strings.TrimSpace(combo) == "" {
+		return nil, fmt.Errorf("key combination must not be empty")
+	}
+	var keys []int
+	for _, name := range strings.Split(combo, "+") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		code, ok := keyAliases[name]
+		if !ok {
+			code, ok = keyCodes[strings.TrimPrefix(name, "KEY_")]
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q in key combination %q", name, combo)
+		}
+		keys = append(keys, code)
+	}
+	return keys, nil
+}
+
+// ParseKeySequence parses a sequence of key combinations separated by whitespace (e.g. "ctrl+a ctrl+c"), see
+// ParseKeyCombo.
+func ParseKeySequence(sequence string) ([][]int, error) {
+	fields := strings.Fields(sequence)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("key sequence must not be empty")
+	}
+	combos := make([][]int, len(fields))
+	for i, field := range fields {
+		keys, err := ParseKeyCombo(field)
+		if err != nil {
+			return nil, err
+		}
+		combos[i] = keys
+	}
+	return combos, nil
+}
+
+// keyCodes and keyNames index keyInfos by name (without the "KEY_" prefix) and by code.
+var keyCodes, keyNames = func() (map[string]int, map[int]string) {
+	codes := make(map[string]int, len(keyInfos))
+	names := make(map[int]string, len(keyInfos))
+	for _, info := range keyInfos {
+		codes[strings.TrimPrefix(info.Name, "KEY_")] = info.Code
+		names[info.Code] = info.Name
+	}
+	return codes, names
+}()
@@ -0,0 +1,108 @@
+package uinput
+
+import "testing"
+
+func TestAllKeysIsSortedAndUnique(t *testing.T) {
+	keys := AllKeys()
+	if len(keys) == 0 {
+		t.Fatalf("Expected a list of keys, but got none")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1].Code >= keys[i].Code {
+			t.Fatalf("Keys are not sorted by code: %v followed by %v", keys[i-1], keys[i])
+		}
+	}
+}
+
+func TestAllKeysAreInRange(t *testing.T) {
+	for _, key := range AllKeys() {
+		if !keyCodeInRange(key.Code) {
+			t.Fatalf("Key %v is not in range", key)
+		}
+		if key.Name == "" {
+			t.Fatalf("Key %d has no name", key.Code)
+		}
+	}
+}
+
+func TestAllKeysReturnsCopy(t *testing.T) {
+	keys := AllKeys()
+	keys[0].Name = "bogus"
+	if AllKeys()[0].Name == "bogus" {
+		t.Fatalf("Expected AllKeys to return a copy, but the internal table was modified")
+	}
+}
+
+func TestAllKeysContainsKnownKeys(t *testing.T) {
+	expected := map[int]string{KeyEsc: "KEY_ESC", KeyLeftctrl: "KEY_LEFTCTRL", KeyMicmute: "KEY_MICMUTE"}
+	for _, key := range AllKeys() {
+		if name, ok := expected[key.Code]; ok {
+			if name != key.Name {
+				t.Fatalf("Expected name %s for code %d, but got %s", name, key.Code, key.Name)
+			}
+			delete(expected, key.Code)
+		}
+	}
+	if len(expected) != 0 {
+		t.Fatalf("Keys missing from AllKeys: %v", expected)
+	}
+}
+
+func TestKeyCodeOfAndKeyNameRoundTrip(t *testing.T) {
+	for _, key := range AllKeys() {
+		code, err := KeyCodeOf(key.Name)
+		if err != nil || code != key.Code {
+			t.Fatalf("Expected code %d for %s, but got %d (error %v)", key.Code, key.Name, code, err)
+		}
+		if KeyName(key.Code) != key.Name {
+			t.Fatalf("Expected name %s for code %d, but got %s", key.Name, key.Code, KeyName(key.Code))
+		}
+	}
+}
+
+func TestKeyCodeOfIsLenient(t *testing.T) {
+	for _, name := range []string{"KEY_LEFTCTRL", "key_leftctrl", "leftctrl"} {
+		code, err := KeyCodeOf(name)
+		if err != nil || code != KeyLeftctrl {
+			t.Fatalf("Expected %q to resolve to KeyLeftctrl, but got %d (error %v)", name, code, err)
+		}
+	}
+	if _, err := KeyCodeOf("KEY_BOGUS"); err == nil {
+		t.Fatalf("Expected an unknown key to fail, but got no error.")
+	}
+	if KeyName(-1) != "" {
+		t.Fatalf("Expected no name for an unknown code")
+	}
+}
+
+func TestParseKeyCombo(t *testing.T) {
+	keys, err := ParseKeyCombo("Ctrl+Shift+t")
+	if err != nil {
+		t.Fatalf("Failed to parse key combination: %v", err)
+	}
+	expected := []int{KeyLeftctrl, KeyLeftshift, KeyT}
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected keys %v, but got %v", expected, keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Fatalf("Expected keys %v, but got %v", expected, keys)
+		}
+	}
+
+	for _, combo := range []string{"", "ctrl+", "ctrl+bogus"} {
+		if _, err := ParseKeyCombo(combo); err == nil {
+			t.Fatalf("Expected %q to fail, but got no error.", combo)
+		}
+	}
+}
+
+func TestParseKeySequence(t *testing.T) {
+	combos, err := ParseKeySequence("ctrl+a  ctrl+c\tf5")
+	if err != nil {
+		t.Fatalf("Failed to parse key sequence: %v", err)
+	}
+	if len(combos) != 3 || combos[1][1] != KeyC || combos[2][0] != KeyF5 {
+		t.Fatalf("Expected three combinations, but got %v", combos)
+	}
+}
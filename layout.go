@@ -0,0 +1,199 @@
+package uinput
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A KeyStroke is a single key press, which is optionally performed while holding down a modifier key (e.g.
+// KeyLeftshift for upper case letters). A Modifier of 0 means that no modifier is needed.
+type KeyStroke struct {
+	Key      int
+	Modifier int
+}
+
+// A Layout maps characters to the key strokes that are needed to type them. The mapping depends on the keyboard layout
+// that is configured in the OS, since the same key results in different characters depending on the layout (e.g. KeyZ
+// types z on a US keyboard, but y on a German one).
+type Layout interface {
+	// KeyStrokes returns the key strokes that type the given character. If the character cannot be typed using the
+	// layout, ok will be false.
+	KeyStrokes(r rune) (strokes []KeyStroke, ok bool)
+}
+
+// KeyMap is a Layout that is backed by a map, which allows to define custom layouts (or to extend an existing one)
+// without implementing the Layout interface.
+type KeyMap map[rune][]KeyStroke
+
+// KeyStrokes returns the key strokes that type the given character.
+func (m KeyMap) KeyStrokes(r rune) ([]KeyStroke, bool) {
+	strokes, ok := m[r]
+	return strokes, ok
+}
+
+// LayoutUS is the US (QWERTY) layout.
+var LayoutUS Layout = newKeyMap(usRows, "")
+
+var usRows = []layoutRow{
+	{keys: []int{KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual},
+		plain: "`1234567890-=",
+		shift: "~!@#$%^&*()_+"},
+	{keys: []int{KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftbrace, KeyRightbrace, KeyBackslash},
+		plain: `qwertyuiop[]\`,
+		shift: "QWERTYUIOP{}|"},
+	{keys: []int{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe},
+		plain: "asdfghjkl;'",
+		shift: `ASDFGHJKL:"`},
+	{keys: []int{KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyDot, KeySlash},
+		plain: "zxcvbnm,./",
+		shift: "ZXCVBNM<>?"},
+}
+
+// LayoutDE is the German (QWERTZ) layout. Note that ^, ´ and ` are dead keys, so they are typed by pressing the key
+// followed by a space.
+var LayoutDE Layout = newKeyMap(deRows, "^´`")
+
+var deRows = []layoutRow{
+	{keys: []int{KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual},
+		plain: "^1234567890ß´",
+		shift: "°!\"§$%&/()=?`",
+		altGr: "  ²³   {[]}\\ "},
+	{keys: []int{KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftbrace, KeyRightbrace},
+		plain: "qwertzuiopü+",
+		shift: "QWERTZUIOPÜ*",
+		altGr: "@ €        ~"},
+	{keys: []int{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe, KeyBackslash},
+		plain: "asdfghjklöä#",
+		shift: "ASDFGHJKLÖÄ'"},
+	{keys: []int{Key102Nd, KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyDot, KeySlash},
+		plain: "<yxcvbnm,.-",
+		shift: ">YXCVBNM;:_",
+		altGr: "|      µ   "},
+}
+
+// LayoutFR is the French (AZERTY) layout. Note that ~, ` and ¨ are dead keys, so they are typed by pressing the key
+// followed by a space.
+var LayoutFR Layout = newKeyMap(frRows, "~`¨")
+
+var frRows = []layoutRow{
+	{keys: []int{KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual},
+		plain: "²&é\"'(-è_çà)=",
+		shift: " 1234567890°+",
+		altGr: "  ~#{[|`\\^@]}"},
+	{keys: []int{KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftbrace, KeyRightbrace},
+		plain: "azertyuiop^$",
+		shift: "AZERTYUIOP¨£",
+		altGr: "  €        ¤"},
+	{keys: []int{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe, KeyBackslash},
+		plain: "qsdfghjklmù*",
+		shift: "QSDFGHJKLM%µ"},
+	{keys: []int{Key102Nd, KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyDot, KeySlash},
+		plain: "<wxcvbn,;:!",
+		shift: ">WXCVBN?./§"},
+}
+
+// LayoutUK is the British (QWERTY) layout.
+var LayoutUK Layout = newKeyMap(ukRows, "")
+
+var ukRows = []layoutRow{
+	{keys: []int{KeyGrave, Key1, Key2, Key3, Key4, Key5, Key6, Key7, Key8, Key9, Key0, KeyMinus, KeyEqual},
+		plain: "`1234567890-=",
+		shift: "¬!\"£$%^&*()_+",
+		altGr: "¦   €        "},
+	{keys: []int{KeyQ, KeyW, KeyE, KeyR, KeyT, KeyY, KeyU, KeyI, KeyO, KeyP, KeyLeftbrace, KeyRightbrace},
+		plain: "qwertyuiop[]",
+		shift: "QWERTYUIOP{}"},
+	{keys: []int{KeyA, KeyS, KeyD, KeyF, KeyG, KeyH, KeyJ, KeyK, KeyL, KeySemicolon, KeyApostrophe, KeyBackslash},
+		plain: "asdfghjkl;'#",
+		shift: "ASDFGHJKL:@~"},
+	{keys: []int{Key102Nd, KeyZ, KeyX, KeyC, KeyV, KeyB, KeyN, KeyM, KeyComma, KeyDot, KeySlash},
+		plain: `\zxcvbnm,./`,
+		shift: "|ZXCVBNM<>?"},
+}
+
+var (
+	layoutsMu sync.RWMutex
+	layouts   = map[string]Layout{
+		"us": LayoutUS,
+		"de": LayoutDE,
+		"fr": LayoutFR,
+		"uk": LayoutUK,
+	}
+)
+
+// RegisterLayout makes the given layout available under the given name (see LookupLayout), e.g. in order to select
+// the layout based on a configuration value. The built-in layouts are registered as "us", "de", "fr" and "uk". A name
+// may only be registered once.
+func RegisterLayout(name string, layout Layout) error {
+	if name == "" {
+		return fmt.Errorf("layout name must not be empty")
+	}
+	if layout == nil {
+		return fmt.Errorf("layout %s must not be nil", name)
+	}
+	layoutsMu.Lock()
+	defer layoutsMu.Unlock()
+	if _, exists := layouts[name]; exists {
+		return fmt.Errorf("a layout named %s is already registered", name)
+	}
+	layouts[name] = layout
+	return nil
+}
+
+// LookupLayout returns the layout that is registered under the given name.
+func LookupLayout(name string) (Layout, bool) {
+	layoutsMu.RLock()
+	defer layoutsMu.RUnlock()
+	layout, ok := layouts[name]
+	return layout, ok
+}
+
+// layoutRow describes the characters that the given keys type, without any modifier, with shift and with AltGr held
+// down. The characters are listed in the same order as the keys, a space marks a key that types no character.
+type layoutRow struct {
+	keys  []int
+	plain string
+	shift string
+	altGr string
+}
+
+// newKeyMap builds a KeyMap from the given rows of a layout. Characters listed in deadKeys are typed by dead keys, so
+// they are followed by a space to type the character itself. Space, tab and newline are the same for all layouts.
+func newKeyMap(rows []layoutRow, deadKeys string) KeyMap {
+	m := KeyMap{
+		' ':  {{Key: KeySpace}},
+		'\t': {{Key: KeyTab}},
+		'\n': {{Key: KeyEnter}},
+	}
+	dead := make(map[rune]bool)
+	for _, r := range deadKeys {
+		dead[r] = true
+	}
+
+	for _, row := range rows {
+		for _, level := range []struct {
+			chars    string
+			modifier int
+		}{
+			{row.plain, 0},
+			{row.shift, KeyLeftshift},
+			{row.altGr, KeyRightalt},
+		} {
+			chars := []rune(level.chars)
+			for i, key := range row.keys {
+				if i >= len(chars) || chars[i] == ' ' {
+					continue
+				}
+				if _, exists := m[chars[i]]; exists {
+					continue
+				}
+				strokes := []KeyStroke{{Key: key, Modifier: level.modifier}}
+				if dead[chars[i]] {
+					strokes = append(strokes, KeyStroke{Key: KeySpace})
+				}
+				m[chars[i]] = strokes
+			}
+		}
+	}
+	return m
+}
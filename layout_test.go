@@ -0,0 +1,122 @@
+package uinput
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLayoutRowsMatchKeys(t *testing.T) {
+	for name, rows := range map[string][]layoutRow{"us": usRows, "de": deRows, "fr": frRows, "uk": ukRows} {
+		for i, row := range rows {
+			for _, chars := range []string{row.plain, row.shift, row.altGr} {
+				if chars != "" && len([]rune(chars)) != len(row.keys) {
+					t.Fatalf("Expected %d characters in row %d of layout %s, but got %d (%q)", len(row.keys), i, name, len([]rune(chars)), chars)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutUSKeyStrokes(t *testing.T) {
+	for r, expected := range map[rune][]KeyStroke{
+		'a':  {{Key: KeyA}},
+		'A':  {{Key: KeyA, Modifier: KeyLeftshift}},
+		'?':  {{Key: KeySlash, Modifier: KeyLeftshift}},
+		'\\': {{Key: KeyBackslash}},
+		'\n': {{Key: KeyEnter}},
+	} {
+		strokes, ok := LayoutUS.KeyStrokes(r)
+		if !ok || fmt.Sprint(strokes) != fmt.Sprint(expected) {
+			t.Fatalf("Expected key strokes %v for %q, but got %v", expected, r, strokes)
+		}
+	}
+	if _, ok := LayoutUS.KeyStrokes('é'); ok {
+		t.Fatalf("Expected é to be unsupported by the US layout")
+	}
+}
+
+func TestNewKeyMapAppendsSpaceToDeadKeys(t *testing.T) {
+	m := newKeyMap([]layoutRow{{keys: []int{KeyGrave, Key1}, plain: "^1", shift: "° "}}, "^")
+
+	strokes, ok := m.KeyStrokes('^')
+	expected := []KeyStroke{{Key: KeyGrave}, {Key: KeySpace}}
+	if !ok || fmt.Sprint(strokes) != fmt.Sprint(expected) {
+		t.Fatalf("Expected key strokes %v for a dead key, but got %v", expected, strokes)
+	}
+	if _, ok := m.KeyStrokes('!'); ok {
+		t.Fatalf("Expected no mapping for a key marked as empty")
+	}
+}
+
+func TestLayoutDEKeyStrokes(t *testing.T) {
+	for r, expected := range map[rune][]KeyStroke{
+		'z': {{Key: KeyY}},
+		'Y': {{Key: KeyZ, Modifier: KeyLeftshift}},
+		'ß': {{Key: KeyMinus}},
+		'@': {{Key: KeyQ, Modifier: KeyRightalt}},
+		'^': {{Key: KeyGrave}, {Key: KeySpace}},
+	} {
+		strokes, ok := LayoutDE.KeyStrokes(r)
+		if !ok || fmt.Sprint(strokes) != fmt.Sprint(expected) {
+			t.Fatalf("Expected key strokes %v for %q, but got %v", expected, r, strokes)
+		}
+	}
+}
+
+func TestLayoutFRKeyStrokes(t *testing.T) {
+	for r, expected := range map[rune][]KeyStroke{
+		'a': {{Key: KeyQ}},
+		'1': {{Key: Key1, Modifier: KeyLeftshift}},
+		'm': {{Key: KeySemicolon}},
+		'^': {{Key: Key9, Modifier: KeyRightalt}},
+	} {
+		strokes, ok := LayoutFR.KeyStrokes(r)
+		if !ok || fmt.Sprint(strokes) != fmt.Sprint(expected) {
+			t.Fatalf("Expected key strokes %v for %q, but got %v", expected, r, strokes)
+		}
+	}
+}
+
+func TestLayoutUKKeyStrokes(t *testing.T) {
+	for r, expected := range map[rune][]KeyStroke{
+		'£':  {{Key: Key3, Modifier: KeyLeftshift}},
+		'@':  {{Key: KeyApostrophe, Modifier: KeyLeftshift}},
+		'#':  {{Key: KeyBackslash}},
+		'\\': {{Key: Key102Nd}},
+	} {
+		strokes, ok := LayoutUK.KeyStrokes(r)
+		if !ok || fmt.Sprint(strokes) != fmt.Sprint(expected) {
+			t.Fatalf("Expected key strokes %v for %q, but got %v", expected, r, strokes)
+		}
+	}
+}
+
+func TestRegisterLayout(t *testing.T) {
+	custom := KeyMap{'x': {{Key: KeyX}}}
+	err := RegisterLayout("test-custom", custom)
+	if err != nil {
+		t.Fatalf("Failed to register layout: %v", err)
+	}
+	layout, ok := LookupLayout("test-custom")
+	if !ok {
+		t.Fatalf("Expected the registered layout to be found")
+	}
+	if _, ok := layout.KeyStrokes('x'); !ok {
+		t.Fatalf("Expected the registered layout to be returned")
+	}
+
+	if err = RegisterLayout("test-custom", custom); err == nil {
+		t.Fatalf("Expected registering a layout twice to fail, but got no error.")
+	}
+	if err = RegisterLayout("", custom); err == nil {
+		t.Fatalf("Expected registering a layout without a name to fail, but got no error.")
+	}
+	if err = RegisterLayout("test-nil", nil); err == nil {
+		t.Fatalf("Expected registering a nil layout to fail, but got no error.")
+	}
+	for _, name := range []string{"us", "de", "fr", "uk"} {
+		if _, ok := LookupLayout(name); !ok {
+			t.Fatalf("Expected built-in layout %s to be registered", name)
+		}
+	}
+}
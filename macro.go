@@ -0,0 +1,172 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// A MacroEvent is a single event of a macro, along with the time it occurred at (relative to the start of the macro).
+type MacroEvent struct {
+	Time  time.Duration
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// A Macro is a recorded sequence of timed events, which can be played back on any device. Macros are either recorded
+// using a MacroRecorder or captured from an existing input device using RecordEventNode. Sync events are part of the
+// macro, so that events are played back in the same frames they were recorded in.
+type Macro struct {
+	Events []MacroEvent
+}
+
+// A RawEventSender is a device raw events can be sent to, like any of the devices in this package.
+type RawEventSender interface {
+	SendRawEvent(evType uint16, code uint16, value int32) error
+}
+
+// Duration returns the time from the start of the macro until its last event.
+func (m *Macro) Duration() time.Duration {
+	if len(m.Events) == 0 {
+		return 0
+	}
+	return m.Events[len(m.Events)-1].Time
+}
+
+// Play will play back the macro on the given device, using the original timing of the events.
+func (m *Macro) Play(dev RawEventSender) error {
+	return m.PlayScaledCtx(context.Background(), dev, 1)
+}
+
+// PlayCtx will play back the macro just like Play, but stops once the given context is done (see PlayScaledCtx).
+func (m *Macro) PlayCtx(ctx context.Context, dev RawEventSender) error {
+	return m.PlayScaledCtx(ctx, dev, 1)
+}
+
+// PlayScaled will play back the macro on the given device, with the timing of the events scaled by the given speed
+// (e.g. 2 to play the macro twice as fast as it was recorded). Since the time of each event is measured from the start
+// of the playback, delays caused by sending events do not add up over the course of the macro.
+func (m *Macro) PlayScaled(dev RawEventSender, speed float64) error {
+	return m.PlayScaledCtx(context.Background(), dev, speed)
+}
+
+// PlayScaledCtx will play back the macro just like PlayScaled, but stops once the given context is done, in which case
+// the error of the context is returned. Keys that have been pressed by the macro, but not released yet are released
+// when stopping, so that no keys are left stuck.
+func (m *Macro) PlayScaledCtx(ctx context.Context, dev RawEventSender, speed float64) error {
+	if dev == nil {
+		return fmt.Errorf("failed to play macro. The device must not be nil")
+	}
+	if speed <= 0 {
+		return fmt.Errorf("failed to play macro. Speed %v is not positive", speed)
+	}
+
+	held := make(heldKeys)
+	start := time.Now()
+	for _, ev := range m.Events {
+		err := sleepUntil(ctx, start.Add(time.Duration(float64(ev.Time)/speed)))
+		if err != nil {
+			return held.releaseAfter(dev, err)
+		}
+		err = dev.SendRawEvent(ev.Type, ev.Code, ev.Value)
+		if err != nil {
+			return fmt.Errorf("failed to play macro event %+v: %w", ev, err)
+		}
+		held.track(ev.Type, ev.Code, ev.Value)
+	}
+	return nil
+}
+
+// A MacroRecorder records the events it is given, along with the time they were given at. The recorder implements
+// RawEventSender, so it may take the place of a device in order to record a macro from API calls.
+type MacroRecorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	events []MacroEvent
+}
+
+// NewMacroRecorder returns a recorder, measuring the time of events from now on.
+func NewMacroRecorder() *MacroRecorder {
+	return &MacroRecorder{start: time.Now()}
+}
+
+// SendRawEvent records a single event of the given type and code.
+func (r *MacroRecorder) SendRawEvent(evType uint16, code uint16, value int32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, MacroEvent{Time: time.Since(r.start), Type: evType, Code: code, Value: value})
+	return nil
+}
+
+// KeyDown records a key press of the given key, including the scan code for media keys.
+func (r *MacroRecorder) KeyDown(key int) error {
+	return r.key(key, btnStatePressed)
+}
+
+// KeyUp records a key release of the given key, including the scan code for media keys.
+func (r *MacroRecorder) KeyUp(key int) error {
+	return r.key(key, btnStateReleased)
+}
+
+func (r *MacroRecorder) key(key int, value int32) error {
+	if !keyCodeInRange(key) {
+		return sentinelErrorf(ErrKeyOutOfRange, "failed to record key event. Code %d is not in range", key)
+	}
+	for _, ev := range keyEvents(uint16(key), value) {
+		_ = r.SendRawEvent(ev.Type, ev.Code, ev.Value)
+	}
+	return nil
+}
+
+// Sync records a sync event, which terminates the events recorded since the last sync.
+func (r *MacroRecorder) Sync() error {
+	return r.SendRawEvent(evSyn, synReport, 0)
+}
+
+// Macro returns the events recorded so far.
+func (r *MacroRecorder) Macro() *Macro {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Macro{Events: append([]MacroEvent{}, r.events...)}
+}
+
+// RecordEventNode will capture the events of an existing input device for the given duration, using the timestamps
+// the kernel assigned to the events. The path is the evdev node of the device (e.g. /dev/input/event3, see EventNode),
+// which requires read permissions.
+// Note that events sent to other consumers (like X11 or Wayland compositors) are captured, but not intercepted.
+func RecordEventNode(path string, duration time.Duration) (*Macro, error) {
+	dev, err := OpenEventDevice(path)
+	if err != nil {
+		return nil, err
+	}
+	defer dev.Close()
+	return dev.Record(duration)
+}
+
+func recordEvents(eventFile *os.File, duration time.Duration) (*Macro, error) {
+	m := &Macro{}
+	var first time.Duration
+	deadline := time.Now().Add(duration)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return m, nil
+		}
+		ev, ok, err := readEvent(eventFile, remaining)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		t := time.Duration(ev.Time.Sec)*time.Second + time.Duration(ev.Time.Usec)*time.Microsecond
+		if len(m.Events) == 0 {
+			first = t
+		}
+		m.Events = append(m.Events, MacroEvent{Time: t - first, Type: ev.Type, Code: ev.Code, Value: ev.Value})
+	}
+}
@@ -0,0 +1,123 @@
+package uinput
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRecordedMacroIsPlayedBack(t *testing.T) {
+	rec := NewMacroRecorder()
+	_ = rec.KeyDown(KeyA)
+	_ = rec.Sync()
+	time.Sleep(20 * time.Millisecond)
+	_ = rec.KeyUp(KeyA)
+	_ = rec.Sync()
+	m := rec.Macro()
+
+	if len(m.Events) != 4 {
+		t.Fatalf("Expected 4 recorded events, but got %d", len(m.Events))
+	}
+	if m.Duration() < 20*time.Millisecond {
+		t.Fatalf("Expected the macro to last at least 20ms, but got %v", m.Duration())
+	}
+
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+	err := m.Play(vk)
+	if err != nil {
+		t.Fatalf("Failed to play macro: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestMacroPlaybackIsScaled(t *testing.T) {
+	m := &Macro{Events: []MacroEvent{
+		{Time: 0, Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Time: 100 * time.Millisecond, Type: evKey, Code: KeyA, Value: btnStateReleased},
+	}}
+
+	rec := NewMacroRecorder()
+	err := m.PlayScaled(rec, 4)
+	if err != nil {
+		t.Fatalf("Failed to play macro: %v", err)
+	}
+
+	played := rec.Macro().Duration()
+	if played < 25*time.Millisecond || played > 75*time.Millisecond {
+		t.Fatalf("Expected playback at four times the speed to take about 25ms, but took %v", played)
+	}
+}
+
+func TestMacroPlaybackRejectsInvalidSpeed(t *testing.T) {
+	err := (&Macro{}).PlayScaled(NewMacroRecorder(), 0)
+	if err == nil {
+		t.Fatalf("Expected playback with a speed of 0 to fail")
+	}
+}
+
+func TestEventsAreRecordedWithKernelTimestamps(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	for _, iev := range []inputEvent{
+		{Time: syscall.Timeval{Sec: 10, Usec: 500}, Type: evKey, Code: KeyB, Value: btnStatePressed},
+		{Time: syscall.Timeval{Sec: 11, Usec: 0}, Type: evKey, Code: KeyB, Value: btnStateReleased},
+	} {
+		buf, err := inputEventToBuffer(iev)
+		if err != nil {
+			t.Fatalf("Failed to encode event: %v", err)
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+
+	m, err := recordEvents(r, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to record events: %v", err)
+	}
+	if len(m.Events) != 2 {
+		t.Fatalf("Expected 2 recorded events, but got %d", len(m.Events))
+	}
+	if m.Events[0].Time != 0 || m.Events[1].Time != time.Second-500*time.Microsecond {
+		t.Fatalf("Expected events relative to the first one, but got %+v", m.Events)
+	}
+}
+
+func TestMacroPlaybackIsCancelled(t *testing.T) {
+	m := &Macro{Events: []MacroEvent{
+		{Time: 0, Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Time: time.Second, Type: evKey, Code: KeyA, Value: btnStateReleased},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := m.PlayCtx(ctx, NewMacroRecorder())
+	if err != context.Canceled {
+		t.Fatalf("Expected playback to stop with %v, but got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,178 @@
+//go:build ignore
+// +build ignore
+
+// mkkeycodes generates keycodes.go from the input-event-codes.h header of the kernel. It is invoked by go generate:
+//
+//	go run mkkeycodes.go [-o keycodes.go] [/usr/include/linux/input-event-codes.h]
+//
+// All KEY_*, BTN_*, REL_*, ABS_*, SW_* and LED_* constants are converted to Go constants by camel-casing the name
+// (e.g. KEY_LEFTCTRL becomes KeyLeftctrl and ABS_MT_POSITION_X becomes AbsMtPositionX). Aliases defined in terms of
+// other constants are kept as such.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// groups lists the prefixes of the constants to generate along with the doc comment of their const block.
+var groups = []struct {
+	prefix string
+	doc    string
+}{
+	{"KEY_", "key codes that can be triggered as key events"},
+	{"BTN_", "button codes that can be triggered as key events"},
+	{"REL_", "relative axes that can be reported as relative events"},
+	{"ABS_", "absolute axes that can be reported as absolute events"},
+	{"SW_", "switches that can be reported as switch events"},
+	{"LED_", "LED states a host may send back to a device"},
+}
+
+// renamed lists constants whose Go name does not follow the naming scheme, either because the name predates the
+// generator or because it would collide with another identifier of the package.
+var renamed = map[string]string{
+	"KEY_RESERVED": "keyReserved",
+	"KEY_102ND":    "Key102Nd",
+	"KEY_INFO":     "KeyInformation",
+}
+
+// btnMisc is the first button code. Key codes below it are keyboard keys, which are listed in keyInfos.
+const btnMisc = 0x100
+
+var defineRe = regexp.MustCompile(`^#define\s+([A-Z][A-Z0-9_]*)\s+([A-Za-z0-9_]+)\s*(?:/\*\s*(.*?)\s*(?:\*/)?)?\s*$`)
+
+type constant struct {
+	name    string
+	value   string
+	code    int
+	alias   bool
+	comment string
+}
+
+func main() {
+	out := flag.String("o", "keycodes.go", "output file")
+	flag.Parse()
+	header := "/usr/include/linux/input-event-codes.h"
+	if flag.NArg() > 0 {
+		header = flag.Arg(0)
+	}
+
+	consts, err := parseHeader(header)
+	if err != nil {
+		log.Fatalf("failed to parse %s: %v", header, err)
+	}
+	src, err := generate(consts)
+	if err != nil {
+		log.Fatalf("failed to generate source: %v", err)
+	}
+	err = ioutil.WriteFile(*out, src, 0644)
+	if err != nil {
+		log.Fatalf("failed to write %s: %v", *out, err)
+	}
+}
+
+// parseHeader reads all #define lines of the header that define a constant of one of the generated groups.
+func parseHeader(path string) ([]constant, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	codes := make(map[string]int)
+	var consts []constant
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := defineRe.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil || !generated(m[1]) {
+			continue
+		}
+		c := constant{name: m[1], value: m[2], comment: m[3]}
+		if code, err := strconv.ParseInt(c.value, 0, 32); err == nil {
+			c.code = int(code)
+		} else if code, ok := codes[c.value]; ok {
+			c.code = code
+			c.alias = true
+		} else {
+			return nil, fmt.Errorf("%s is defined as unknown value %s", c.name, c.value)
+		}
+		codes[c.name] = c.code
+		consts = append(consts, c)
+	}
+	return consts, scanner.Err()
+}
+
+// generated reports whether a constant belongs to one of the groups. The _MAX and _CNT bounds are left out, since
+// they change with every new code.
+func generated(name string) bool {
+	if strings.HasSuffix(name, "_MAX") || strings.HasSuffix(name, "_CNT") {
+		return false
+	}
+	for _, g := range groups {
+		if strings.HasPrefix(name, g.prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// goName converts the name of a constant to camel case, e.g. KEY_VIDEO_NEXT becomes KeyVideoNext.
+func goName(name string) string {
+	if n, ok := renamed[name]; ok {
+		return n
+	}
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(part[:1])
+		b.WriteString(strings.ToLower(part[1:]))
+	}
+	return b.String()
+}
+
+func generate(consts []constant) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by mkkeycodes.go from input-event-codes.h; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package uinput\n")
+
+	for _, g := range groups {
+		fmt.Fprintf(&buf, "\n// the constants that are defined here relate 1:1 to the %s* constants defined in input-event-codes.h\n"+
+			"// and represent the %s\nconst (\n", g.prefix, g.doc)
+		for _, c := range consts {
+			if !strings.HasPrefix(c.name, g.prefix) {
+				continue
+			}
+			value := c.value
+			if c.alias {
+				value = goName(c.value)
+			}
+			fmt.Fprintf(&buf, "\t%s = %s", goName(c.name), value)
+			if c.comment != "" {
+				fmt.Fprintf(&buf, " // %s", c.comment)
+			}
+			fmt.Fprintf(&buf, "\n")
+		}
+		fmt.Fprintf(&buf, ")\n")
+	}
+
+	fmt.Fprintf(&buf, "\n// keyInfos lists the keyboard keys along with their names, sorted by code.\nvar keyInfos = []KeyInfo{\n")
+	for _, c := range consts {
+		if strings.HasPrefix(c.name, "KEY_") && !c.alias && c.code > 0 && c.code < btnMisc {
+			fmt.Fprintf(&buf, "\t{%s, %q},\n", goName(c.name), c.name)
+		}
+	}
+	fmt.Fprintf(&buf, "}\n")
+
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,240 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// An Easing maps the progress of a movement in time (from 0 to 1) to the progress of the movement in space (from 0 to
+// 1). This allows movements to speed up and slow down like the movements of a human hand.
+type Easing func(progress float64) float64
+
+// EaseLinear moves at a constant speed.
+func EaseLinear(progress float64) float64 {
+	return progress
+}
+
+// EaseInOut speeds up at the beginning of a movement and slows down towards its end. This is the default easing used by
+// smooth movements (see WithEasing).
+func EaseInOut(progress float64) float64 {
+	return progress * progress * (3 - 2*progress)
+}
+
+// EaseOut starts a movement at full speed and slows down towards its end.
+func EaseOut(progress float64) float64 {
+	return 1 - (1-progress)*(1-progress)
+}
+
+// motionInterval is the time between two steps of a smooth movement, which matches the polling rate of common mice
+// (125Hz).
+const motionInterval = 8 * time.Millisecond
+
+// interpolate splits a movement of the given duration into steps that are motionInterval apart and invokes the given
+// function for each step with the eased progress of the movement. The last step is always invoked with a progress of 1.
+// The movement stops once the given context is done, in which case the error of the context is returned.
+func interpolate(ctx context.Context, duration time.Duration, easing Easing, step func(progress float64) error) error {
+	if easing == nil {
+		easing = EaseInOut
+	}
+	steps := int(duration / motionInterval)
+	if steps < 1 {
+		steps = 1
+	}
+
+	start := time.Now()
+	for i := 1; i <= steps; i++ {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+		progress := 1.0
+		if i < steps {
+			progress = easing(float64(i) / float64(steps))
+		}
+		err = step(progress)
+		if err != nil {
+			return err
+		}
+		if i < steps {
+			err = sleepUntil(ctx, start.Add(time.Duration(i)*duration/time.Duration(steps)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MoveSmooth will move the mouse pointer by the given distance in many small steps spread over the given duration,
+// instead of a single jump. Games and remote desktop software may refuse large jumps, and jumps do not look like a
+// human moving a mouse. The steps are eased using the easing set with WithEasing.
+func (vRel *vMouse) MoveSmooth(dx int32, dy int32, duration time.Duration) error {
+	return vRel.MoveSmoothCtx(context.Background(), dx, dy, duration)
+}
+
+// MoveSmoothCtx will move the mouse pointer just like MoveSmooth, but stops moving once the given context is done, in
+// which case the error of the context is returned. The pointer stays where the movement was stopped.
+func (vRel *vMouse) MoveSmoothCtx(ctx context.Context, dx int32, dy int32, duration time.Duration) error {
+	var movedX, movedY int32
+	err := interpolate(ctx, duration, vRel.easing, func(progress float64) error {
+		x := int32(math.Round(float64(dx) * progress))
+		y := int32(math.Round(float64(dy) * progress))
+		vRel.mu.Lock()
+		defer vRel.mu.Unlock()
+		err := sendRelMove(vRel.deviceFile, x-movedX, y-movedY)
+		if err != nil {
+			return err
+		}
+		movedX, movedY = x, y
+		return nil
+	})
+	if err != nil && err != ctx.Err() {
+		return fmt.Errorf("failed to perform MoveSmooth: %w", err)
+	}
+	return err
+}
+
+// GlideTo will move the cursor from its current position to the given position in many small steps spread over the
+// given duration (see Mouse.MoveSmooth). If the cursor has not been positioned using MoveTo (or GlideTo) before, it
+// is moved to the given position right away, since its current position is unknown.
+func (vTouch *vTouchPad) GlideTo(x int32, y int32, duration time.Duration) error {
+	return vTouch.GlideToCtx(context.Background(), x, y, duration)
+}
+
+// GlideToCtx will move the cursor just like GlideTo, but stops moving once the given context is done, in which case the
+// error of the context is returned. The cursor stays where the movement was stopped.
+func (vTouch *vTouchPad) GlideToCtx(ctx context.Context, x int32, y int32, duration time.Duration) error {
+	vTouch.mu.Lock()
+	if !vTouch.positioned {
+		defer vTouch.mu.Unlock()
+		return vTouch.moveTo(x, y)
+	}
+	startX, startY := vTouch.x, vTouch.y
+	vTouch.mu.Unlock()
+
+	err := interpolate(ctx, duration, vTouch.easing, func(progress float64) error {
+		vTouch.mu.Lock()
+		defer vTouch.mu.Unlock()
+		return vTouch.moveTo(
+			startX+int32(math.Round(float64(x-startX)*progress)),
+			startY+int32(math.Round(float64(y-startY)*progress)))
+	})
+	if err != nil && err != ctx.Err() {
+		return fmt.Errorf("failed to perform GlideTo: %w", err)
+	}
+	return err
+}
+
+// dragTiming holds the delays applied by drag and drop operations (see WithDragTiming).
+type dragTiming struct {
+	hold     time.Duration
+	duration time.Duration
+}
+
+// Drag will press the left button, move the mouse pointer smoothly by the given distance (see MoveSmooth) and release
+// the button again, pausing for a moment after the press and before the release (see WithDragTiming). The button is
+// released even if the movement fails.
+func (vRel *vMouse) Drag(dx int32, dy int32) error {
+	return vRel.DragCtx(context.Background(), dx, dy)
+}
+
+// DragCtx will drag just like Drag, but stops once the given context is done, in which case the button is released
+// right away and the error of the context is returned.
+func (vRel *vMouse) DragCtx(ctx context.Context, dx int32, dy int32) error {
+	err := vRel.LeftPress()
+	if err != nil {
+		return fmt.Errorf("failed to perform Drag: %w", err)
+	}
+
+	err = sleepUntil(ctx, time.Now().Add(vRel.drag.hold))
+	if err == nil {
+		err = vRel.MoveSmoothCtx(ctx, dx, dy, vRel.drag.duration)
+	}
+	if err == nil {
+		err = sleepUntil(ctx, time.Now().Add(vRel.drag.hold))
+	}
+	if err != nil {
+		return releaseDragButton(ctx, "Drag", err, vRel.LeftRelease)
+	}
+
+	err = vRel.LeftRelease()
+	if err != nil {
+		return fmt.Errorf("failed to perform Drag: %w", err)
+	}
+	return nil
+}
+
+// DragTo will move the cursor to the first position, press the left button, glide to the second position (see GlideTo)
+// and release the button again, pausing for a moment after the press and before the release (see WithDragTiming). The
+// button is released even if the movement fails.
+func (vTouch *vTouchPad) DragTo(x1 int32, y1 int32, x2 int32, y2 int32) error {
+	return vTouch.DragToCtx(context.Background(), x1, y1, x2, y2)
+}
+
+// DragToCtx will drag just like DragTo, but stops once the given context is done, in which case the button is
+// released right away and the error of the context is returned.
+func (vTouch *vTouchPad) DragToCtx(ctx context.Context, x1 int32, y1 int32, x2 int32, y2 int32) error {
+	err := ctx.Err()
+	if err != nil {
+		return err
+	}
+	err = vTouch.MoveTo(x1, y1)
+	if err != nil {
+		return fmt.Errorf("failed to perform DragTo: %w", err)
+	}
+	err = vTouch.LeftPress()
+	if err != nil {
+		return fmt.Errorf("failed to perform DragTo: %w", err)
+	}
+
+	err = sleepUntil(ctx, time.Now().Add(vTouch.drag.hold))
+	if err == nil {
+		err = vTouch.GlideToCtx(ctx, x2, y2, vTouch.drag.duration)
+	}
+	if err == nil {
+		err = sleepUntil(ctx, time.Now().Add(vTouch.drag.hold))
+	}
+	if err != nil {
+		return releaseDragButton(ctx, "DragTo", err, vTouch.LeftRelease)
+	}
+
+	err = vTouch.LeftRelease()
+	if err != nil {
+		return fmt.Errorf("failed to perform DragTo: %w", err)
+	}
+	return nil
+}
+
+// releaseDragButton releases the button of a drag operation that failed or was stopped, and returns the error that
+// stopped the operation. The error of the context is returned as is, just like by the other functions taking a
+// context.
+func releaseDragButton(ctx context.Context, operation string, err error, release func() error) error {
+	_ = release()
+	if err == ctx.Err() {
+		return err
+	}
+	return fmt.Errorf("failed to perform %s: %w", operation, err)
+}
+
+// sendRelMove emits a relative movement along both axes within a single frame. Zero values are left out, since they
+// are dropped by the kernel anyway.
+func sendRelMove(deviceFile *device, x int32, y int32) error {
+	if x == 0 && y == 0 {
+		return nil
+	}
+	for _, iev := range []inputEvent{
+		{Type: evRel, Code: relX, Value: x},
+		{Type: evRel, Code: relY, Value: y},
+	} {
+		if iev.Value == 0 {
+			continue
+		}
+		err := writeEvent(deviceFile, iev)
+		if err != nil {
+			return fmt.Errorf("failed to write rel event to device file: %w", err)
+		}
+	}
+	return syncEvents(deviceFile)
+}
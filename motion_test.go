@@ -0,0 +1,242 @@
+package uinput
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEasingsStartAtZeroAndEndAtOne(t *testing.T) {
+	for name, easing := range map[string]Easing{"linear": EaseLinear, "in-out": EaseInOut, "out": EaseOut} {
+		if easing(0) != 0 || easing(1) != 1 {
+			t.Fatalf("Expected %s easing to map 0 to 0 and 1 to 1, but got %v and %v", name, easing(0), easing(1))
+		}
+		for p := 0.1; p < 1; p += 0.1 {
+			if easing(p) < easing(p-0.1) {
+				t.Fatalf("Expected %s easing to never move backwards at %v", name, p)
+			}
+		}
+	}
+}
+
+func TestInterpolateSpreadsStepsOverDuration(t *testing.T) {
+	var progress []float64
+	start := time.Now()
+	err := interpolate(context.Background(), 10*motionInterval, EaseLinear, func(p float64) error {
+		progress = append(progress, p)
+		return nil
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("Failed to interpolate: %v", err)
+	}
+	if len(progress) != 10 || progress[len(progress)-1] != 1 {
+		t.Fatalf("Expected 10 steps ending at 1, but got %v", progress)
+	}
+	if elapsed < 9*motionInterval {
+		t.Fatalf("Expected steps to be spread over the duration, but it took %v", elapsed)
+	}
+}
+
+func TestInterpolateJumpsWithoutDuration(t *testing.T) {
+	var progress []float64
+	err := interpolate(context.Background(), 0, nil, func(p float64) error {
+		progress = append(progress, p)
+		return nil
+	})
+	if err != nil || len(progress) != 1 || progress[0] != 1 {
+		t.Fatalf("Expected a single step, but got %v (error %v)", progress, err)
+	}
+}
+
+func TestMoveSmoothEmitsSmallRelativeSteps(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}, easing: EaseInOut}
+
+	err := vRel.MoveSmooth(300, -100, 20*motionInterval)
+	if err != nil {
+		t.Fatalf("Failed to move smoothly: %v", err)
+	}
+
+	var x, y, frames int32
+	for _, ev := range readTestEvents(t, file) {
+		switch {
+		case ev.Type == evSyn:
+			frames++
+		case ev.Type == evRel && ev.Code == relX:
+			if ev.Value > 30 {
+				t.Fatalf("Expected small steps, but got a step of %d", ev.Value)
+			}
+			x += ev.Value
+		case ev.Type == evRel && ev.Code == relY:
+			y += ev.Value
+		}
+	}
+	if x != 300 || y != -100 {
+		t.Fatalf("Expected a total movement of (300, -100), but got (%d, %d)", x, y)
+	}
+	if frames < 15 {
+		t.Fatalf("Expected the movement to be split into many frames, but got %d", frames)
+	}
+}
+
+func TestGlideToEndsAtTargetPosition(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vTouch := &vTouchPad{deviceFile: &device{File: file}, easing: EaseLinear}
+
+	if err := vTouch.GlideTo(100, 100, 10*motionInterval); err != nil {
+		t.Fatalf("Failed to glide: %v", err)
+	}
+	if len(readTestEvents(t, file)) != 3 {
+		t.Fatalf("Expected the first glide to jump to the target position")
+	}
+
+	if err := vTouch.GlideTo(200, 50, 10*motionInterval); err != nil {
+		t.Fatalf("Failed to glide: %v", err)
+	}
+	var xs, ys []int32
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evAbs && ev.Code == absX {
+			xs = append(xs, ev.Value)
+		}
+		if ev.Type == evAbs && ev.Code == absY {
+			ys = append(ys, ev.Value)
+		}
+	}
+	if len(xs) != 11 {
+		t.Fatalf("Expected 11 positions, but got %d", len(xs))
+	}
+	if xs[1] != 110 || xs[10] != 200 || ys[10] != 50 {
+		t.Fatalf("Expected a linear glide to (200, 50), but got x %v and y %v", xs, ys)
+	}
+}
+
+func TestDragHoldsLeftButtonDuringMovement(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}, easing: EaseLinear, drag: dragTiming{hold: time.Millisecond, duration: 5 * motionInterval}}
+
+	err := vRel.Drag(50, 0)
+	if err != nil {
+		t.Fatalf("Failed to drag: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	first, last := events[0], events[len(events)-2]
+	if first.Type != evKey || first.Code != evMouseBtnLeft || first.Value != btnStatePressed {
+		t.Fatalf("Expected drag to start with a press of the left button, but got %+v", first)
+	}
+	if last.Type != evKey || last.Code != evMouseBtnLeft || last.Value != btnStateReleased {
+		t.Fatalf("Expected drag to end with a release of the left button, but got %+v", last)
+	}
+	var x int32
+	for _, ev := range events {
+		if ev.Type == evRel && ev.Code == relX {
+			x += ev.Value
+		}
+	}
+	if x != 50 {
+		t.Fatalf("Expected a movement by 50 while dragging, but got %d", x)
+	}
+}
+
+func TestDragToMovesToStartBeforePressing(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vTouch := &vTouchPad{deviceFile: &device{File: file}, easing: EaseLinear, drag: dragTiming{hold: time.Millisecond, duration: 5 * motionInterval}}
+
+	err := vTouch.DragTo(10, 20, 110, 220)
+	if err != nil {
+		t.Fatalf("Failed to drag: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expectedStart := []inputEvent{
+		{Type: evAbs, Code: absX, Value: 10},
+		{Type: evAbs, Code: absY, Value: 20},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: evMouseBtnLeft, Value: btnStatePressed},
+	}
+	for i, want := range expectedStart {
+		if events[i].Type != want.Type || events[i].Code != want.Code || events[i].Value != want.Value {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, want, events[i])
+		}
+	}
+	last := events[len(events)-2]
+	if last.Code != evMouseBtnLeft || last.Value != btnStateReleased {
+		t.Fatalf("Expected drag to end with a release of the left button, but got %+v", last)
+	}
+	if vTouch.x != 110 || vTouch.y != 220 {
+		t.Fatalf("Expected cursor to end at (110, 220), but got (%d, %d)", vTouch.x, vTouch.y)
+	}
+}
+
+func TestMoveSmoothCtxStopsOnCancellation(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}, easing: EaseLinear}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*motionInterval)
+	defer cancel()
+	start := time.Now()
+	err := vRel.MoveSmoothCtx(ctx, 1000, 0, 100*motionInterval)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected: %v\nActual: %v", context.DeadlineExceeded, err)
+	}
+	if time.Since(start) > 50*motionInterval {
+		t.Fatalf("Expected the movement to stop once the context is done, but it took %v", time.Since(start))
+	}
+
+	var x int32
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evRel && ev.Code == relX {
+			x += ev.Value
+		}
+	}
+	if x <= 0 || x >= 1000 {
+		t.Fatalf("Expected the movement to be stopped part of the way, but moved by %d", x)
+	}
+}
+
+func TestDragCtxReleasesButtonOnCancellation(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}, easing: EaseLinear, drag: dragTiming{hold: time.Millisecond, duration: 100 * motionInterval}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*motionInterval)
+	defer cancel()
+	err := vRel.DragCtx(ctx, 1000, 0)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected: %v\nActual: %v", context.DeadlineExceeded, err)
+	}
+
+	events := readTestEvents(t, file)
+	last := events[len(events)-2]
+	if last.Type != evKey || last.Code != evMouseBtnLeft || last.Value != btnStateReleased {
+		t.Fatalf("Expected the left button to be released after cancellation, but got %+v", last)
+	}
+}
+
+func TestDragToCtxReleasesButtonOnCancellation(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vTouch := &vTouchPad{deviceFile: &device{File: file}, easing: EaseLinear, drag: dragTiming{hold: time.Millisecond, duration: 100 * motionInterval}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*motionInterval)
+	defer cancel()
+	err := vTouch.DragToCtx(ctx, 10, 20, 1010, 20)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected: %v\nActual: %v", context.DeadlineExceeded, err)
+	}
+
+	events := readTestEvents(t, file)
+	last := events[len(events)-2]
+	if last.Type != evKey || last.Code != evMouseBtnLeft || last.Value != btnStateReleased {
+		t.Fatalf("Expected the left button to be released after cancellation, but got %+v", last)
+	}
+	if vTouch.x <= 10 || vTouch.x >= 1010 {
+		t.Fatalf("Expected the cursor to be stopped part of the way, but got x %d", vTouch.x)
+	}
+}
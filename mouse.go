@@ -1,10 +1,12 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"syscall"
+	"time"
 )
 
 // A Mouse is a device that will trigger an absolute change event.
@@ -27,6 +29,18 @@ type Mouse interface {
 	// values will cause a move towards the upper left corner.
 	Move(x, y int32) error
 
+	// MoveSmooth will move the mouse pointer by the given distance in small steps spread over the given duration.
+	MoveSmooth(dx int32, dy int32, duration time.Duration) error
+
+	// MoveSmoothCtx will move the mouse pointer just like MoveSmooth, but stops once the given context is done.
+	MoveSmoothCtx(ctx context.Context, dx int32, dy int32, duration time.Duration) error
+
+	// Drag will press the left button, move the mouse pointer smoothly by the given distance and release the button.
+	Drag(dx int32, dy int32) error
+
+	// DragCtx will drag just like Drag, but stops and releases the button once the given context is done.
+	DragCtx(ctx context.Context, dx int32, dy int32) error
+
 	// LeftClick will issue a single left click.
 	LeftClick() error
 
@@ -36,6 +50,13 @@ type Mouse interface {
 	// MiddleClick will issue a middle click.
 	MiddleClick() error
 
+	// DoubleClick will issue a left double click.
+	DoubleClick() error
+
+	// ClickN will issue n clicks of the given button (ButtonLeft, ButtonRight or ButtonMiddle), pausing for the given
+	// interval between two clicks.
+	ClickN(button int, n int, interval time.Duration) error
+
 	// LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
 	// LeftRelease is invoked.
 	LeftPress() error
@@ -60,39 +81,89 @@ type Mouse interface {
 	// Wheel will simulate a wheel movement.
 	Wheel(horizontal bool, delta int32) error
 
+	// ScrollFloat will simulate a vertical wheel movement by a fractional number of notches.
+	ScrollFloat(delta float64) error
+
+	// WheelHiRes will simulate a vertical high-resolution wheel movement, where 120 units amount to a single notch.
+	WheelHiRes(delta int32) error
+
 	// FetchSysPath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
 	io.Closer
 }
 
 type vMouse struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *device
+	wheel      wheelAccumulator
+	hiRes      hiResAccumulator
+	easing     Easing
+	drag       dragTiming
+	onClose    closeHooks
+	mu         deviceMutex
 }
 
 // CreateMouse will create a new mouse input device. A mouse is a device that allows relative input.
 // Relative input means that all changes to the x and y coordinates of the mouse pointer will be
-func CreateMouse(path string, name []byte) (Mouse, error) {
+func CreateMouse(path string, name []byte, opts ...DeviceOption) (Mouse, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
 	}
-	err = validateUinputName(name)
+	return createVMouse(path, name, opts)
+}
+
+// CreateMouseFromFile will create a new mouse just like CreateMouse, but uses the given uinput device file, which has
+// been opened by the caller already. The mouse takes ownership of the file (see CreateKeyboardFromFile).
+func CreateMouseFromFile(file *os.File, name []byte, opts ...DeviceOption) (Mouse, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVMouse(file.Name(), name, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVMouse(path string, name []byte, opts []DeviceOption) (Mouse, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	fd, err := createMouse(path, name)
+	fd, err := createMouse(path, name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return vMouse{name: name, deviceFile: fd}, nil
+	return &vMouse{name: name, deviceFile: fd, easing: options.easing, drag: options.drag, mu: newDeviceMutex(options)}, nil
+}
+
+// NewMouse is the same as CreateMouse, but takes the name as a string, which is truncated if it exceeds 80 bytes (see
+// WithNameTruncation).
+func NewMouse(path string, name string, opts ...DeviceOption) (Mouse, error) {
+	return CreateMouse(path, []byte(name), withStringName(opts)...)
 }
 
 // MoveLeft will move the cursor left by the number of pixel specified.
-func (vRel vMouse) MoveLeft(pixel int32) error {
+func (vRel *vMouse) MoveLeft(pixel int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
@@ -100,7 +171,9 @@ func (vRel vMouse) MoveLeft(pixel int32) error {
 }
 
 // MoveRight will move the cursor right by the number of pixel specified.
-func (vRel vMouse) MoveRight(pixel int32) error {
+func (vRel *vMouse) MoveRight(pixel int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
@@ -108,7 +181,9 @@ func (vRel vMouse) MoveRight(pixel int32) error {
 }
 
 // MoveUp will move the cursor up by the number of pixel specified.
-func (vRel vMouse) MoveUp(pixel int32) error {
+func (vRel *vMouse) MoveUp(pixel int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
@@ -116,7 +191,9 @@ func (vRel vMouse) MoveUp(pixel int32) error {
 }
 
 // MoveDown will move the cursor down by the number of pixel specified.
-func (vRel vMouse) MoveDown(pixel int32) error {
+func (vRel *vMouse) MoveDown(pixel int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	if err := assertNotNegative(pixel); err != nil {
 		return err
 	}
@@ -126,103 +203,229 @@ func (vRel vMouse) MoveDown(pixel int32) error {
 // Move will perform a move of the mouse pointer along the x and y axes relative to the current position as requested.
 // Note that the upper left corner is (0, 0), so positive x and y means moving right (x) and down (y), whereas negative
 // values will cause a move towards the upper left corner.
-func (vRel vMouse) Move(x, y int32) error {
+func (vRel *vMouse) Move(x, y int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	if err := sendRelEvent(vRel.deviceFile, relX, x); err != nil {
-		return fmt.Errorf("Failed to move pointer along x axis: %v", err)
+		return fmt.Errorf("Failed to move pointer along x axis: %w", err)
 	}
 	if err := sendRelEvent(vRel.deviceFile, relY, y); err != nil {
-		return fmt.Errorf("Failed to move pointer along y axis: %v", err)
+		return fmt.Errorf("Failed to move pointer along y axis: %w", err)
 	}
 	return nil
 }
 
+// moveFrame moves the pointer along both axes within a single frame (see FixedRateEmitter).
+func (vRel *vMouse) moveFrame(x, y int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendRelMove(vRel.deviceFile, x, y)
+}
+
 // LeftClick will issue a LeftClick.
-func (vRel vMouse) LeftClick() error {
+func (vRel *vMouse) LeftClick() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	err := sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the LeftClick event: %v", err)
+		return fmt.Errorf("Failed to issue the LeftClick event: %w", err)
 	}
 
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
 }
 
 // RightClick will issue a RightClick
-func (vRel vMouse) RightClick() error {
+func (vRel *vMouse) RightClick() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	err := sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the RightClick event: %v", err)
+		return fmt.Errorf("Failed to issue the RightClick event: %w", err)
 	}
 
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
 }
 
 // MiddleClick will issue a MiddleClick
-func (vRel vMouse) MiddleClick() error {
+func (vRel *vMouse) MiddleClick() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	err := sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("Failed to issue the MiddleClick event: %v", err)
+		return fmt.Errorf("Failed to issue the MiddleClick event: %w", err)
 	}
 
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStateReleased)
 }
 
+const (
+	// clickHold is the time a button is held down during a click. Clicks without any delay between the press and the
+	// release are beyond what a human is capable of and may be ignored as synthetic input.
+	clickHold = 30 * time.Millisecond
+	// doubleClickInterval is the time between the clicks of a double click, which is well below the double click time
+	// of common desktops (400ms to 500ms), yet not suspiciously fast.
+	doubleClickInterval = 100 * time.Millisecond
+)
+
+// DoubleClick will issue a left double click, using an interval between both clicks that desktops recognize as a
+// double click.
+func (vRel *vMouse) DoubleClick() error {
+	return vRel.ClickN(ButtonLeft, 2, doubleClickInterval)
+}
+
+// ClickN will issue n clicks of the given button (ButtonLeft, ButtonRight or ButtonMiddle). Each button is held down for
+// a moment and the given interval is waited for between two clicks. Note that clicks are only recognized as a double
+// (or triple) click if the interval is shorter than the double click time configured in the desktop.
+func (vRel *vMouse) ClickN(button int, n int, interval time.Duration) error {
+	if button != ButtonLeft && button != ButtonRight && button != ButtonMiddle {
+		return fmt.Errorf("failed to perform ClickN. %#x is not a mouse button", button)
+	}
+	if n <= 0 {
+		return fmt.Errorf("failed to perform ClickN. %d is not a valid number of clicks. Expected a positive value", n)
+	}
+	if interval < 0 {
+		return fmt.Errorf("failed to perform ClickN. The interval must not be negative")
+	}
+
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			time.Sleep(interval)
+		}
+		err := vRel.sendButton(button, btnStatePressed)
+		if err != nil {
+			return fmt.Errorf("failed to perform ClickN: %w", err)
+		}
+		time.Sleep(clickHold)
+		err = vRel.sendButton(button, btnStateReleased)
+		if err != nil {
+			return fmt.Errorf("failed to perform ClickN: %w", err)
+		}
+	}
+	return nil
+}
+
+func (vRel *vMouse) sendButton(button int, btnState int) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendBtnEvent(vRel.deviceFile, []int{button}, btnState)
+}
+
 // LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
 // LeftRelease is invoked.
-func (vRel vMouse) LeftPress() error {
+func (vRel *vMouse) LeftPress() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
 }
 
 // LeftRelease will simulate the release of the left mouse button.
-func (vRel vMouse) LeftRelease() error {
+func (vRel *vMouse) LeftRelease() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
 }
 
 // RightPress will simulate the press of the right mouse button. Note that the button will not be released until
 // RightRelease is invoked.
-func (vRel vMouse) RightPress() error {
+func (vRel *vMouse) RightPress() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
 }
 
 // RightRelease will simulate the release of the right mouse button.
-func (vRel vMouse) RightRelease() error {
+func (vRel *vMouse) RightRelease() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
 }
 
 // MiddlePress will simulate the press of the middle mouse button. Note that the button will not be released until
 // MiddleRelease is invoked.
-func (vRel vMouse) MiddlePress() error {
+func (vRel *vMouse) MiddlePress() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStatePressed)
 }
 
 // MiddleRelease will simulate the release of the middle mouse button.
-func (vRel vMouse) MiddleRelease() error {
+func (vRel *vMouse) MiddleRelease() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
 	return sendBtnEvent(vRel.deviceFile, []int{evMouseBtnMiddle}, btnStateReleased)
 }
 
 // Wheel will simulate a wheel movement.
-func (vRel vMouse) Wheel(horizontal bool, delta int32) error {
-	w := relWheel
-	if horizontal {
-		w = relHWheel
+func (vRel *vMouse) Wheel(horizontal bool, delta int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendWheelEvent(vRel.deviceFile, horizontal, delta, delta*hiResPerNotch)
+}
+
+// ScrollFloat will simulate a vertical wheel movement by a fractional number of notches. Fractions are accumulated
+// and a wheel event is only emitted once the accumulated movement amounts to at least one full notch. This allows for
+// smooth, slow scrolling (e.g. 0.25 notches per frame) that would otherwise be rounded away.
+func (vRel *vMouse) ScrollFloat(delta float64) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	notches := vRel.wheel.add(delta)
+	if notches == 0 {
+		return nil
+	}
+	return sendWheelEvent(vRel.deviceFile, false, notches, notches*hiResPerNotch)
+}
+
+// WheelHiRes will simulate a vertical high-resolution wheel movement (REL_WHEEL_HI_RES), which allows for smooth
+// scrolling in consumers that support it (e.g. libinput). A single notch amounts to 120 units, so a delta of 30 will
+// scroll by a quarter of a notch. For consumers that only support regular wheel events, a REL_WHEEL event is emitted
+// along with the high-resolution event each time the accumulated movement amounts to a full notch.
+func (vRel *vMouse) WheelHiRes(delta int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	if delta == 0 {
+		return nil
 	}
-	return sendRelEvent(vRel.deviceFile, uint16(w), delta)
+	return sendWheelEvent(vRel.deviceFile, false, vRel.hiRes.add(delta), delta)
 }
 
 // Close closes the device and releases the device.
-func (vRel vMouse) Close() error {
-	return closeDevice(vRel.deviceFile)
+func (vRel *vMouse) Close() error {
+	err := closeDevice(vRel.deviceFile)
+	vRel.onClose.run()
+	return err
 }
 
-func createMouse(path string, name []byte) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vRel *vMouse) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return sendRawEvent(vRel.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vRel *vMouse) Sync() error {
+	vRel.mu.Lock()
+	defer vRel.mu.Unlock()
+	return syncEvents(vRel.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vRel *vMouse) OnClose(callback func()) {
+	vRel.onClose.add(callback)
+}
+
+func createMouse(path string, name []byte, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
 	if err != nil {
-		return nil, fmt.Errorf("could not create relative axis input device: %v", err)
+		return nil, fmt.Errorf("could not create relative axis input device: %w", err)
 	}
 
 	err = registerDevice(deviceFile, uintptr(evKey))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		return nil, fmt.Errorf("failed to register key device: %w", err)
 	}
 
 	// register button events (in order to enable left, right and middle click)
@@ -230,55 +433,98 @@ func createMouse(path string, name []byte) (fd *os.File, err error) {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register click event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register click event %v: %w", event, err)
 		}
 	}
 
 	err = registerDevice(deviceFile, uintptr(evRel))
 	if err != nil {
 		deviceFile.Close()
-		return nil, fmt.Errorf("failed to register relative axis input device: %v", err)
+		return nil, fmt.Errorf("failed to register relative axis input device: %w", err)
 	}
 
 	// register relative events
-	for _, event := range []int{relX, relY, relWheel, relHWheel} {
+	for _, event := range []int{relX, relY, relWheel, relHWheel, relWheelHiRes, relHWheelHiRes} {
 		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
 		if err != nil {
 			deviceFile.Close()
-			return nil, fmt.Errorf("failed to register relative event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register relative event %v: %w", event, err)
 		}
 	}
 
 	return createUsbDevice(deviceFile,
 		uinputUserDev{
 			Name: toUinputName(name),
-			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  0x4711,
-				Product: 0x0816,
-				Version: 1}})
+			ID:   options.inputID(0x0816)}, options)
 }
 
-func sendRelEvent(deviceFile *os.File, eventCode uint16, pixel int32) error {
+func sendRelEvent(deviceFile *device, eventCode uint16, pixel int32) error {
 	iev := inputEvent{
 		Time:  syscall.Timeval{Sec: 0, Usec: 0},
 		Type:  evRel,
 		Code:  eventCode,
 		Value: pixel}
 
-	buf, err := inputEventToBuffer(iev)
+	err := writeEvent(deviceFile, iev)
 	if err != nil {
-		return fmt.Errorf("writing abs event failed: %v", err)
+		return fmt.Errorf("failed to write rel event to device file: %w", err)
 	}
 
-	_, err = deviceFile.Write(buf)
-	if err != nil {
-		return fmt.Errorf("failed to write rel event to device file: %v", err)
+	return syncEvents(deviceFile)
+}
+
+// sendWheelEvent emits the given wheel movement, both in notches and in high-resolution units, within a single frame.
+// Consumers that support high-resolution scrolling ignore the regular wheel events and vice versa. Zero values are
+// left out, since they are dropped by the kernel anyway.
+func sendWheelEvent(deviceFile *device, horizontal bool, notches int32, hiRes int32) error {
+	wheel, wheelHiRes := uint16(relWheel), uint16(relWheelHiRes)
+	if horizontal {
+		wheel, wheelHiRes = relHWheel, relHWheelHiRes
+	}
+
+	for _, iev := range []inputEvent{
+		{Type: evRel, Code: wheel, Value: notches},
+		{Type: evRel, Code: wheelHiRes, Value: hiRes},
+	} {
+		if iev.Value == 0 {
+			continue
+		}
+		err := writeEvent(deviceFile, iev)
+		if err != nil {
+			return fmt.Errorf("failed to write wheel event to device file: %w", err)
+		}
 	}
 
 	return syncEvents(deviceFile)
 }
 
+// hiResPerNotch is the number of high-resolution wheel units that amount to a single notch (see REL_WHEEL_HI_RES).
+const hiResPerNotch = 120
+
+// A hiResAccumulator sums up high-resolution wheel movements until they amount to full notches.
+type hiResAccumulator int32
+
+// add adds the given delta and returns the number of full notches that are ready to be emitted. The rest is kept for
+// the next call.
+func (h *hiResAccumulator) add(delta int32) int32 {
+	sum := int32(*h) + delta
+	notches := sum / hiResPerNotch
+	*h = hiResAccumulator(sum - notches*hiResPerNotch)
+	return notches
+}
+
+// A wheelAccumulator sums up fractional wheel movements until they amount to full notches.
+type wheelAccumulator float64
+
+// add adds the given delta and returns the number of full notches that are ready to be emitted. The fractional rest is
+// kept for the next call.
+func (w *wheelAccumulator) add(delta float64) int32 {
+	sum := float64(*w) + delta
+	notches := int32(sum)
+	*w = wheelAccumulator(sum - float64(notches))
+	return notches
+}
+
 func assertNotNegative(val int32) error {
 	if val < 0 {
 		return fmt.Errorf("%v is out of range. Expected a positive or zero value", val)
@@ -286,6 +532,11 @@ func assertNotNegative(val int32) error {
 	return nil
 }
 
-func (vRel vMouse) FetchSyspath() (string, error) {
-	return fetchSyspath(vRel.deviceFile)
+func (vRel *vMouse) FetchSyspath() (string, error) {
+	return lookupSyspath(vRel.deviceFile.File, vRel.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vRel *vMouse) EventNode() (string, error) {
+	return lookupEventNode(vRel.deviceFile.File, vRel.name)
 }
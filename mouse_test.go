@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 )
 
 // This test confirms that all basic mouse moves are working as expected.
@@ -440,3 +441,220 @@ func TestMouseSyspath(t *testing.T) {
 	}
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestWheelAccumulatorEmitsFullNotches(t *testing.T) {
+	var w wheelAccumulator
+	for i, expected := range []int32{0, 0, 0, 1, 0, 0, 0, 1} {
+		notches := w.add(0.25)
+		if notches != expected {
+			t.Fatalf("Expected %d notches after step %d, but got %d", expected, i, notches)
+		}
+	}
+}
+
+func TestWheelAccumulatorHandlesNegativeDeltas(t *testing.T) {
+	var w wheelAccumulator
+	if notches := w.add(-0.75); notches != 0 {
+		t.Fatalf("Expected no notches, but got %d", notches)
+	}
+	if notches := w.add(-1.5); notches != -2 {
+		t.Fatalf("Expected -2 notches, but got %d", notches)
+	}
+	if notches := w.add(0.25); notches != 0 {
+		t.Fatalf("Expected no notches after the direction changed, but got %d", notches)
+	}
+}
+
+func TestMouseScrollFloat(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Smooth Scroll Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	for i := 0; i < 8; i++ {
+		err = relDev.ScrollFloat(0.5)
+		if err != nil {
+			t.Fatalf("Failed to scroll. Last error was: %s\n", err)
+		}
+	}
+}
+
+func TestMouseScrollFloatFailsOnClosedDevice(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test Smooth Scroll Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	_ = relDev.Close()
+
+	err = relDev.ScrollFloat(1)
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
+func TestHiResAccumulatorKeepsRest(t *testing.T) {
+	var h hiResAccumulator
+	var notches int32
+	for i := 0; i < 8; i++ {
+		notches += h.add(30)
+	}
+	if notches != 2 || h != 0 {
+		t.Fatalf("Expected 2 notches without rest, but got %d notches and a rest of %d", notches, h)
+	}
+	if n := h.add(-150); n != -1 || h != -30 {
+		t.Fatalf("Expected -1 notch with a rest of -30, but got %d notches and a rest of %d", n, h)
+	}
+}
+
+func TestWheelHiResEmitsNotchOnceComplete(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}}
+
+	for i := 0; i < 2; i++ {
+		err := vRel.WheelHiRes(60)
+		if err != nil {
+			t.Fatalf("Failed to scroll: %v", err)
+		}
+	}
+	err := vRel.Wheel(true, -1)
+	if err != nil {
+		t.Fatalf("Failed to scroll: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evRel, Code: relWheelHiRes, Value: 60},
+		{Type: evSyn, Code: synReport},
+		{Type: evRel, Code: relWheel, Value: 1},
+		{Type: evRel, Code: relWheelHiRes, Value: 60},
+		{Type: evSyn, Code: synReport},
+		{Type: evRel, Code: relHWheel, Value: -1},
+		{Type: evRel, Code: relHWheelHiRes, Value: -120},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestMouseWheelHiRes(t *testing.T) {
+	relDev, err := CreateMouse("/dev/uinput", []byte("Test HiRes Mouse"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual mouse. Last error was: %s\n", err)
+	}
+	defer relDev.Close()
+
+	for i := 0; i < 4; i++ {
+		err = relDev.WheelHiRes(30)
+		if err != nil {
+			t.Fatalf("Failed to scroll. Last error was: %s\n", err)
+		}
+	}
+}
+
+func TestMouseSendRawEvent(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}}
+
+	err := vRel.SendRawEvent(evRel, relDial, 3)
+	if err != nil {
+		t.Fatalf("Failed to send raw event: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	if len(events) != 1 || events[0] != (inputEvent{Type: evRel, Code: relDial, Value: 3}) {
+		t.Fatalf("Expected a single dial event, but got %+v", events)
+	}
+}
+
+func TestDoubleClickEmitsTwoTimedClicks(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}}
+
+	start := time.Now()
+	err := vRel.DoubleClick()
+	if err != nil {
+		t.Fatalf("Failed to double click: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 2*clickHold+doubleClickInterval {
+		t.Fatalf("Expected the double click to take at least %v, but it took %v", 2*clickHold+doubleClickInterval, elapsed)
+	}
+
+	click := []inputEvent{
+		{Type: evKey, Code: evMouseBtnLeft, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: evMouseBtnLeft, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	expected := append(append([]inputEvent{}, click...), click...)
+	events := readTestEvents(t, file)
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestClickNClicksGivenButton(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}}
+
+	err := vRel.ClickN(ButtonRight, 3, time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+
+	presses := 0
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evKey {
+			if ev.Code != evMouseBtnRight {
+				t.Fatalf("Expected only the right button to be clicked, but got %+v", ev)
+			}
+			if ev.Value == btnStatePressed {
+				presses++
+			}
+		}
+	}
+	if presses != 3 {
+		t.Fatalf("Expected 3 clicks, but got %d", presses)
+	}
+}
+
+func TestClickNFailsOnInvalidArguments(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vRel := &vMouse{deviceFile: &device{File: file}}
+
+	if err := vRel.ClickN(KeyA, 1, 0); err == nil {
+		t.Fatalf("Expected click of a key to fail")
+	}
+	if err := vRel.ClickN(ButtonLeft, 0, 0); err == nil {
+		t.Fatalf("Expected zero clicks to fail")
+	}
+	if err := vRel.ClickN(ButtonLeft, 2, -time.Second); err == nil {
+		t.Fatalf("Expected negative interval to fail")
+	}
+	if len(readTestEvents(t, file)) != 0 {
+		t.Fatalf("Expected invalid clicks not to emit any events")
+	}
+}
+
+func TestCreateMouseFromFileFailsOnNilFile(t *testing.T) {
+	_, err := CreateMouseFromFile(nil, []byte("Test Mouse"))
+	if err == nil {
+		t.Fatalf("Expected mouse creation to fail due to a missing device file, but got no error.")
+	}
+}
@@ -0,0 +1,93 @@
+package uinput
+
+// Event is a single input event, as emitted by a device.
+type Event struct {
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// writeEvents writes the given buffer, holding one or more encoded events, to the device file, waiting for the rate
+// limit of the device (if any). Events are stamped before, if the device uses monotonic timestamps. Once written, the
+// events are passed on to the observer of the device.
+func writeEvents(deviceFile *device, buf []byte) (int, error) {
+	err := prepareEvents(deviceFile, buf)
+	if err != nil {
+		return 0, err
+	}
+	return writePreparedEvents(deviceFile, buf)
+}
+
+// prepareEvents stamps the events in the given buffer and waits for the rate limit of the device, just like
+// writeEvents, without writing them yet. This allows to time the write alone (see MeasureLatency).
+func prepareEvents(deviceFile *device, buf []byte) error {
+	err := stampEvents(deviceFile, buf)
+	if err != nil {
+		return err
+	}
+	throttleEvents(deviceFile, buf)
+	return nil
+}
+
+// writePreparedEvents writes events prepared by prepareEvents to the device file and passes them on to the observer of
+// the device.
+func writePreparedEvents(deviceFile *device, buf []byte) (int, error) {
+	n, err := writeDeviceFile(deviceFile, buf)
+	if err != nil {
+		return n, err
+	}
+	trackSync(deviceFile, buf[:n])
+	return n, observeEvents(deviceFile, buf[:n])
+}
+
+// writeEventsVectored writes the given buffers just like writeEvents, but using a single vectored write, which saves
+// copying the buffers into one.
+func writeEventsVectored(deviceFile *device, bufs [][]byte) (int, error) {
+	for _, buf := range bufs {
+		err := stampEvents(deviceFile, buf)
+		if err != nil {
+			return 0, err
+		}
+		throttleEvents(deviceFile, buf)
+	}
+	n, writeErr := writeDeviceFileVectored(deviceFile, bufs)
+
+	// only the events that have actually been written are tracked and observed
+	remaining := n
+	for _, buf := range bufs {
+		if remaining <= 0 {
+			break
+		}
+		if len(buf) > remaining {
+			buf = buf[:remaining]
+		}
+		remaining -= len(buf)
+		if len(buf) == 0 {
+			continue
+		}
+		trackSync(deviceFile, buf)
+		err := observeEvents(deviceFile, buf)
+		if err != nil && writeErr == nil {
+			writeErr = err
+		}
+	}
+	return n, writeErr
+}
+
+// observeEvents passes the events in the given buffer on to the observer of the device, if there is one.
+func observeEvents(deviceFile *device, buf []byte) error {
+	observer := deviceFile.observer
+	if observer == nil {
+		return nil
+	}
+
+	size := EventSize
+	for i := 0; i+size <= len(buf); i += size {
+		iev, err := bufferToInputEvent(buf[i : i+size])
+		if err != nil {
+			return err
+		}
+		observer(Event{Type: iev.Type, Code: iev.Code, Value: iev.Value})
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package uinput
+
+import (
+	"testing"
+)
+
+func TestObserverReceivesBatchedEvents(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	var observed []Event
+	fd := newDevice(file, newDeviceOptions([]DeviceOption{WithEventObserver(func(ev Event) {
+		observed = append(observed, ev)
+	})}))
+
+	err := newEventBatch(fd).KeyDown(KeyA).Sync().KeyUp(KeyA).Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+
+	expected := []Event{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(observed) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(observed), observed)
+	}
+	for i := range expected {
+		if observed[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], observed[i])
+		}
+	}
+}
+
+func TestObserverMatchesWrittenEvents(t *testing.T) {
+	fake := NewFake()
+	var observed []Event
+	mouse, err := fake.CreateMouse(WithEventObserver(func(ev Event) {
+		observed = append(observed, ev)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create fake mouse: %v", err)
+	}
+	defer mouse.Close()
+
+	if err := mouse.LeftClick(); err != nil {
+		t.Fatalf("Failed to click: %v", err)
+	}
+	written := fake.Events()
+	if len(observed) == 0 || len(observed) != len(written) {
+		t.Fatalf("Expected the observed events %+v to match the written events %+v", observed, written)
+	}
+	for i := range written {
+		if observed[i] != written[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, written[i], observed[i])
+		}
+	}
+}
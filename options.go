@@ -0,0 +1,412 @@
+package uinput
+
+import (
+	"os"
+	"time"
+)
+
+// BusType specifies the bus a virtual device reports to be attached to (see BUS_* in input.h).
+// Some software treats devices differently depending on the bus they are connected to, e.g. built-in
+// laptop keyboards (i8042) vs. external USB keyboards.
+type BusType uint16
+
+const (
+	// BusUsb will make the device report as a USB device. This is the default.
+	BusUsb BusType = busUsb
+	// BusI8042 will make the device report as an internal (PS/2 controller) device, like a built-in laptop keyboard.
+	BusI8042 BusType = busI8042
+	// BusBluetooth will make the device report as a bluetooth device.
+	BusBluetooth BusType = busBluetooth
+	// BusVirtual will make the device report as a virtual device.
+	BusVirtual BusType = busVirtual
+)
+
+// InputProperty is a property of a device (see INPUT_PROP_* in input.h), which tells consumers how to interpret the
+// events of the device. libinput, for example, tells touch pads, clickpads and touch screens apart by their properties.
+type InputProperty uint16
+
+const (
+	// PropPointer marks a device that moves a cursor indirectly, like a touch pad.
+	PropPointer InputProperty = 0x00
+	// PropDirect marks a device that is used on the screen directly, like a touch screen or a pen display.
+	PropDirect InputProperty = 0x01
+	// PropButtonPad marks a touch pad that is clicked by pressing the whole pad (a clickpad).
+	PropButtonPad InputProperty = 0x02
+	// PropSemiMT marks a touch pad that reports the bounding box of the contacts instead of their positions.
+	PropSemiMT InputProperty = 0x03
+	// PropTopButtonPad marks a clickpad with software buttons at its top, like the ones of many ThinkPads.
+	PropTopButtonPad InputProperty = 0x04
+	// PropPointingStick marks a pointing stick, like a TrackPoint.
+	PropPointingStick InputProperty = 0x05
+	// PropAccelerometer marks a device that reports acceleration instead of positions.
+	PropAccelerometer InputProperty = 0x06
+)
+
+// Absolute axes of touch pads, touch screens and pens, which may be configured using WithAxis.
+const (
+	AxisX           uint16 = absX
+	AxisY           uint16 = absY
+	AxisPressure    uint16 = absPressure
+	AxisTiltX       uint16 = absTiltX
+	AxisTiltY       uint16 = absTiltY
+	AxisMTPositionX uint16 = absMTPositionX
+	AxisMTPositionY uint16 = absMTPositionY
+)
+
+// A DeviceOption configures optional properties of a virtual device upon creation.
+type DeviceOption func(*deviceOptions)
+
+type deviceOptions struct {
+	busType        BusType
+	maxKeys        int
+	silentKeyDrop  bool
+	checkName      bool
+	composeKey     int
+	preSyncDelay   time.Duration
+	closeOnExec    bool
+	layout         Layout
+	keys           []int
+	ffHandler      FFHandler
+	readyTimeout   time.Duration
+	keyRepeat      bool
+	repeatDelay    time.Duration
+	repeatPeriod   time.Duration
+	releaseOnClose bool
+	concurrent     bool
+	truncateName   bool
+	easing         Easing
+	properties     []InputProperty
+	drag           dragTiming
+	observer       func(Event)
+	axes           map[uint16]AbsRange
+	phys           string
+	unicodeInput   bool
+	maxEventRate   int
+	nonBlocking    bool
+	writeTimeout   time.Duration
+	destroyDelay   time.Duration
+	timestamps     bool
+	deviceFile     *os.File
+	windowFocuser  WindowFocuser
+
+	vendor     uint16
+	product    uint16
+	productSet bool
+	version    uint16
+}
+
+// WithBusType sets the bus type the device will report (BusUsb by default).
+func WithBusType(bus BusType) DeviceOption {
+	return func(o *deviceOptions) {
+		o.busType = bus
+	}
+}
+
+// WithMaxSimultaneousKeys limits the number of non-modifier keys a keyboard may hold down at the same time, which
+// mimics keyboards without n-key rollover. Key presses beyond the limit are refused with an error, unless
+// WithSilentKeyDrop is set as well. A value of zero or less disables the limit (the default).
+func WithMaxSimultaneousKeys(n int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.maxKeys = n
+	}
+}
+
+// WithSilentKeyDrop will cause key presses beyond the limit set by WithMaxSimultaneousKeys to be dropped without
+// returning an error if enabled, just like a real keyboard suffering from ghosting would do.
+func WithSilentKeyDrop(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.silentKeyDrop = enabled
+	}
+}
+
+// WithNameCollisionCheck enables a check that makes device creation fail with ErrNameCollision if an input device with
+// the same name already exists (as listed in /proc/bus/input/devices). Devices that share the name of a physical device
+// are hard to tell apart when debugging.
+func WithNameCollisionCheck(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.checkName = enabled
+	}
+}
+
+// WithComposeKey sets the key that is used as compose key by TypeCompose (KeyCompose by default). This should match the
+// compose key that is configured in the OS.
+func WithComposeKey(key int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.composeKey = key
+	}
+}
+
+// WithPreSyncDelay inserts a delay between writing key events and the sync event terminating them. This is only needed
+// for consumers that are sensitive to the timing of events and syncs (as observed with certain VNC servers).
+// The delay is zero by default.
+func WithPreSyncDelay(d time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.preSyncDelay = d
+	}
+}
+
+// WithMaxEventRate limits the number of events written to the device per second, pacing them evenly. Some compositors
+// and games drop or misbehave on synthetic input that arrives unrealistically fast, which otherwise requires sleeps
+// between calls. Sync events are not counted, and the events of a single frame (e.g. a batch) are written at once.
+// A value of zero or less disables the limit (the default).
+func WithMaxEventRate(eventsPerSecond int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.maxEventRate = eventsPerSecond
+	}
+}
+
+// WithNonBlockingWrites controls whether writing events fails with ErrWouldBlock if the kernel event queue of the device
+// is full, instead of waiting until the events can be written (the default). Daemons may use this to detect a stalled
+// consumer rather than hanging silently.
+func WithNonBlockingWrites(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.nonBlocking = enabled
+	}
+}
+
+// WithWriteTimeout limits the time writing events may wait for the kernel event queue of the device to drain. Writes
+// exceeding the timeout fail with ErrWouldBlock. A timeout of zero or less waits indefinitely (the default).
+func WithWriteTimeout(timeout time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.writeTimeout = timeout
+	}
+}
+
+// WithDestroyDelay sets the time Close waits after flushing the last events of the device (by sending a sync event)
+// before destroying it. Some environments drop the last events of a device once it is destroyed, unless they had a
+// moment to process them. The delay is zero by default.
+func WithDestroyDelay(d time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.destroyDelay = d
+	}
+}
+
+// WithMonotonicTimestamps controls whether events are stamped with the time of CLOCK_MONOTONIC they are sent at, instead
+// of leaving the timestamp to the kernel, which assigns the time it receives the events at. The two differ if writing
+// the events is delayed, e.g. by WithMaxEventRate or a full event queue. Events that carry an explicit timestamp (see
+// EventBatch.EventAt) are not stamped. This is disabled by default.
+func WithMonotonicTimestamps(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.timestamps = enabled
+	}
+}
+
+// WithCloseOnExec controls whether the device file descriptor is closed upon exec, so that it is not inherited by child
+// processes. This is enabled by default and should only be disabled if a child process is supposed to take over the
+// device.
+func WithCloseOnExec(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.closeOnExec = enabled
+	}
+}
+
+// WithLayout sets the keyboard layout that is used by Type and TypeRune in order to map characters to keys (LayoutUS by
+// default). This should match the keyboard layout that is configured in the OS.
+func WithLayout(layout Layout) DeviceOption {
+	return func(o *deviceOptions) {
+		o.layout = layout
+	}
+}
+
+// WithWindowFocuser sets the WindowFocuser a keyboard uses in order to focus the target window of TypeToWindow. By
+// default (or if the focuser is nil), the focus is not changed at all.
+func WithWindowFocuser(focuser WindowFocuser) DeviceOption {
+	return func(o *deviceOptions) {
+		o.windowFocuser = focuser
+	}
+}
+
+// WithUnicodeInput makes Type and TypeRune enter characters that are not supported by the keyboard layout (like emoji)
+// by their code point, using the Unicode entry sequence of IBus and GTK: Ctrl+Shift+U, followed by the hexadecimal code
+// point and a space. This is disabled by default, since applications that do not support the sequence receive the
+// plain key strokes instead.
+func WithUnicodeInput(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.unicodeInput = enabled
+	}
+}
+
+// WithKeys restricts the keys a keyboard registers to the given ones. By default, a keyboard registers all keys,
+// which makes it advertise keys it never uses. Some consumers rely on a minimal set of keys in order to classify a
+// device correctly (e.g. a remote control that only sends media keys). Note that the kernel drops events of keys that
+// have not been registered.
+func WithKeys(keys []int) DeviceOption {
+	return func(o *deviceOptions) {
+		o.keys = append([]int{}, keys...)
+	}
+}
+
+// WithKeyRepeat enables key repeat for a keyboard, using the given delay before a held key starts repeating and the
+// given period between repeats (e.g. 250ms and 33ms). Just like for a real keyboard, the repeat events are generated by
+// the kernel, which some applications rely on. The values can be changed later on using Keyboard.SetRepeat.
+func WithKeyRepeat(delay time.Duration, period time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.keyRepeat = true
+		o.repeatDelay = delay
+		o.repeatPeriod = period
+	}
+}
+
+// WithReleaseOnClose controls whether a keyboard releases all keys that are still held down when it is closed. This
+// prevents modifiers from getting stuck on the host if a program closes the keyboard in the middle of a key
+// combination. It is disabled by default.
+func WithReleaseOnClose(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.releaseOnClose = enabled
+	}
+}
+
+// WithProperties sets the given properties on the device, in addition to the properties the device sets on its own
+// (like PropDirect for touch screens). Without the right properties, consumers may misdetect a device, e.g. treat a
+// touch pad as a touch screen.
+func WithProperties(props ...InputProperty) DeviceOption {
+	return func(o *deviceOptions) {
+		o.properties = append(o.properties, props...)
+	}
+}
+
+// WithAxis configures an absolute axis of the device (like AxisX), setting its fuzz (noise filtering), flat (dead zone)
+// and resolution (units per millimeter, or per radian for rotational axes). libinput, for example, requires the
+// resolution of touch pads in order to compute pointer acceleration and gestures based on physical distances. The range
+// of the axis is replaced as well, unless both Min and Max are 0. The axis needs to be one the device reports.
+func WithAxis(axis uint16, r AbsRange) DeviceOption {
+	return func(o *deviceOptions) {
+		if o.axes == nil {
+			o.axes = make(map[uint16]AbsRange)
+		}
+		o.axes[axis] = r
+	}
+}
+
+// WithEasing sets the easing that is applied to smooth movements, like Mouse.MoveSmooth and TouchPad.GlideTo
+// (EaseInOut by default).
+func WithEasing(easing Easing) DeviceOption {
+	return func(o *deviceOptions) {
+		o.easing = easing
+	}
+}
+
+// WithDragTiming sets the timing of drag and drop operations, like Mouse.Drag and TouchPad.DragTo. The hold delay is
+// waited for after pressing the button as well as before releasing it, since many applications only start a drag if the
+// button is held for a moment. The duration is the time the movement in between takes. By default, the hold delay is
+// 50ms and the duration is 250ms.
+func WithDragTiming(hold time.Duration, duration time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.drag = dragTiming{hold: hold, duration: duration}
+	}
+}
+
+// WithNameTruncation controls whether device names exceeding the maximum length of 80 bytes are shortened to fit,
+// instead of making device creation fail. Names are truncated without cutting UTF-8 encoded characters in half. It is
+// disabled by default.
+func WithNameTruncation(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.truncateName = enabled
+	}
+}
+
+// WithConcurrencySafe controls whether devices may be used from several goroutines at once. This is enabled by default,
+// in which case each call sends its events without being interleaved with the events of other calls. This includes
+// composite operations like Type, KeyCombo or the gestures of touch screens and click pads, which lock the device until
+// they are done. Smooth movements (like MoveSmooth or GlideTo) lock the device for each of their steps only.
+// Note that sequences of calls (like Macro playback) may still interleave with calls made by other goroutines.
+// Disabling this saves the (small) locking overhead for programs that access devices from a single goroutine only.
+func WithConcurrencySafe(enabled bool) DeviceOption {
+	return func(o *deviceOptions) {
+		o.concurrent = enabled
+	}
+}
+
+// WithForceFeedback makes a gamepad support rumble effects. The given handler is invoked for each force feedback request
+// (upload, erase, play and stop of an effect, as well as changes of the gain and autocenter settings) made by an
+// application, e.g. in order to pass the rumble on to a real controller. Some games refuse to use a gamepad without force feedback support.
+func WithForceFeedback(handler FFHandler) DeviceOption {
+	return func(o *deviceOptions) {
+		o.ffHandler = handler
+	}
+}
+
+// WithWaitUntilReady makes device creation wait until the event node of the device has appeared and can be opened, for
+// at most the given timeout. Events sent right after creating a device are often lost, since consumers (e.g. X11 or
+// Wayland compositors) pick up devices only after udev has set them up. Device creation fails if the device does not
+// become ready in time. Without this option, device creation waits for a fixed delay of 200ms instead.
+func WithWaitUntilReady(timeout time.Duration) DeviceOption {
+	return func(o *deviceOptions) {
+		o.readyTimeout = timeout
+	}
+}
+
+// WithVendor sets the vendor id the device will report (0x4711 by default). Together with WithProduct, this allows a
+// virtual device to masquerade as specific hardware, for applications that match devices by their ids.
+func WithVendor(vendor uint16) DeviceOption {
+	return func(o *deviceOptions) {
+		o.vendor = vendor
+	}
+}
+
+// WithProduct sets the product id the device will report. By default, each type of device reports its own product id.
+func WithProduct(product uint16) DeviceOption {
+	return func(o *deviceOptions) {
+		o.product = product
+		o.productSet = true
+	}
+}
+
+// WithVersion sets the version the device will report (1 by default).
+func WithVersion(version uint16) DeviceOption {
+	return func(o *deviceOptions) {
+		o.version = version
+	}
+}
+
+// WithPhys sets the physical path the device will report (see UI_SET_PHYS), which is empty by default. Since the phys
+// of a device is exposed by udev (ATTRS{phys}), udev rules and libinput quirks may use it to match a device
+// deterministically, even if other devices share its name and ids. Note that the kernel does not allow to set the
+// unique identifier (uniq) of a virtual device.
+func WithPhys(phys string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.phys = phys
+	}
+}
+
+// WithEventObserver registers a callback that is invoked for each event written to the device, after it has been
+// written successfully. This gives insight into what is actually sent to the kernel, for logging or for comparing the
+// events against golden files in tests. The callback is invoked synchronously, so it should return quickly.
+func WithEventObserver(observer func(Event)) DeviceOption {
+	return func(o *deviceOptions) {
+		o.observer = observer
+	}
+}
+
+func newDeviceOptions(opts []DeviceOption) deviceOptions {
+	options := deviceOptions{
+		busType:     BusUsb,
+		composeKey:  KeyCompose,
+		closeOnExec: true,
+		concurrent:  true,
+		easing:      EaseInOut,
+		drag:        dragTiming{hold: 50 * time.Millisecond, duration: 250 * time.Millisecond},
+		layout:      LayoutUS,
+		vendor:      0x4711,
+		version:     1,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// inputID returns the id the device will report, using the given product id unless another one was set using
+// WithProduct.
+func (o deviceOptions) inputID(defaultProduct uint16) inputID {
+	product := defaultProduct
+	if o.productSet {
+		product = o.product
+	}
+	return inputID{
+		Bustype: uint16(o.busType),
+		Vendor:  o.vendor,
+		Product: product,
+		Version: o.version,
+	}
+}
@@ -0,0 +1,67 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeviceOptionsDefaultToUsb(t *testing.T) {
+	options := newDeviceOptions(nil)
+	if options.busType != BusUsb {
+		t.Fatalf("Expected bus type %#x, but got %#x", BusUsb, options.busType)
+	}
+}
+
+func TestWithBusTypeOverridesDefault(t *testing.T) {
+	options := newDeviceOptions([]DeviceOption{WithBusType(BusI8042)})
+	if options.busType != BusI8042 {
+		t.Fatalf("Expected bus type %#x, but got %#x", BusI8042, options.busType)
+	}
+}
+
+func TestInputIDDefaultsToDeviceProduct(t *testing.T) {
+	id := newDeviceOptions(nil).inputID(0x0815)
+	expected := inputID{Bustype: busUsb, Vendor: 0x4711, Product: 0x0815, Version: 1}
+	if id != expected {
+		t.Fatalf("Expected id %+v, but got %+v", expected, id)
+	}
+}
+
+func TestInputIDOptionsOverrideDefaults(t *testing.T) {
+	options := newDeviceOptions([]DeviceOption{WithVendor(0x1234), WithProduct(0), WithVersion(3), WithBusType(BusBluetooth)})
+	id := options.inputID(0x0815)
+	expected := inputID{Bustype: busBluetooth, Vendor: 0x1234, Product: 0, Version: 3}
+	if id != expected {
+		t.Fatalf("Expected id %+v, but got %+v", expected, id)
+	}
+}
+
+func TestWithDragTimingOverridesDefaults(t *testing.T) {
+	options := newDeviceOptions([]DeviceOption{WithDragTiming(time.Second, 2*time.Second)})
+	if options.drag.hold != time.Second || options.drag.duration != 2*time.Second {
+		t.Fatalf("Expected drag timing to be overridden, but got %+v", options.drag)
+	}
+}
+
+func TestWithPropertiesAccumulates(t *testing.T) {
+	options := newDeviceOptions([]DeviceOption{WithProperties(PropPointer), WithProperties(PropButtonPad, PropTopButtonPad)})
+	expected := []InputProperty{PropPointer, PropButtonPad, PropTopButtonPad}
+	if len(options.properties) != len(expected) {
+		t.Fatalf("Expected properties %v, but got %v", expected, options.properties)
+	}
+	for i := range expected {
+		if options.properties[i] != expected[i] {
+			t.Fatalf("Expected properties %v, but got %v", expected, options.properties)
+		}
+	}
+}
+
+func TestWithPhysSetsPhysicalPath(t *testing.T) {
+	options := newDeviceOptions([]DeviceOption{WithPhys("uinput/keyboard0")})
+	if options.phys != "uinput/keyboard0" {
+		t.Fatalf("Expected phys to be set, but got %q", options.phys)
+	}
+	if newDeviceOptions(nil).phys != "" {
+		t.Fatalf("Expected phys to be empty by default")
+	}
+}
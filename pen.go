@@ -0,0 +1,407 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// A Pen is a stylus on a graphics tablet (like a pen display), which reports its absolute position along with the
+// pressure of its tip and its tilt. While the pen is close to the tablet (in proximity), it moves the cursor without
+// touching the tablet (hovering). The tip touches the tablet as soon as a pressure above zero is reported.
+type Pen interface {
+	// MoveTo will hover the pen at the given position, lifting the tip if it touches the tablet.
+	MoveTo(x int32, y int32) error
+
+	// MoveWithPressure will move the pen to the given position, pressing the tip onto the tablet with the given
+	// pressure. A pressure of zero lifts the tip.
+	MoveWithPressure(x int32, y int32, pressure int32) error
+
+	// SetTilt will report the tilt of the pen along the x and y axes, in degrees (from -90 to 90).
+	SetTilt(x int32, y int32) error
+
+	// StylusPress will simulate a press of the button on the barrel of the pen. Note that the button will not be
+	// released until StylusRelease is invoked.
+	StylusPress() error
+
+	// StylusRelease will simulate the release of the button on the barrel of the pen.
+	StylusRelease() error
+
+	// Lift will move the pen out of proximity of the tablet.
+	Lift() error
+
+	// EraserIn will flip the pen, so that the eraser end is used instead of the tip (BTN_TOOL_RUBBER).
+	EraserIn() error
+
+	// EraserOut will flip the pen back, so that the tip is used again (BTN_TOOL_PEN).
+	EraserOut() error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
+	io.Closer
+}
+
+const (
+	// maxPenTilt is the maximum tilt of a pen in degrees, in either direction.
+	maxPenTilt = 90
+	// penTiltResolution is the resolution of the tilt axes in units per radian, which amounts to one unit per degree.
+	penTiltResolution = 57
+)
+
+type vPen struct {
+	name       []byte
+	deviceFile *device
+	onClose    closeHooks
+	mu         deviceMutex
+
+	minX        int32
+	maxX        int32
+	minY        int32
+	maxY        int32
+	maxPressure int32
+
+	inProximity bool
+	touching    bool
+	eraser      bool
+	x           int32
+	y           int32
+}
+
+// CreatePen will create a new pen device, emulating a pen display. Just like for the touch pad, the x and y-axis
+// boundaries (min and max) need to be defined upon creation, along with the maximum pressure the tip may report (e.g.
+// 4095 for 4096 pressure levels).
+func CreatePen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxPressure int32, opts ...DeviceOption) (Pen, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return createVPen(path, name, minX, maxX, minY, maxY, maxPressure, opts)
+}
+
+// CreatePenFromFile will create a new pen just like CreatePen, but uses the given uinput device file, which has been
+// opened by the caller already. The pen takes ownership of the file (see CreateKeyboardFromFile).
+func CreatePenFromFile(file *os.File, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxPressure int32, opts ...DeviceOption) (Pen, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVPen(file.Name(), name, minX, maxX, minY, maxY, maxPressure, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVPen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxPressure int32, opts []DeviceOption) (Pen, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+	if minX >= maxX || minY >= maxY {
+		return nil, fmt.Errorf("invalid tablet area. Minimum values must be less than maximum values")
+	}
+	if maxPressure <= 0 {
+		return nil, fmt.Errorf("%d is not a valid maximum pressure. Expected a positive value", maxPressure)
+	}
+
+	fd, err := createPen(path, name, minX, maxX, minY, maxY, maxPressure, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vPen{name: name, deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, maxPressure: maxPressure, mu: newDeviceMutex(options)}, nil
+}
+
+// NewPen is the same as CreatePen, but takes the name as a string, which is truncated if it exceeds 80 bytes (see
+// WithNameTruncation).
+func NewPen(path string, name string, minX int32, maxX int32, minY int32, maxY int32, maxPressure int32, opts ...DeviceOption) (Pen, error) {
+	return CreatePen(path, []byte(name), minX, maxX, minY, maxY, maxPressure, withStringName(opts)...)
+}
+
+// MoveTo will hover the pen at the given position. The pen is brought into proximity of the tablet first, if needed.
+func (vp *vPen) MoveTo(x int32, y int32) error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return vp.moveWithPressure(x, y, 0)
+}
+
+// MoveWithPressure will move the pen to the given position, with the tip pressed onto the tablet using the given
+// pressure (between 0 and the maximum pressure defined upon creation). The first pressure above zero also reports a
+// BTN_TOUCH press, while a pressure of zero reports its release, just like a real pen would.
+func (vp *vPen) MoveWithPressure(x int32, y int32, pressure int32) error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return vp.moveWithPressure(x, y, pressure)
+}
+
+// moveWithPressure moves the pen and keeps track of its state. The device needs to be locked by the caller.
+func (vp *vPen) moveWithPressure(x int32, y int32, pressure int32) error {
+	if x < vp.minX || x > vp.maxX || y < vp.minY || y > vp.maxY {
+		return fmt.Errorf("failed to move pen. Position (%d, %d) is outside of the tablet area", x, y)
+	}
+	if pressure < 0 || pressure > vp.maxPressure {
+		return fmt.Errorf("failed to move pen. Pressure %d is out of range. Expected a value between 0 and %d", pressure, vp.maxPressure)
+	}
+
+	var events []inputEvent
+	if !vp.inProximity {
+		events = append(events, inputEvent{Type: evKey, Code: vp.tool(), Value: btnStatePressed})
+	}
+	events = append(events,
+		inputEvent{Type: evAbs, Code: absX, Value: x},
+		inputEvent{Type: evAbs, Code: absY, Value: y},
+		inputEvent{Type: evAbs, Code: absPressure, Value: pressure})
+	if pressure > 0 && !vp.touching {
+		events = append(events, inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStatePressed})
+	}
+	if pressure == 0 && vp.touching {
+		events = append(events, inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStateReleased})
+	}
+
+	err := vp.sendEvents(events)
+	if err != nil {
+		return err
+	}
+	vp.inProximity = true
+	vp.touching = pressure > 0
+	vp.x, vp.y = x, y
+	return nil
+}
+
+// SetTilt will report the tilt of the pen along the x and y axes, in degrees (from -90 to 90). A tilt of zero means that
+// the pen is perpendicular to the tablet.
+func (vp *vPen) SetTilt(x int32, y int32) error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	if x < -maxPenTilt || x > maxPenTilt || y < -maxPenTilt || y > maxPenTilt {
+		return fmt.Errorf("failed to tilt pen. Tilt (%d, %d) is out of range. Expected values between %d and %d", x, y, -maxPenTilt, maxPenTilt)
+	}
+	return vp.sendEvents([]inputEvent{
+		{Type: evAbs, Code: absTiltX, Value: x},
+		{Type: evAbs, Code: absTiltY, Value: y},
+	})
+}
+
+// StylusPress will simulate a press of the button on the barrel of the pen. Note that the button will not be released
+// until StylusRelease is invoked.
+func (vp *vPen) StylusPress() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return sendBtnEvent(vp.deviceFile, []int{evBtnStylus}, btnStatePressed)
+}
+
+// StylusRelease will simulate the release of the button on the barrel of the pen.
+func (vp *vPen) StylusRelease() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return sendBtnEvent(vp.deviceFile, []int{evBtnStylus}, btnStateReleased)
+}
+
+// Lift will move the pen out of proximity of the tablet, lifting its tip first if it touches the tablet. Applications
+// commonly finish a stroke once the pen leaves proximity. Lifting a pen that is not in proximity has no effect.
+func (vp *vPen) Lift() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return vp.lift()
+}
+
+// lift moves the pen out of proximity. The device needs to be locked by the caller.
+func (vp *vPen) lift() error {
+	if !vp.inProximity {
+		return nil
+	}
+
+	var events []inputEvent
+	if vp.touching {
+		events = append(events,
+			inputEvent{Type: evAbs, Code: absPressure, Value: 0},
+			inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStateReleased})
+	}
+	events = append(events, inputEvent{Type: evKey, Code: vp.tool(), Value: btnStateReleased})
+
+	err := vp.sendEvents(events)
+	if err != nil {
+		return err
+	}
+	vp.inProximity = false
+	vp.touching = false
+	return nil
+}
+
+// EraserIn will flip the pen, so that the eraser end is used for all following movements, which are reported using
+// BTN_TOOL_RUBBER instead of BTN_TOOL_PEN. Applications commonly switch to erase mode once the eraser comes into
+// proximity. If the pen is in proximity, its tip leaves proximity within one frame and the eraser enters proximity at
+// the same position within the next frame, so that only one of both tools is in proximity at a time.
+func (vp *vPen) EraserIn() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	err := vp.flip(true)
+	if err != nil {
+		return fmt.Errorf("failed to perform EraserIn: %w", err)
+	}
+	return nil
+}
+
+// EraserOut will flip the pen back, so that the tip is used for all following movements (see EraserIn).
+func (vp *vPen) EraserOut() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	err := vp.flip(false)
+	if err != nil {
+		return fmt.Errorf("failed to perform EraserOut: %w", err)
+	}
+	return nil
+}
+
+// flip switches between the tip and the eraser end of the pen. A pen in proximity is lifted and brought back into
+// proximity using the other end. The device needs to be locked by the caller.
+func (vp *vPen) flip(eraser bool) error {
+	if vp.eraser == eraser {
+		return nil
+	}
+	if !vp.inProximity {
+		vp.eraser = eraser
+		return nil
+	}
+
+	err := vp.lift()
+	if err != nil {
+		return err
+	}
+	vp.eraser = eraser
+	return vp.moveWithPressure(vp.x, vp.y, 0)
+}
+
+// tool returns the tool code of the end of the pen that is currently used.
+func (vp *vPen) tool() uint16 {
+	if vp.eraser {
+		return evBtnToolRubber
+	}
+	return evBtnToolPen
+}
+
+// FetchSyspath will return the syspath to the device file.
+func (vp *vPen) FetchSyspath() (string, error) {
+	return lookupSyspath(vp.deviceFile.File, vp.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vp *vPen) EventNode() (string, error) {
+	return lookupEventNode(vp.deviceFile.File, vp.name)
+}
+
+// Close closes the device and releases the device.
+func (vp *vPen) Close() error {
+	err := closeDevice(vp.deviceFile)
+	vp.onClose.run()
+	return err
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vp *vPen) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return sendRawEvent(vp.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vp *vPen) Sync() error {
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return syncEvents(vp.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vp *vPen) OnClose(callback func()) {
+	vp.onClose.add(callback)
+}
+
+func (vp *vPen) sendEvents(events []inputEvent) error {
+	for _, ev := range events {
+		err := writeEvent(vp.deviceFile, ev)
+		if err != nil {
+			return fmt.Errorf("failed to write pen event to device file: %w", err)
+		}
+	}
+	return syncEvents(vp.deviceFile)
+}
+
+func createPen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, maxPressure int32, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not create pen input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	for _, event := range []int{evBtnToolPen, evBtnToolRubber, evBtnTouch, evBtnStylus} {
+		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
+		}
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+	for _, event := range []int{absX, absY, absPressure, absTiltX, absTiltY} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	// mark the device as a pen display (the pen is used on the screen directly), which is what most drawing
+	// applications expect
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropDirect))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register direct input property: %w", err)
+	}
+
+	var absMin [absSize]int32
+	absMin[absX] = minX
+	absMin[absY] = minY
+	absMin[absTiltX] = -maxPenTilt
+	absMin[absTiltY] = -maxPenTilt
+
+	var absMax [absSize]int32
+	absMax[absX] = maxX
+	absMax[absY] = maxY
+	absMax[absPressure] = maxPressure
+	absMax[absTiltX] = maxPenTilt
+	absMax[absTiltY] = maxPenTilt
+
+	var absRes [absSize]int32
+	absRes[absTiltX] = penTiltResolution
+	absRes[absTiltY] = penTiltResolution
+
+	return createUsbDeviceWithResolution(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     options.inputID(0x081b),
+			Absmin: absMin,
+			Absmax: absMax}, absRes, options)
+}
@@ -0,0 +1,202 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestPenStroke(t *testing.T) {
+	pen, err := CreatePen("/dev/uinput", []byte("Test Pen"), 0, 1024, 0, 768, 4095)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual pen. Last error was: %s\n", err)
+	}
+	defer pen.Close()
+
+	err = pen.MoveTo(100, 100)
+	if err != nil {
+		t.Fatalf("Failed to hover pen. Last error was: %s\n", err)
+	}
+	err = pen.SetTilt(-30, 15)
+	if err != nil {
+		t.Fatalf("Failed to tilt pen. Last error was: %s\n", err)
+	}
+	for i := int32(1); i <= 10; i++ {
+		err = pen.MoveWithPressure(100+i*10, 100, i*400)
+		if err != nil {
+			t.Fatalf("Failed to draw with pen. Last error was: %s\n", err)
+		}
+	}
+	err = pen.Lift()
+	if err != nil {
+		t.Fatalf("Failed to lift pen. Last error was: %s\n", err)
+	}
+}
+
+func TestPenReportsProximityAndTouch(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vp := &vPen{deviceFile: &device{File: file}, maxX: 1024, maxY: 768, maxPressure: 1023}
+
+	if err := vp.MoveTo(10, 20); err != nil {
+		t.Fatalf("Failed to hover pen: %v", err)
+	}
+	if err := vp.MoveWithPressure(11, 21, 500); err != nil {
+		t.Fatalf("Failed to draw with pen: %v", err)
+	}
+	if err := vp.MoveWithPressure(12, 22, 600); err != nil {
+		t.Fatalf("Failed to draw with pen: %v", err)
+	}
+	if err := vp.Lift(); err != nil {
+		t.Fatalf("Failed to lift pen: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: evBtnToolPen, Value: btnStatePressed},
+		{Type: evAbs, Code: absX, Value: 10},
+		{Type: evAbs, Code: absY, Value: 20},
+		{Type: evAbs, Code: absPressure, Value: 0},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absX, Value: 11},
+		{Type: evAbs, Code: absY, Value: 21},
+		{Type: evAbs, Code: absPressure, Value: 500},
+		{Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absX, Value: 12},
+		{Type: evAbs, Code: absY, Value: 22},
+		{Type: evAbs, Code: absPressure, Value: 600},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absPressure, Value: 0},
+		{Type: evKey, Code: evBtnTouch, Value: btnStateReleased},
+		{Type: evKey, Code: evBtnToolPen, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestPenEraserIsExclusiveWithTip(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vp := &vPen{deviceFile: &device{File: file}, maxX: 1024, maxY: 768, maxPressure: 1023}
+
+	if err := vp.MoveWithPressure(10, 20, 500); err != nil {
+		t.Fatalf("Failed to draw with pen: %v", err)
+	}
+	if err := vp.EraserIn(); err != nil {
+		t.Fatalf("Failed to flip pen: %v", err)
+	}
+	if err := vp.EraserIn(); err != nil {
+		t.Fatalf("Expected flipping a flipped pen to succeed, but got %v", err)
+	}
+	if err := vp.MoveWithPressure(30, 40, 500); err != nil {
+		t.Fatalf("Failed to erase with pen: %v", err)
+	}
+	if err := vp.Lift(); err != nil {
+		t.Fatalf("Failed to lift pen: %v", err)
+	}
+	if err := vp.EraserOut(); err != nil {
+		t.Fatalf("Failed to flip pen back: %v", err)
+	}
+	if err := vp.MoveTo(50, 60); err != nil {
+		t.Fatalf("Failed to hover pen: %v", err)
+	}
+
+	// the tools in proximity at the end of each frame
+	var proximity [][]uint16
+	var tools []uint16
+	for _, ev := range readTestEvents(t, file) {
+		if ev.Type == evSyn {
+			proximity = append(proximity, append([]uint16(nil), tools...))
+			continue
+		}
+		if ev.Type != evKey || (ev.Code != evBtnToolPen && ev.Code != evBtnToolRubber) {
+			continue
+		}
+		if ev.Value == btnStatePressed {
+			tools = append(tools, ev.Code)
+		} else if len(tools) != 1 || tools[0] != ev.Code {
+			t.Fatalf("Expected tool %d to be released while it is in proximity, but got %v", ev.Code, tools)
+		} else {
+			tools = nil
+		}
+	}
+
+	expected := [][]uint16{{evBtnToolPen}, nil, {evBtnToolRubber}, {evBtnToolRubber}, nil, {evBtnToolPen}}
+	if len(proximity) != len(expected) {
+		t.Fatalf("Expected %d frames, but got %d: %v", len(expected), len(proximity), proximity)
+	}
+	for i := range expected {
+		if fmt.Sprint(proximity[i]) != fmt.Sprint(expected[i]) {
+			t.Fatalf("Expected tools %v to be in proximity after frame %d, but got %v", expected[i], i, proximity[i])
+		}
+	}
+}
+
+func TestPenFailsOnValuesOutOfRange(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vp := &vPen{deviceFile: &device{File: file}, maxX: 1024, maxY: 768, maxPressure: 1023}
+
+	if err := vp.MoveTo(2000, 0); err == nil {
+		t.Fatalf("Expected MoveTo to fail for a position outside of the tablet, but got no error.")
+	}
+	if err := vp.MoveWithPressure(0, 0, 1024); err == nil {
+		t.Fatalf("Expected MoveWithPressure to fail for a pressure out of range, but got no error.")
+	}
+	if err := vp.SetTilt(91, 0); err == nil {
+		t.Fatalf("Expected SetTilt to fail for a tilt out of range, but got no error.")
+	}
+	if err := vp.Lift(); err != nil {
+		t.Fatalf("Expected Lift to succeed for a pen out of proximity, but got %v", err)
+	}
+	if len(readTestEvents(t, file)) != 0 {
+		t.Fatalf("Expected no events to be emitted")
+	}
+}
+
+func TestPenCreationFailsOnInvalidPressure(t *testing.T) {
+	_, err := CreatePen("/dev/uinput", []byte("PenDevice"), 0, 1024, 0, 768, 0)
+	if err == nil {
+		t.Fatalf("Expected creation to fail due to an invalid maximum pressure, but got no error.")
+	}
+}
+
+func TestPenCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreatePen("", []byte("PenDevice"), 0, 1024, 0, 768, 4095)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestPenCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-pen-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	_, err = CreatePen(file.Name(), []byte("PenDevice"), 0, 1024, 0, 768, 4095)
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestPenCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreatePen("/dev/uinput", []byte(name), 0, 1024, 0, 768, 4095)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
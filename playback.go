@@ -0,0 +1,122 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// A TimedEvent is a single event, followed by a delay before the next event is sent.
+type TimedEvent struct {
+	Type       uint16
+	Code       uint16
+	Value      int32
+	DelayAfter time.Duration
+}
+
+// spinThreshold is the remaining time until a deadline below which sleepUntil stops sleeping and spins instead, since
+// the scheduler may oversleep by up to a millisecond or more.
+const spinThreshold = time.Millisecond
+
+// PlayTimedEvents will send the given events to the device, waiting for the delay of each event before sending the
+// next one. The delays are measured using the monotonic clock from the start of the playback, so that the time it
+// takes to send the events does not add up, and the last stretch of each delay is spun rather than slept, which makes
+// the events arrive within microseconds of their intended time (at the cost of some CPU usage). This allows to
+// simulate a realistic keystroke cadence.
+// Note that sync events are not sent implicitly, so each frame needs to be terminated by a sync event
+// (EV_SYN/SYN_REPORT) of its own.
+func PlayTimedEvents(dev RawEventSender, events []TimedEvent) error {
+	return PlayTimedEventsCtx(context.Background(), dev, events)
+}
+
+// PlayTimedEventsCtx will send the given events just like PlayTimedEvents, but stops once the given context is done, in
+// which case the error of the context is returned. Keys that have been pressed by the events, but not released yet are
+// released when stopping.
+func PlayTimedEventsCtx(ctx context.Context, dev RawEventSender, events []TimedEvent) error {
+	if dev == nil {
+		return fmt.Errorf("failed to play events. The device must not be nil")
+	}
+
+	held := make(heldKeys)
+	deadline := time.Now()
+	for _, ev := range events {
+		err := sleepUntil(ctx, deadline)
+		if err != nil {
+			return held.releaseAfter(dev, err)
+		}
+		err = dev.SendRawEvent(ev.Type, ev.Code, ev.Value)
+		if err != nil {
+			return fmt.Errorf("failed to play event %+v: %w", ev, err)
+		}
+		held.track(ev.Type, ev.Code, ev.Value)
+		deadline = deadline.Add(ev.DelayAfter)
+	}
+	return nil
+}
+
+// sleepUntil blocks until the given deadline has passed or the context is done, whichever happens first.
+func sleepUntil(ctx context.Context, deadline time.Time) error {
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		if remaining <= spinThreshold {
+			runtime.Gosched()
+			continue
+		}
+
+		timer := time.NewTimer(remaining - spinThreshold)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// heldKeys keeps track of the keys pressed during a playback, so that they can be released if the playback is stopped.
+type heldKeys map[uint16]bool
+
+func (h heldKeys) track(evType uint16, code uint16, value int32) {
+	if evType != evKey {
+		return
+	}
+	if value == btnStateReleased {
+		delete(h, code)
+	} else {
+		h[code] = true
+	}
+}
+
+// releaseAfter releases all held keys within a single frame and returns the given cause of stopping the playback.
+func (h heldKeys) releaseAfter(dev RawEventSender, cause error) error {
+	if len(h) == 0 {
+		return cause
+	}
+	var keys []int
+	for key := range h {
+		keys = append(keys, int(key))
+	}
+	sort.Ints(keys)
+
+	for _, key := range keys {
+		err := dev.SendRawEvent(evKey, uint16(key), btnStateReleased)
+		if err != nil {
+			return fmt.Errorf("failed to release key %d after playback was stopped (%v): %w", key, cause, err)
+		}
+		delete(h, uint16(key))
+	}
+	err := dev.SendRawEvent(evSyn, synReport, 0)
+	if err != nil {
+		return fmt.Errorf("failed to release keys after playback was stopped (%v): %w", cause, err)
+	}
+	return cause
+}
@@ -0,0 +1,78 @@
+package uinput
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTimedEventsArePlayedWithDelays(t *testing.T) {
+	events := []TimedEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport, DelayAfter: 30 * time.Millisecond},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport, DelayAfter: 10 * time.Millisecond},
+		{Type: evKey, Code: KeyB, Value: btnStatePressed},
+	}
+
+	rec := NewMacroRecorder()
+	err := PlayTimedEvents(rec, events)
+	if err != nil {
+		t.Fatalf("Failed to play events: %v", err)
+	}
+
+	m := rec.Macro()
+	if len(m.Events) != len(events) {
+		t.Fatalf("Expected %d events, but got %d", len(events), len(m.Events))
+	}
+	for i, expected := range []time.Duration{0, 0, 30 * time.Millisecond, 30 * time.Millisecond, 40 * time.Millisecond} {
+		offset := m.Events[i].Time - m.Events[0].Time
+		if offset < expected-time.Millisecond || offset > expected+15*time.Millisecond {
+			t.Fatalf("Expected event %d to be sent after %v, but was sent after %v", i, expected, offset)
+		}
+	}
+}
+
+func TestSleepUntilDoesNotReturnEarly(t *testing.T) {
+	deadline := time.Now().Add(5 * time.Millisecond)
+	err := sleepUntil(context.Background(), deadline)
+	if err != nil {
+		t.Fatalf("Failed to sleep: %v", err)
+	}
+	if time.Now().Before(deadline) {
+		t.Fatalf("Expected sleepUntil to block until the deadline")
+	}
+}
+
+func TestCancelledPlaybackReleasesHeldKeys(t *testing.T) {
+	events := []TimedEvent{
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport, DelayAfter: time.Second},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	rec := NewMacroRecorder()
+	err := PlayTimedEventsCtx(ctx, rec, events)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected playback to stop with %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	m := rec.Macro()
+	expected := []MacroEvent{
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(m.Events) != 3+len(expected) {
+		t.Fatalf("Expected %d events, but got %+v", 3+len(expected), m.Events)
+	}
+	for i, ev := range m.Events[3:] {
+		ev.Time = 0
+		if ev != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, ev)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package uinput
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter paces events using a token bucket holding a single token, i.e. each event has to wait for its own slot.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	// next is the earliest time the next event may be written
+	next time.Time
+}
+
+// newRateLimiter returns a rate limiter for the maximum event rate set in the given options, or nil if the rate of
+// events is not limited.
+func newRateLimiter(options deviceOptions) *rateLimiter {
+	if options.maxEventRate <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Second / time.Duration(options.maxEventRate)}
+}
+
+// throttleEvents blocks until the events in the given buffer may be written to the device file. The events of a
+// single buffer are written at once, so that frames are never split, while the following events wait longer instead.
+func throttleEvents(deviceFile *device, buf []byte) {
+	limiter := deviceFile.limiter
+	if limiter == nil {
+		return
+	}
+
+	events := 0
+	size := EventSize
+	for i := 0; i+size <= len(buf); i += size {
+		iev, err := bufferToInputEvent(buf[i : i+size])
+		if err == nil && iev.Type != evSyn {
+			events++
+		}
+	}
+	if events > 0 {
+		limiter.wait(events)
+	}
+}
+
+// wait blocks until the next slot is due and reserves the slots of the given number of events.
+func (l *rateLimiter) wait(events int) {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(events) * l.interval)
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
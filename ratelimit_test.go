@@ -0,0 +1,53 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaxEventRatePacesEvents(t *testing.T) {
+	fake := NewFake()
+	keyboard, err := fake.CreateKeyboard(WithMaxEventRate(100))
+	if err != nil {
+		t.Fatalf("Failed to create keyboard: %v", err)
+	}
+	defer keyboard.Close()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err = keyboard.KeyPress(KeyA)
+		if err != nil {
+			t.Fatalf("Failed to press key: %v", err)
+		}
+	}
+	// six key events, of which the first one is written right away
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("Expected the key events to be paced at 100 events per second, but they took %v", elapsed)
+	}
+
+	events := fake.Events()
+	if len(events) != 12 {
+		t.Fatalf("Expected 12 events, but got %d: %+v", len(events), events)
+	}
+}
+
+func TestRateLimiterReservesSlots(t *testing.T) {
+	limiter := &rateLimiter{interval: 10 * time.Millisecond}
+	start := time.Now()
+	limiter.wait(3)
+	if elapsed := time.Since(start); elapsed >= 10*time.Millisecond {
+		t.Fatalf("Expected the first events to be written right away, but waited %v", elapsed)
+	}
+	limiter.wait(1)
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("Expected the next event to wait for the slots of the previous ones, but waited %v", elapsed)
+	}
+}
+
+func TestUnlimitedDeviceIsNotThrottled(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	if newDevice(file, newDeviceOptions(nil)).limiter != nil {
+		t.Fatalf("Expected no rate limiter without WithMaxEventRate")
+	}
+}
@@ -0,0 +1,250 @@
+package uinput
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// RecordingVersion is the version of the JSON format written by Recording.Save.
+const RecordingVersion = 1
+
+// A Recording is a macro along with a description of the device it was recorded on, which can be saved to and loaded
+// from JSON. This allows to ship recorded input as fixtures along with a test suite. The JSON format looks like this:
+//
+//	{
+//	  "version": 1,
+//	  "device": "touchpad",
+//	  "capabilities": {"ev": [1, 3], "key": [330], "abs": [0, 1], "absRanges": {"0": {"min": 0, "max": 1024}}},
+//	  "events": [
+//	    {"delayUs": 0, "type": 1, "code": 330, "value": 1},
+//	    {"delayUs": 0, "type": 0, "code": 0, "value": 0},
+//	    {"delayUs": 8000, "type": 3, "code": 0, "value": 512}
+//	  ]
+//	}
+//
+// The delay of each event is the time in microseconds since the previous event. The device and its capabilities are
+// optional.
+type Recording struct {
+	// Device is a free-form description of the kind of device the events were recorded on (e.g. "keyboard").
+	Device string
+	// Capabilities optionally holds the capabilities of the device the events were recorded on, which are used by
+	// CreateDevice.
+	Capabilities *Capabilities
+
+	Macro
+}
+
+type recordingJSON struct {
+	Version      int                 `json:"version"`
+	Device       string              `json:"device,omitempty"`
+	Capabilities *capabilitiesJSON   `json:"capabilities,omitempty"`
+	Events       []recordedEventJSON `json:"events"`
+}
+
+type capabilitiesJSON struct {
+	EV        []int                `json:"ev,omitempty"`
+	Key       []int                `json:"key,omitempty"`
+	Rel       []int                `json:"rel,omitempty"`
+	Abs       []int                `json:"abs,omitempty"`
+	Msc       []int                `json:"msc,omitempty"`
+	Sw        []int                `json:"sw,omitempty"`
+	Led       []int                `json:"led,omitempty"`
+	Snd       []int                `json:"snd,omitempty"`
+	Prop      []int                `json:"prop,omitempty"`
+	AbsRanges map[int]absRangeJSON `json:"absRanges,omitempty"`
+}
+
+type absRangeJSON struct {
+	Min        int32 `json:"min"`
+	Max        int32 `json:"max"`
+	Fuzz       int32 `json:"fuzz,omitempty"`
+	Flat       int32 `json:"flat,omitempty"`
+	Resolution int32 `json:"resolution,omitempty"`
+}
+
+type recordedEventJSON struct {
+	DelayUs int64  `json:"delayUs"`
+	Type    uint16 `json:"type"`
+	Code    uint16 `json:"code"`
+	Value   int32  `json:"value"`
+}
+
+// Save will write the recording to w in JSON format. The events must be sorted by time.
+func (r *Recording) Save(w io.Writer) error {
+	rec := recordingJSON{Version: RecordingVersion, Device: r.Device, Events: []recordedEventJSON{}}
+	if r.Capabilities != nil {
+		rec.Capabilities = newCapabilitiesJSON(*r.Capabilities)
+	}
+	// delays are computed from the rounded times, so that rounding errors do not add up over the recording
+	var last time.Duration
+	for i, ev := range r.Events {
+		if ev.Time < last {
+			return fmt.Errorf("failed to save recording. Event %d occurs before the previous event", i)
+		}
+		rec.Events = append(rec.Events, recordedEventJSON{
+			DelayUs: int64(ev.Time/time.Microsecond - last/time.Microsecond),
+			Type:    ev.Type,
+			Code:    ev.Code,
+			Value:   ev.Value,
+		})
+		last = ev.Time
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(rec)
+	if err != nil {
+		return fmt.Errorf("failed to save recording: %w", err)
+	}
+	return nil
+}
+
+// SaveFile will write the recording to the file at the given path in JSON format, replacing the file if it exists.
+func (r *Recording) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create recording file: %w", err)
+	}
+	err = r.Save(f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadRecording will read a recording in JSON format (see Recording) from rd.
+func LoadRecording(rd io.Reader) (*Recording, error) {
+	var rec recordingJSON
+	err := json.NewDecoder(rd).Decode(&rec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recording: %w", err)
+	}
+	if rec.Version != RecordingVersion {
+		return nil, fmt.Errorf("failed to load recording. Version %d is not supported", rec.Version)
+	}
+
+	r := &Recording{Device: rec.Device}
+	if rec.Capabilities != nil {
+		caps := rec.Capabilities.capabilities()
+		r.Capabilities = &caps
+	}
+	var t time.Duration
+	for i, ev := range rec.Events {
+		if ev.DelayUs < 0 {
+			return nil, fmt.Errorf("failed to load recording. Event %d has a negative delay", i)
+		}
+		t += time.Duration(ev.DelayUs) * time.Microsecond
+		r.Events = append(r.Events, MacroEvent{Time: t, Type: ev.Type, Code: ev.Code, Value: ev.Value})
+	}
+	return r, nil
+}
+
+// LoadRecordingFile will read a recording in JSON format from the file at the given path.
+func LoadRecordingFile(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer f.Close()
+	return LoadRecording(f)
+}
+
+// EventCapabilities returns the capabilities required to play back the events of the recording, i.e. the codes of all
+// events except for sync events. The range of each absolute axis is the range of values the axis reports.
+func (r *Recording) EventCapabilities() Capabilities {
+	codes := make(map[uint16]map[int]bool)
+	absRanges := make(map[int]AbsRange)
+	for _, ev := range r.Events {
+		if ev.Type == evSyn {
+			continue
+		}
+		if codes[ev.Type] == nil {
+			codes[ev.Type] = make(map[int]bool)
+		}
+		codes[ev.Type][int(ev.Code)] = true
+		if ev.Type != evAbs {
+			continue
+		}
+		ar, ok := absRanges[int(ev.Code)]
+		if !ok || ev.Value < ar.Min {
+			ar.Min = ev.Value
+		}
+		if !ok || ev.Value > ar.Max {
+			ar.Max = ev.Value
+		}
+		absRanges[int(ev.Code)] = ar
+	}
+
+	b := NewDeviceBuilder()
+	for evType, set := range codes {
+		b.EventTypes(int(evType))
+		keys := sortedCodes(set)
+		switch evType {
+		case evKey:
+			b.Keys(keys...)
+		case evRel:
+			b.Rel(keys...)
+		case evAbs:
+			for _, code := range keys {
+				b.AbsAxis(code, absRanges[code])
+			}
+		case evMsc:
+			b.Msc(keys...)
+		case evSw:
+			b.Switches(keys...)
+		case evLed:
+			b.LEDs(keys...)
+		case evSnd:
+			b.Sounds(keys...)
+		}
+	}
+	return b.Capabilities()
+}
+
+// CreateDevice will create a new custom device the recording can be played back on. The device has the capabilities
+// of the recording if they are known, or the capabilities required by its events otherwise (see EventCapabilities).
+func (r *Recording) CreateDevice(path string, name []byte, opts ...DeviceOption) (CustomDevice, error) {
+	caps := r.EventCapabilities()
+	if r.Capabilities != nil {
+		caps = *r.Capabilities
+	}
+	return CreateFromCapabilities(path, name, caps, opts...)
+}
+
+func sortedCodes(set map[int]bool) []int {
+	codes := make([]int, 0, len(set))
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func newCapabilitiesJSON(caps Capabilities) *capabilitiesJSON {
+	c := &capabilitiesJSON{EV: caps.EV, Key: caps.Key, Rel: caps.Rel, Abs: caps.Abs, Msc: caps.Msc, Sw: caps.Sw,
+		Led: caps.Led, Snd: caps.Snd, Prop: caps.Prop}
+	if len(caps.AbsRanges) > 0 {
+		c.AbsRanges = make(map[int]absRangeJSON)
+		for axis, r := range caps.AbsRanges {
+			c.AbsRanges[axis] = absRangeJSON(r)
+		}
+	}
+	return c
+}
+
+func (c *capabilitiesJSON) capabilities() Capabilities {
+	caps := Capabilities{EV: c.EV, Key: c.Key, Rel: c.Rel, Abs: c.Abs, Msc: c.Msc, Sw: c.Sw, Led: c.Led, Snd: c.Snd,
+		Prop: c.Prop}
+	if len(c.AbsRanges) > 0 {
+		caps.AbsRanges = make(map[int]AbsRange)
+		for axis, r := range c.AbsRanges {
+			caps.AbsRanges[axis] = AbsRange(r)
+		}
+	}
+	return caps
+}
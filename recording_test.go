@@ -0,0 +1,136 @@
+package uinput
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRecording() *Recording {
+	return &Recording{
+		Device: "touchpad",
+		Macro: Macro{Events: []MacroEvent{
+			{Time: 0, Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+			{Time: 0, Type: evAbs, Code: absX, Value: 100},
+			{Time: 0, Type: evSyn, Code: synReport},
+			{Time: 8 * time.Millisecond, Type: evAbs, Code: absX, Value: 300},
+			{Time: 8 * time.Millisecond, Type: evSyn, Code: synReport},
+			{Time: 16 * time.Millisecond, Type: evKey, Code: evBtnTouch, Value: btnStateReleased},
+			{Time: 16 * time.Millisecond, Type: evSyn, Code: synReport},
+		}},
+	}
+}
+
+func TestRecordingIsSavedAndLoaded(t *testing.T) {
+	r := newTestRecording()
+	r.Capabilities = &Capabilities{EV: []int{evKey, evAbs}, Key: []int{evBtnTouch}, Abs: []int{absX},
+		AbsRanges: map[int]AbsRange{absX: {Min: 0, Max: 1024, Resolution: 12}}}
+
+	var buf bytes.Buffer
+	err := r.Save(&buf)
+	if err != nil {
+		t.Fatalf("Failed to save recording: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"delayUs": 8000`) {
+		t.Fatalf("Expected the delays to be saved in microseconds, but got %s", buf.String())
+	}
+
+	loaded, err := LoadRecording(&buf)
+	if err != nil {
+		t.Fatalf("Failed to load recording: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, r) {
+		t.Fatalf("Expected the loaded recording to equal the saved one\nExpected: %+v\nActual: %+v", r, loaded)
+	}
+}
+
+func TestRecordingIsSavedToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uinput-recording-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "touchpad.json")
+	r := newTestRecording()
+	err = r.SaveFile(path)
+	if err != nil {
+		t.Fatalf("Failed to save recording: %v", err)
+	}
+	loaded, err := LoadRecordingFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load recording: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, r) {
+		t.Fatalf("Expected the loaded recording to equal the saved one\nExpected: %+v\nActual: %+v", r, loaded)
+	}
+}
+
+func TestLoadedRecordingIsPlayedBack(t *testing.T) {
+	r, err := LoadRecording(strings.NewReader(`{"version": 1, "events": [
+		{"delayUs": 0, "type": 1, "code": 30, "value": 1},
+		{"delayUs": 0, "type": 0, "code": 0, "value": 0},
+		{"delayUs": 1000, "type": 1, "code": 30, "value": 0},
+		{"delayUs": 0, "type": 0, "code": 0, "value": 0}
+	]}`))
+	if err != nil {
+		t.Fatalf("Failed to load recording: %v", err)
+	}
+
+	rec := NewMacroRecorder()
+	err = r.Play(rec)
+	if err != nil {
+		t.Fatalf("Failed to play recording: %v", err)
+	}
+	played := rec.Macro()
+	if len(played.Events) != 4 {
+		t.Fatalf("Expected 4 events to be played, but got %+v", played.Events)
+	}
+	if played.Events[2].Code != KeyA || played.Events[2].Value != btnStateReleased {
+		t.Fatalf("Expected the key to be released, but got %+v", played.Events[2])
+	}
+	if played.Events[2].Time < time.Millisecond {
+		t.Fatalf("Expected the release to be delayed by 1ms, but got %v", played.Events[2].Time)
+	}
+}
+
+func TestLoadRecordingFailsOnInvalidInput(t *testing.T) {
+	for _, input := range []string{
+		`{"version": 1, "events": [`,
+		`{"version": 2, "events": []}`,
+		`{"version": 1, "events": [{"delayUs": -1, "type": 1, "code": 30, "value": 1}]}`,
+	} {
+		_, err := LoadRecording(strings.NewReader(input))
+		if err == nil {
+			t.Fatalf("Expected loading %s to fail, but got no error.", input)
+		}
+	}
+}
+
+func TestSaveRecordingFailsOnUnsortedEvents(t *testing.T) {
+	r := &Recording{Macro: Macro{Events: []MacroEvent{
+		{Time: time.Second, Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Time: 0, Type: evKey, Code: KeyA, Value: btnStateReleased},
+	}}}
+	if err := r.Save(ioutil.Discard); err == nil {
+		t.Fatalf("Expected saving to fail due to unsorted events, but got no error.")
+	}
+}
+
+func TestRecordingEventCapabilities(t *testing.T) {
+	caps := newTestRecording().EventCapabilities()
+	expected := Capabilities{
+		EV:        []int{evKey, evAbs},
+		Key:       []int{evBtnTouch},
+		Abs:       []int{absX},
+		AbsRanges: map[int]AbsRange{absX: {Min: 100, Max: 300}},
+	}
+	if !reflect.DeepEqual(caps, expected) {
+		t.Fatalf("Expected capabilities %+v, but got %+v", expected, caps)
+	}
+}
@@ -0,0 +1,96 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// A RemapFunc maps an event of the source device of a Remapper to the events that are sent to the target device in its
+// place. Returning the event unchanged passes it through, while returning no events drops it.
+type RemapFunc func(ev Event) []Event
+
+// remapPollInterval is the interval in which a Remapper checks whether it has been stopped while waiting for events.
+const remapPollInterval = 50 * time.Millisecond
+
+// A Remapper grabs an existing input device (like a physical keyboard), maps each of its events using a RemapFunc and
+// forwards the resulting events to a virtual device. This allows to remap keys or buttons, to implement key chords or
+// to filter out events, without changing any configuration of the system:
+//
+//	keyboard, _ := uinput.CreateKeyboard("/dev/uinput", []byte("remapped keyboard"))
+//	source, _ := uinput.OpenEventDevice("/dev/input/event3")
+//	remapper, _ := uinput.NewRemapper(source, keyboard, uinput.RemapKeys(map[int]int{uinput.KeyCapslock: uinput.KeyEsc}))
+//	err := remapper.Run(ctx)
+//
+// Note that sync events are passed to the RemapFunc as well, so that a RemapFunc may decide how to terminate frames.
+type Remapper struct {
+	source  *EventDevice
+	target  RawEventSender
+	mapping RemapFunc
+}
+
+// NewRemapper will create a remapper forwarding the events of source to target, using the given mapping.
+func NewRemapper(source *EventDevice, target RawEventSender, mapping RemapFunc) (*Remapper, error) {
+	if source == nil || target == nil {
+		return nil, fmt.Errorf("failed to create remapper. The source and target devices must not be nil")
+	}
+	if mapping == nil {
+		return nil, fmt.Errorf("failed to create remapper. The mapping must not be nil")
+	}
+	return &Remapper{source: source, target: target, mapping: mapping}, nil
+}
+
+// Run will grab the source device and forward its events until the given context is done, in which case the error
+// of the context is returned. Keys that have been pressed on the target device, but not released yet are released
+// when stopping, and the grab of the source device is released as well.
+func (r *Remapper) Run(ctx context.Context) error {
+	err := r.source.Grab()
+	if err != nil {
+		return err
+	}
+	err = r.forward(ctx)
+	// a failure to release the grab supersedes the error of the context, but not a failure while forwarding
+	ungrabErr := r.source.Ungrab()
+	if ungrabErr != nil && err == ctx.Err() {
+		return ungrabErr
+	}
+	return err
+}
+
+func (r *Remapper) forward(ctx context.Context) error {
+	held := make(heldKeys)
+	for {
+		err := ctx.Err()
+		if err != nil {
+			return held.releaseAfter(r.target, err)
+		}
+		ev, ok, err := r.source.ReadEvent(remapPollInterval)
+		if err != nil {
+			return held.releaseAfter(r.target, err)
+		}
+		if !ok {
+			continue
+		}
+
+		for _, mapped := range r.mapping(ev.Event) {
+			err = r.target.SendRawEvent(mapped.Type, mapped.Code, mapped.Value)
+			if err != nil {
+				return fmt.Errorf("failed to forward event %+v: %w", mapped, err)
+			}
+			held.track(mapped.Type, mapped.Code, mapped.Value)
+		}
+	}
+}
+
+// RemapKeys returns a RemapFunc that replaces the keys (or buttons) in the given map by the keys they are mapped to,
+// passing all other events through unchanged.
+func RemapKeys(keys map[int]int) RemapFunc {
+	return func(ev Event) []Event {
+		if ev.Type == evKey {
+			if key, ok := keys[int(ev.Code)]; ok {
+				ev.Code = uint16(key)
+			}
+		}
+		return []Event{ev}
+	}
+}
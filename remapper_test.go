@@ -0,0 +1,105 @@
+package uinput
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRemapperForwardsMappedEventsAndReleasesHeldKeys(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer w.Close()
+	source := &EventDevice{file: r}
+	defer source.Close()
+	target := NewMacroRecorder()
+
+	remapper, err := NewRemapper(source, target, RemapKeys(map[int]int{KeyCapslock: KeyEsc}))
+	if err != nil {
+		t.Fatalf("Failed to create remapper: %v", err)
+	}
+	for _, iev := range []inputEvent{
+		{Type: evKey, Code: KeyCapslock, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	} {
+		buf, err := inputEventToBuffer(iev)
+		if err != nil {
+			t.Fatalf("Failed to encode event: %v", err)
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			t.Fatalf("Failed to write event: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*remapPollInterval)
+	defer cancel()
+	err = remapper.forward(ctx)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected forwarding to stop with %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	expected := []Event{
+		{Type: evKey, Code: KeyEsc, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyEsc, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	events := target.Macro().Events
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		got := Event{Type: events[i].Type, Code: events[i].Code, Value: events[i].Value}
+		if got != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], got)
+		}
+	}
+}
+
+func TestRemapperDropsEventsMappedToNothing(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer w.Close()
+	source := &EventDevice{file: r}
+	defer source.Close()
+	target := NewMacroRecorder()
+
+	remapper, err := NewRemapper(source, target, func(Event) []Event { return nil })
+	if err != nil {
+		t.Fatalf("Failed to create remapper: %v", err)
+	}
+	buf, err := inputEventToBuffer(inputEvent{Type: evKey, Code: KeyA, Value: btnStatePressed})
+	if err != nil {
+		t.Fatalf("Failed to encode event: %v", err)
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.Fatalf("Failed to write event: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_ = remapper.forward(ctx)
+	if len(target.Macro().Events) != 0 {
+		t.Fatalf("Expected all events to be dropped, but got %+v", target.Macro().Events)
+	}
+}
+
+func TestRemapperCreationFailsWithoutMapping(t *testing.T) {
+	_, err := NewRemapper(&EventDevice{}, NewMacroRecorder(), nil)
+	if err == nil {
+		t.Fatalf("Expected remapper creation to fail without a mapping, but got no error.")
+	}
+}
@@ -0,0 +1,37 @@
+package uinput
+
+import "os"
+
+// remoteKeys are the keys of a remote control, as used by media centers (like Kodi) and TV boxes. Note that the select
+// button is reported as KeyEnter, since KEY_OK is out of the range of keys supported by this package.
+var remoteKeys = []int{
+	KeyUp, KeyDown, KeyLeft, KeyRight, KeyEnter, KeyBack, KeyHomepage, KeyMenu, KeyExit, KeyPower,
+	KeyVolumeup, KeyVolumedown, KeyMute,
+	KeyPlaypause, KeyStopcd, KeyNextsong, KeyPrevioussong, KeyRewind, KeyFastforward,
+}
+
+// CreateRemote will create a keyboard emulating the remote control of a TV box, which reports to be attached via
+// bluetooth and only registers navigation keys (arrows, KeyEnter for OK, KeyBack and KeyHomepage), volume keys and media
+// keys. Media centers tend to misbehave when facing a full keyboard, e.g. by showing an on-screen keyboard or
+// switching to keyboard navigation. The given options are applied after the preset, so that e.g. WithKeys may still
+// be used to register another set of keys.
+func CreateRemote(path string, name []byte, opts ...DeviceOption) (Keyboard, error) {
+	return CreateKeyboard(path, name, remoteOptions(opts)...)
+}
+
+// CreateRemoteFromFile will create a remote control just like CreateRemote, but uses the given uinput device file,
+// which has been opened by the caller already. The remote control takes ownership of the file (see
+// CreateKeyboardFromFile).
+func CreateRemoteFromFile(file *os.File, name []byte, opts ...DeviceOption) (Keyboard, error) {
+	return CreateKeyboardFromFile(file, name, remoteOptions(opts)...)
+}
+
+// NewRemote is the same as CreateRemote, but takes the name as a string, which is truncated if it exceeds 80 bytes (see
+// WithNameTruncation).
+func NewRemote(path string, name string, opts ...DeviceOption) (Keyboard, error) {
+	return CreateRemote(path, []byte(name), withStringName(opts)...)
+}
+
+func remoteOptions(opts []DeviceOption) []DeviceOption {
+	return append([]DeviceOption{WithKeys(remoteKeys), WithBusType(BusBluetooth)}, opts...)
+}
@@ -0,0 +1,58 @@
+package uinput
+
+import (
+	"testing"
+)
+
+func TestRemoteNavigation(t *testing.T) {
+	remote, err := CreateRemote("/dev/uinput", []byte("Test Remote"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual remote. Last error was: %s\n", err)
+	}
+	defer remote.Close()
+
+	for _, key := range []int{KeyDown, KeyEnter, KeyBack, KeyHomepage} {
+		err = remote.KeyPress(key)
+		if err != nil {
+			t.Fatalf("Failed to press key %d. Last error was: %s\n", key, err)
+		}
+	}
+}
+
+func TestRemoteOptionsPresetKeysAndBus(t *testing.T) {
+	options := newDeviceOptions(remoteOptions(nil))
+	if options.busType != BusBluetooth {
+		t.Fatalf("Expected bus type %#x, but got %#x", BusBluetooth, options.busType)
+	}
+	if len(options.keys) != len(remoteKeys) {
+		t.Fatalf("Expected the remote keys to be registered, but got %v", options.keys)
+	}
+	if err := validateKeys(options.keys); err != nil {
+		t.Fatalf("Expected the remote keys to be valid, but got %v", err)
+	}
+}
+
+func TestRemoteOptionsMayBeOverridden(t *testing.T) {
+	options := newDeviceOptions(remoteOptions([]DeviceOption{WithBusType(BusUsb), WithKeys([]int{KeyEnter})}))
+	if options.busType != BusUsb {
+		t.Fatalf("Expected bus type %#x, but got %#x", BusUsb, options.busType)
+	}
+	if len(options.keys) != 1 || options.keys[0] != KeyEnter {
+		t.Fatalf("Expected the keys to be overridden, but got %v", options.keys)
+	}
+}
+
+func TestRemoteCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateRemote("", []byte("RemoteDevice"))
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestCreateRemoteFromFileFailsOnNilFile(t *testing.T) {
+	_, err := CreateRemoteFromFile(nil, []byte("Test Remote"))
+	if err == nil {
+		t.Fatalf("Expected remote creation to fail due to a missing device file, but got no error.")
+	}
+}
@@ -0,0 +1,250 @@
+package uinput
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// resilientKeyboard is a keyboard that recreates its device once writing to it fails because the device is gone.
+type resilientKeyboard struct {
+	mu      sync.Mutex
+	kb      Keyboard
+	create  func() (Keyboard, error)
+	repress bool
+	lost    func(error) bool
+	onClose closeHooks
+}
+
+// CreateResilientKeyboard will create a new keyboard just like CreateKeyboard, which transparently recreates the
+// virtual device with the same configuration if it gets destroyed (e.g. after a suspend or a change of namespaces).
+// This is meant for long running daemons, which would otherwise have to detect and handle such failures themselves.
+//
+// A lost device is detected by the error of an operation, which is then repeated on the new device. Since an operation
+// is repeated as a whole, events that were delivered before the device was lost (e.g. part of a typed text) may be
+// repeated as well. If repressKeys is set, the keys held down by the lost device are pressed again on the new device.
+func CreateResilientKeyboard(path string, name []byte, repressKeys bool, opts ...DeviceOption) (Keyboard, error) {
+	rk, err := newResilientKeyboard(func() (Keyboard, error) {
+		return CreateKeyboard(path, name, opts...)
+	}, repressKeys)
+	if err != nil {
+		return nil, err
+	}
+	return rk, nil
+}
+
+// NewResilientKeyboard is the same as CreateResilientKeyboard, but takes the name as a string, which is truncated if it
+// exceeds 80 bytes (see WithNameTruncation).
+func NewResilientKeyboard(path string, name string, repressKeys bool, opts ...DeviceOption) (Keyboard, error) {
+	return CreateResilientKeyboard(path, []byte(name), repressKeys, withStringName(opts)...)
+}
+
+func newResilientKeyboard(create func() (Keyboard, error), repressKeys bool) (*resilientKeyboard, error) {
+	kb, err := create()
+	if err != nil {
+		return nil, err
+	}
+	return &resilientKeyboard{kb: kb, create: create, repress: repressKeys, lost: isDeviceLost}, nil
+}
+
+// isDeviceLost reports whether the error was caused by a device that no longer exists.
+func isDeviceLost(err error) bool {
+	return errors.Is(err, syscall.ENODEV) || errors.Is(err, syscall.EIO) || errors.Is(err, syscall.EPIPE)
+}
+
+// do performs the operation on the current device. If the device turns out to be lost, it is recreated and the
+// operation is performed once more.
+func (rk *resilientKeyboard) do(op func(Keyboard) error) error {
+	rk.mu.Lock()
+	defer rk.mu.Unlock()
+	err := op(rk.kb)
+	if err == nil || !rk.lost(err) {
+		return err
+	}
+	err = rk.recreate()
+	if err != nil {
+		return err
+	}
+	return op(rk.kb)
+}
+
+// recreate replaces the lost device with a new one, pressing the keys held down by the lost device again if requested.
+func (rk *resilientKeyboard) recreate() error {
+	pressed := rk.kb.PressedKeys()
+	_ = rk.kb.Close()
+	kb, err := rk.create()
+	if err != nil {
+		return fmt.Errorf("failed to recreate lost keyboard: %w", err)
+	}
+	rk.kb = kb
+	if !rk.repress {
+		return nil
+	}
+	for _, key := range pressed {
+		err = kb.KeyDown(key)
+		if err != nil {
+			return fmt.Errorf("failed to press key %d on the recreated keyboard: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// current returns the current device, which is only used for operations that do not write to the device.
+func (rk *resilientKeyboard) current() Keyboard {
+	rk.mu.Lock()
+	defer rk.mu.Unlock()
+	return rk.kb
+}
+
+func (rk *resilientKeyboard) KeyPress(key int) error {
+	return rk.do(func(kb Keyboard) error { return kb.KeyPress(key) })
+}
+
+func (rk *resilientKeyboard) KeyDown(key int) error {
+	return rk.do(func(kb Keyboard) error { return kb.KeyDown(key) })
+}
+
+func (rk *resilientKeyboard) KeyUp(key int) error {
+	return rk.do(func(kb Keyboard) error { return kb.KeyUp(key) })
+}
+
+func (rk *resilientKeyboard) PressedKeys() []int {
+	return rk.current().PressedKeys()
+}
+
+func (rk *resilientKeyboard) IsPressed(key int) bool {
+	return rk.current().IsPressed(key)
+}
+
+func (rk *resilientKeyboard) KeyPressAndWaitLED(key int, led int, timeout time.Duration) (bool, error) {
+	var ok bool
+	err := rk.do(func(kb Keyboard) (err error) {
+		ok, err = kb.KeyPressAndWaitLED(key, led, timeout)
+		return err
+	})
+	return ok, err
+}
+
+func (rk *resilientKeyboard) FetchLEDState() (LEDState, error) {
+	return rk.current().FetchLEDState()
+}
+
+func (rk *resilientKeyboard) EmitKeyEvents(events []KeyRaw) error {
+	return rk.do(func(kb Keyboard) error { return kb.EmitKeyEvents(events) })
+}
+
+func (rk *resilientKeyboard) Type(text string) error {
+	return rk.do(func(kb Keyboard) error { return kb.Type(text) })
+}
+
+func (rk *resilientKeyboard) TypeWithLayout(text string, layout Layout) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeWithLayout(text, layout) })
+}
+
+func (rk *resilientKeyboard) TypeRune(r rune) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeRune(r) })
+}
+
+func (rk *resilientKeyboard) TypeHuman(text string, opts ...TypingOption) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeHuman(text, opts...) })
+}
+
+func (rk *resilientKeyboard) TypeCtx(ctx context.Context, text string) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeCtx(ctx, text) })
+}
+
+func (rk *resilientKeyboard) TypeToWindow(windowTitle string, text string) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeToWindow(windowTitle, text) })
+}
+
+func (rk *resilientKeyboard) TypeVerified(text string) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeVerified(text) })
+}
+
+func (rk *resilientKeyboard) TypeCompose(sequence ...int) error {
+	return rk.do(func(kb Keyboard) error { return kb.TypeCompose(sequence...) })
+}
+
+func (rk *resilientKeyboard) KeyCombo(keys ...int) error {
+	return rk.do(func(kb Keyboard) error { return kb.KeyCombo(keys...) })
+}
+
+func (rk *resilientKeyboard) SwitchVT(n int) error {
+	return rk.do(func(kb Keyboard) error { return kb.SwitchVT(n) })
+}
+
+func (rk *resilientKeyboard) SelectWord(ctx EditingContext) error {
+	return rk.do(func(kb Keyboard) error { return kb.SelectWord(ctx) })
+}
+
+func (rk *resilientKeyboard) SelectToLineEnd(ctx EditingContext) error {
+	return rk.do(func(kb Keyboard) error { return kb.SelectToLineEnd(ctx) })
+}
+
+func (rk *resilientKeyboard) MagicSysRq(command byte) error {
+	return rk.do(func(kb Keyboard) error { return kb.MagicSysRq(command) })
+}
+
+func (rk *resilientKeyboard) SetRepeat(delayMs int, periodMs int) error {
+	return rk.do(func(kb Keyboard) error { return kb.SetRepeat(delayMs, periodMs) })
+}
+
+// Grab will grab the current device. Note that a recreated device is not grabbed.
+func (rk *resilientKeyboard) Grab() error {
+	return rk.current().Grab()
+}
+
+func (rk *resilientKeyboard) Ungrab() error {
+	return rk.current().Ungrab()
+}
+
+func (rk *resilientKeyboard) MeasureLatency(key int) (time.Duration, error) {
+	var latency time.Duration
+	err := rk.do(func(kb Keyboard) (err error) {
+		latency, err = kb.MeasureLatency(key)
+		return err
+	})
+	return latency, err
+}
+
+func (rk *resilientKeyboard) FetchSyspath() (string, error) {
+	return rk.current().FetchSyspath()
+}
+
+func (rk *resilientKeyboard) EventNode() (string, error) {
+	return rk.current().EventNode()
+}
+
+func (rk *resilientKeyboard) SendRawEvent(evType uint16, code uint16, value int32) error {
+	return rk.do(func(kb Keyboard) error { return kb.SendRawEvent(evType, code, value) })
+}
+
+func (rk *resilientKeyboard) Sync() error {
+	return rk.do(func(kb Keyboard) error { return kb.Sync() })
+}
+
+// Batch returns an empty batch of events for the current device. Since a batch is bound to the device it was created
+// for, sending it is not repeated if the device is lost.
+func (rk *resilientKeyboard) Batch() *EventBatch {
+	return rk.current().Batch()
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration. Unlike the callbacks of the devices themselves, they are not invoked if a lost device
+// is replaced.
+func (rk *resilientKeyboard) OnClose(callback func()) {
+	rk.mu.Lock()
+	defer rk.mu.Unlock()
+	rk.onClose.add(callback)
+}
+
+func (rk *resilientKeyboard) Close() error {
+	rk.mu.Lock()
+	defer rk.mu.Unlock()
+	err := rk.kb.Close()
+	rk.onClose.run()
+	return err
+}
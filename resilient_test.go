@@ -0,0 +1,115 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// newTestResilientKeyboard returns a resilient keyboard using fake devices, which are lost once they are closed.
+func newTestResilientKeyboard(t *testing.T, repressKeys bool) (*resilientKeyboard, *Fake, *int) {
+	fake := NewFake()
+	created := 0
+	rk, err := newResilientKeyboard(func() (Keyboard, error) {
+		created++
+		return fake.CreateKeyboard()
+	}, repressKeys)
+	if err != nil {
+		t.Fatalf("Failed to create resilient keyboard: %v", err)
+	}
+	rk.lost = func(err error) bool {
+		return errors.Is(err, os.ErrClosed)
+	}
+	return rk, fake, &created
+}
+
+// loseDevice closes the current device behind its back.
+func loseDevice(t *testing.T, rk *resilientKeyboard) {
+	err := closeDevice(rk.kb.(*vKeyboard).deviceFile)
+	if err != nil {
+		t.Fatalf("Failed to close device: %v", err)
+	}
+}
+
+func TestResilientKeyboardRecreatesLostDevice(t *testing.T) {
+	rk, fake, created := newTestResilientKeyboard(t, true)
+	defer rk.Close()
+
+	err := rk.KeyDown(KeyLeftshift)
+	if err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+	loseDevice(t, rk)
+	err = rk.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Expected the key press to succeed on the recreated device, but got %v", err)
+	}
+	if *created != 2 {
+		t.Fatalf("Expected the device to be recreated once, but it was created %d times", *created)
+	}
+
+	events := fake.Events()
+	expected := []Event{
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyLeftshift, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+	if !rk.IsPressed(KeyLeftshift) {
+		t.Fatalf("Expected the held key to be pressed on the recreated device")
+	}
+}
+
+func TestResilientKeyboardDoesNotRepressKeysUnlessRequested(t *testing.T) {
+	rk, _, _ := newTestResilientKeyboard(t, false)
+	defer rk.Close()
+
+	err := rk.KeyDown(KeyLeftshift)
+	if err != nil {
+		t.Fatalf("Failed to press key: %v", err)
+	}
+	loseDevice(t, rk)
+	err = rk.KeyPress(KeyA)
+	if err != nil {
+		t.Fatalf("Expected the key press to succeed on the recreated device, but got %v", err)
+	}
+	if len(rk.PressedKeys()) != 0 {
+		t.Fatalf("Expected no keys to be held down, but got %v", rk.PressedKeys())
+	}
+}
+
+func TestResilientKeyboardKeepsOtherErrors(t *testing.T) {
+	rk, _, created := newTestResilientKeyboard(t, false)
+	defer rk.Close()
+
+	err := rk.KeyPress(keyMax + 1)
+	if !errors.Is(err, ErrKeyOutOfRange) {
+		t.Fatalf("Expected ErrKeyOutOfRange, but got %v", err)
+	}
+	if *created != 1 {
+		t.Fatalf("Expected the device not to be recreated, but it was created %d times", *created)
+	}
+}
+
+func TestIsDeviceLost(t *testing.T) {
+	err := &os.PathError{Op: "write", Path: "/dev/uinput", Err: syscall.ENODEV}
+	if !isDeviceLost(err) {
+		t.Fatalf("Expected %v to indicate a lost device", err)
+	}
+	if isDeviceLost(os.ErrClosed) {
+		t.Fatalf("Expected a closed device not to be considered lost")
+	}
+}
@@ -0,0 +1,137 @@
+package uinput
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// selfCheckDevicePath is the uinput device used by VerifyEventLayout.
+const selfCheckDevicePath = "/dev/uinput"
+
+// VerifyEventLayout checks that the input_event struct used by this package matches the layout the running kernel
+// uses. It creates a temporary keyboard, grabs its event node, issues a key press and verifies that the events read
+// back decode to the same type, code and value that were sent. A mismatch (e.g. due to a different timeval size on
+// the target architecture) would otherwise silently lead to garbage events.
+// Note that this requires access to /dev/uinput as well as to the event node of the created device.
+func VerifyEventLayout() error {
+	kbd, err := CreateKeyboard(selfCheckDevicePath, []byte("uinput event layout check"))
+	if err != nil {
+		return fmt.Errorf("failed to create keyboard for layout check: %w", err)
+	}
+	defer kbd.Close()
+
+	err = kbd.Grab()
+	if err != nil {
+		return fmt.Errorf("failed to grab keyboard for layout check: %w", err)
+	}
+	defer kbd.Ungrab()
+
+	err = kbd.KeyPress(KeyA)
+	if err != nil {
+		return fmt.Errorf("failed to send key press for layout check: %w", err)
+	}
+
+	eventFile := kbd.(*vKeyboard).eventFile
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+		{Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	for i, want := range expected {
+		ev, ok, err := readEvent(eventFile, time.Second)
+		if err != nil {
+			return fmt.Errorf("failed to read back event %d: %w", i, err)
+		}
+		if !ok {
+			return fmt.Errorf("no event was read back for event %d", i)
+		}
+		if ev.Type != want.Type || ev.Code != want.Code || ev.Value != want.Value {
+			return fmt.Errorf("event layout mismatch: expected type %d, code %d, value %d but got type %d, code %d, value %d",
+				want.Type, want.Code, want.Value, ev.Type, ev.Code, ev.Value)
+		}
+		if ev.Time.Sec == 0 && ev.Time.Usec == 0 {
+			return fmt.Errorf("event layout mismatch: event %d has no timestamp", i)
+		}
+	}
+	return nil
+}
+
+// miscDevicesPath lists the registered misc devices, which includes uinput once the kernel module is loaded (or
+// built into the kernel).
+const miscDevicesPath = "/proc/misc"
+
+// CheckAccess verifies that virtual devices may be created using the uinput device file at the given path (usually
+// /dev/uinput), without actually creating a device. It checks that the device file exists, that the uinput kernel
+// module is loaded and that the current user may open the device file for writing. The returned error describes how
+// to fix the problem and matches ErrUinputMissing or ErrPermission using errors.Is.
+func CheckAccess(path string) error {
+	err := checkPlatform()
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return fmt.Errorf("device path must not be empty")
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		if !uinputModuleLoaded() {
+			return sentinelErrorf(ErrUinputMissing, "%s does not exist since the uinput kernel module is not loaded. "+
+				"Load it using 'modprobe uinput' (as root)", path)
+		}
+		return sentinelErrorf(ErrUinputMissing, "%s does not exist although the uinput kernel module is loaded. "+
+			"Check the path or whether udev created the device file", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("%s is not a character device", path)
+	}
+
+	deviceFile, err := createDeviceFile(path)
+	if errors.Is(err, ErrPermission) {
+		return &sentinelError{
+			msg: fmt.Sprintf("no permission to open %s. Add the user to the group owning it (usually 'input') or "+
+				"add a udev rule granting access, then log in again", path),
+			sentinel: ErrPermission,
+			cause:    err,
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return deviceFile.Close()
+}
+
+// uinputModuleLoaded reports whether uinput is registered as a misc device. If the list of misc devices cannot be read,
+// the module is assumed to be loaded, so that the error message does not point in the wrong direction.
+func uinputModuleLoaded() bool {
+	devices, err := os.Open(miscDevicesPath)
+	if err != nil {
+		return true
+	}
+	defer devices.Close()
+
+	registered, err := miscDeviceRegistered(devices, "uinput")
+	return registered || err != nil
+}
+
+// miscDeviceRegistered checks whether the device list (in the format of /proc/misc, i.e. a minor number and a name per
+// line) contains a device with the given name.
+func miscDeviceRegistered(devices io.Reader, name string) (bool, error) {
+	scanner := bufio.NewScanner(devices)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,72 @@
+package uinput
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestVerifyEventLayout(t *testing.T) {
+	err := VerifyEventLayout()
+	if err != nil {
+		t.Fatalf("Failed to verify event layout. Last error was: %s\n", err)
+	}
+}
+
+func TestCheckAccessFailsOnMissingDeviceFile(t *testing.T) {
+	err := CheckAccess("/dev/does/not/exist")
+	if !errors.Is(err, ErrUinputMissing) {
+		t.Fatalf("Expected error to match ErrUinputMissing, but got %v", err)
+	}
+	if !strings.Contains(err.Error(), "/dev/does/not/exist") {
+		t.Fatalf("Expected error to mention the path, but got %v", err)
+	}
+}
+
+func TestCheckAccessFailsOnEmptyPath(t *testing.T) {
+	err := CheckAccess("")
+	if err == nil {
+		t.Fatalf("Expected check to fail on an empty path")
+	}
+}
+
+func TestCheckAccessFailsOnRegularFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "uinput-check")
+	if err != nil {
+		t.Fatalf("Failed to create temporary file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	err = CheckAccess(file.Name())
+	if err == nil || !strings.Contains(err.Error(), "not a character device") {
+		t.Fatalf("Expected check to refuse a regular file, but got %v", err)
+	}
+}
+
+func TestCheckAccessSucceedsOnWritableCharacterDevice(t *testing.T) {
+	err := CheckAccess("/dev/null")
+	if err != nil {
+		t.Fatalf("Expected check to succeed on /dev/null, but got %v", err)
+	}
+}
+
+func TestMiscDeviceRegistered(t *testing.T) {
+	devices := "236 device-mapper\n223 uinput\n 60 cpu_dma_latency\n"
+	for name, expected := range map[string]bool{
+		"uinput":          true,
+		"cpu_dma_latency": true,
+		"input":           false,
+		"223":             false,
+	} {
+		registered, err := miscDeviceRegistered(strings.NewReader(devices), name)
+		if err != nil {
+			t.Fatalf("Failed to parse device list: %v", err)
+		}
+		if registered != expected {
+			t.Fatalf("Expected registered to be %v for %q, but got %v", expected, name, registered)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package uinput
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// trackSync records whether the given buffer, which has just been written to the device, ends with a sync event.
+func trackSync(deviceFile *device, buf []byte) {
+	size := EventSize
+	if len(buf) < size {
+		return
+	}
+	iev, err := bufferToInputEvent(buf[len(buf)-size:])
+	if err != nil {
+		return
+	}
+	if iev.Type == evSyn {
+		atomic.StoreInt32(&deviceFile.unsynced, 0)
+	} else {
+		atomic.StoreInt32(&deviceFile.unsynced, 1)
+	}
+}
+
+// flushDevice terminates pending events of the device with a sync event and waits for the destroy delay of the device
+// (if any), so that consumers receive the last events before the device is destroyed.
+func flushDevice(deviceFile *device) {
+	if atomic.LoadInt32(&deviceFile.unsynced) != 0 {
+		// the device is destroyed regardless of whether the sync succeeds, e.g. if the device is gone already
+		_ = syncEvents(deviceFile)
+	}
+	time.Sleep(deviceFile.destroyDelay)
+}
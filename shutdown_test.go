@@ -0,0 +1,46 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCloseSyncsPendingEvents(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.SendRawEvent(evKey, KeyA, btnStatePressed)
+	if err != nil {
+		t.Fatalf("Failed to send raw event: %v", err)
+	}
+	_ = vk.Close()
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %+v", len(expected), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+func TestCloseWaitsForDestroyDelay(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	delay := 20 * time.Millisecond
+	options := newDeviceOptions([]DeviceOption{WithDestroyDelay(delay)})
+	vk := &vKeyboard{deviceFile: newDevice(file, options), options: options, pressed: make(map[int]bool)}
+
+	start := time.Now()
+	_ = vk.Close()
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Fatalf("Expected Close to wait for %v before destroying the device, but it took %v", delay, elapsed)
+	}
+}
@@ -0,0 +1,198 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Switch codes that may be passed to CreateSwitchDevice (see SW_* in input-event-codes.h).
+const (
+	SwitchLid              = 0x00 // set = lid shut
+	SwitchTabletMode       = 0x01 // set = tablet mode (of a convertible laptop)
+	SwitchHeadphoneInsert  = 0x02 // set = inserted
+	SwitchRfkillAll        = 0x03 // set = radio enabled
+	SwitchMicrophoneInsert = 0x04 // set = inserted
+	SwitchDock             = 0x05 // set = plugged into dock
+	SwitchLineoutInsert    = 0x06 // set = inserted
+	SwitchCameraLensCover  = 0x09 // set = lens covered
+	SwitchKeypadSlide      = 0x0a // set = keypad slide out
+	SwitchLineInInsert     = 0x0d // set = inserted
+	switchMax              = 0x10
+)
+
+// A SwitchDevice is an input device that reports the state of switches, like the lid switch of a laptop or the switch
+// that reports whether a convertible laptop is in tablet mode. This allows to test how software reacts to these
+// switches (e.g. suspending once the lid is shut).
+type SwitchDevice interface {
+	// SetSwitch will set the state of the given switch (true meaning that the switch is set, e.g. the lid is shut).
+	SetSwitch(code int, state bool) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
+	io.Closer
+}
+
+type vSwitchDevice struct {
+	name       []byte
+	deviceFile *device
+	switches   map[int]bool
+	onClose    closeHooks
+	mu         deviceMutex
+}
+
+// CreateSwitchDevice will create a new device that reports the given switches (e.g. SwitchLid). All switches are unset
+// initially. Note that the kernel only passes on changes of a switch state, so setting a switch to its current state
+// has no effect.
+func CreateSwitchDevice(path string, name []byte, switches []int, opts ...DeviceOption) (SwitchDevice, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return createVSwitchDevice(path, name, switches, opts)
+}
+
+// CreateSwitchDeviceFromFile will create a new switch device just like CreateSwitchDevice, but uses the given uinput
+// device file, which has been opened by the caller already. The switch device takes ownership of the file (see
+// CreateKeyboardFromFile).
+func CreateSwitchDeviceFromFile(file *os.File, name []byte, switches []int, opts ...DeviceOption) (SwitchDevice, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVSwitchDevice(file.Name(), name, switches, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVSwitchDevice(path string, name []byte, switches []int, opts []DeviceOption) (SwitchDevice, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(switches) == 0 {
+		return nil, fmt.Errorf("at least one switch is required")
+	}
+	registered := make(map[int]bool)
+	for _, code := range switches {
+		if code < 0 || code > switchMax {
+			return nil, fmt.Errorf("switch code %d is out of range", code)
+		}
+		registered[code] = true
+	}
+
+	fd, err := createSwitchDevice(path, name, switches, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vSwitchDevice{name: name, deviceFile: fd, switches: registered, mu: newDeviceMutex(options)}, nil
+}
+
+// NewSwitchDevice is the same as CreateSwitchDevice, but takes the name as a string, which is truncated if it exceeds
+// 80 bytes (see WithNameTruncation).
+func NewSwitchDevice(path string, name string, switches []int, opts ...DeviceOption) (SwitchDevice, error) {
+	return CreateSwitchDevice(path, []byte(name), switches, withStringName(opts)...)
+}
+
+// SetSwitch will set the state of the given switch, which needs to be one of the switches the device was created with.
+func (vs *vSwitchDevice) SetSwitch(code int, state bool) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if !vs.switches[code] {
+		return fmt.Errorf("switch %d is not supported by this device", code)
+	}
+
+	var value int32
+	if state {
+		value = 1
+	}
+	err := writeEvent(vs.deviceFile, inputEvent{
+		Type:  evSw,
+		Code:  uint16(code),
+		Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to write switch event to device file: %w", err)
+	}
+
+	return syncEvents(vs.deviceFile)
+}
+
+// FetchSyspath will return the syspath to the device file.
+func (vs *vSwitchDevice) FetchSyspath() (string, error) {
+	return lookupSyspath(vs.deviceFile.File, vs.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vs *vSwitchDevice) EventNode() (string, error) {
+	return lookupEventNode(vs.deviceFile.File, vs.name)
+}
+
+// Close closes the device and releases the device.
+func (vs *vSwitchDevice) Close() error {
+	err := closeDevice(vs.deviceFile)
+	vs.onClose.run()
+	return err
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vs *vSwitchDevice) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	return sendRawEvent(vs.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vs *vSwitchDevice) Sync() error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	return syncEvents(vs.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vs *vSwitchDevice) OnClose(callback func()) {
+	vs.onClose.add(callback)
+}
+
+func createSwitchDevice(path string, name []byte, switches []int, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not create switch input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evSw))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register switch input device: %w", err)
+	}
+
+	for _, code := range switches {
+		err = ioctl(deviceFile, uiSetSwBit, uintptr(code))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register switch %d: %w", code, err)
+		}
+	}
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name: toUinputName(name),
+			ID:   options.inputID(0x081c)}, options)
+}
@@ -0,0 +1,99 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSwitchDeviceLid(t *testing.T) {
+	dev, err := CreateSwitchDevice("/dev/uinput", []byte("Test Switches"), []int{SwitchLid, SwitchTabletMode})
+	if err != nil {
+		t.Fatalf("Failed to create the virtual switch device. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	err = dev.SetSwitch(SwitchLid, true)
+	if err != nil {
+		t.Fatalf("Failed to shut lid. Last error was: %s\n", err)
+	}
+	err = dev.SetSwitch(SwitchLid, false)
+	if err != nil {
+		t.Fatalf("Failed to open lid. Last error was: %s\n", err)
+	}
+}
+
+func TestSwitchDeviceEmitsSwitchEvents(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vs := &vSwitchDevice{deviceFile: &device{File: file}, switches: map[int]bool{SwitchTabletMode: true}}
+
+	if err := vs.SetSwitch(SwitchTabletMode, true); err != nil {
+		t.Fatalf("Failed to set switch: %v", err)
+	}
+	if err := vs.SetSwitch(SwitchTabletMode, false); err != nil {
+		t.Fatalf("Failed to unset switch: %v", err)
+	}
+	if err := vs.SetSwitch(SwitchLid, true); err == nil {
+		t.Fatalf("Expected SetSwitch to fail for a switch that is not supported, but got no error.")
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evSw, Code: SwitchTabletMode, Value: 1},
+		{Type: evSyn, Code: synReport},
+		{Type: evSw, Code: SwitchTabletMode, Value: 0},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %d to be %+v, but got %+v", i, expected[i], events[i])
+		}
+	}
+}
+
+func TestSwitchDeviceCreationFailsOnInvalidSwitches(t *testing.T) {
+	_, err := CreateSwitchDevice("/dev/uinput", []byte("SwitchDevice"), nil)
+	if err == nil {
+		t.Fatalf("Expected creation to fail without any switch, but got no error.")
+	}
+	_, err = CreateSwitchDevice("/dev/uinput", []byte("SwitchDevice"), []int{switchMax + 1})
+	if err == nil {
+		t.Fatalf("Expected creation to fail due to a switch out of range, but got no error.")
+	}
+}
+
+func TestSwitchDeviceCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateSwitchDevice("", []byte("SwitchDevice"), []int{SwitchLid})
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestSwitchDeviceCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-switch-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register switch input device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateSwitchDevice(file.Name(), []byte("SwitchDevice"), []int{SwitchLid})
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestSwitchDeviceCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreateSwitchDevice("/dev/uinput", []byte(name), []int{SwitchLid})
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
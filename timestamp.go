@@ -0,0 +1,37 @@
+package uinput
+
+import (
+	"syscall"
+	"time"
+)
+
+// MonotonicTime returns the current time of CLOCK_MONOTONIC, which is the clock the kernel uses for the timestamps of
+// input events. It is the base for explicit timestamps of events (see EventBatch.EventAt).
+func MonotonicTime() (time.Duration, error) {
+	return monotonicTime()
+}
+
+// stampEvents sets the timestamp of all events in the buffer that do not have a timestamp yet to the current time of
+// CLOCK_MONOTONIC, provided that the device uses monotonic timestamps. The buffer is modified in place.
+func stampEvents(deviceFile *device, buf []byte) error {
+	if !deviceFile.timestamps {
+		return nil
+	}
+
+	now, err := monotonicTime()
+	if err != nil {
+		return err
+	}
+	for i := 0; i+EventSize <= len(buf); i += EventSize {
+		iev, err := decodeInputEvent(buf[i : i+EventSize])
+		if err != nil {
+			return err
+		}
+		if iev.Time.Sec != 0 || iev.Time.Usec != 0 {
+			continue
+		}
+		iev.Time = syscall.NsecToTimeval(now.Nanoseconds())
+		encodeInputEvent(buf[i:i+EventSize], iev)
+	}
+	return nil
+}
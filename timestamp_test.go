@@ -0,0 +1,94 @@
+package uinput
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBatchEventsCarryExplicitTimestamps(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	m := &Macro{Events: []MacroEvent{
+		{Time: 0, Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Time: 0, Type: evSyn, Code: synReport},
+		{Time: 1500 * time.Microsecond, Type: evKey, Code: KeyA, Value: btnStateReleased},
+		{Time: 1500 * time.Microsecond, Type: evSyn, Code: synReport},
+	}}
+	end := 10 * time.Second
+	err := newEventBatch(&device{File: file}).MacroAt(m, end).Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	if len(events) != len(m.Events) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(m.Events), len(events), events)
+	}
+	for i, ev := range events {
+		expected := syscall.NsecToTimeval((end - m.Duration() + m.Events[i].Time).Nanoseconds())
+		if ev.Time != expected {
+			t.Fatalf("Expected event %d to carry timestamp %+v, but got %+v", i, expected, ev.Time)
+		}
+	}
+}
+
+func TestBatchRejectsMacrosExceedingTimestampWindow(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	m := &Macro{Events: []MacroEvent{
+		{Time: 0, Type: evKey, Code: KeyA, Value: btnStatePressed},
+		{Time: TimestampWindow + time.Millisecond, Type: evKey, Code: KeyA, Value: btnStateReleased},
+	}}
+	err := newEventBatch(&device{File: file}).MacroAt(m, time.Minute).Flush()
+	if err == nil {
+		t.Fatalf("Expected flushing to fail due to a macro exceeding the timestamp window, but got no error.")
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected no events to be sent, but got %+v", events)
+	}
+}
+
+func TestBatchRejectsInvalidTimestamps(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	err := newEventBatch(&device{File: file}).EventAt(0, evKey, KeyA, btnStatePressed).Flush()
+	if err == nil {
+		t.Fatalf("Expected flushing to fail due to an invalid timestamp, but got no error.")
+	}
+}
+
+func TestMonotonicTimestampsAreApplied(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	fd := newDevice(file, newDeviceOptions([]DeviceOption{WithMonotonicTimestamps(true)}))
+
+	before, err := MonotonicTime()
+	if err != nil {
+		t.Fatalf("Failed to read monotonic clock: %v", err)
+	}
+	explicit := time.Second
+	err = newEventBatch(fd).KeyDown(KeyA).SyncAt(explicit).Flush()
+	if err != nil {
+		t.Fatalf("Failed to flush batch: %v", err)
+	}
+	after, err := MonotonicTime()
+	if err != nil {
+		t.Fatalf("Failed to read monotonic clock: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, but got %d: %+v", len(events), events)
+	}
+	stamp := time.Duration(events[0].Time.Nano())
+	if stamp < before.Truncate(time.Microsecond) || stamp > after {
+		t.Fatalf("Expected the event to be stamped between %v and %v, but got %v", before, after, stamp)
+	}
+	if events[1].Time != syscall.NsecToTimeval(explicit.Nanoseconds()) {
+		t.Fatalf("Expected the explicit timestamp to be kept, but got %+v", events[1].Time)
+	}
+}
@@ -1,9 +1,11 @@
 package uinput
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"time"
 )
 
 // A TouchPad is an input device that uses absolute axis events, meaning that you can specify
@@ -13,6 +15,19 @@ type TouchPad interface {
 	// MoveTo will move the cursor to the specified position on the screen
 	MoveTo(x int32, y int32) error
 
+	// GlideTo will move the cursor to the specified position in small steps spread over the given duration.
+	GlideTo(x int32, y int32, duration time.Duration) error
+
+	// GlideToCtx will move the cursor just like GlideTo, but stops once the given context is done.
+	GlideToCtx(ctx context.Context, x int32, y int32, duration time.Duration) error
+
+	// DragTo will move the cursor to the first position, press the left button, glide to the second position and release
+	// the button.
+	DragTo(x1 int32, y1 int32, x2 int32, y2 int32) error
+
+	// DragToCtx will drag just like DragTo, but stops and releases the button once the given context is done.
+	DragToCtx(ctx context.Context, x1 int32, y1 int32, x2 int32, y2 int32) error
+
 	// LeftClick will issue a single left click.
 	LeftClick() error
 
@@ -39,54 +54,121 @@ type TouchPad interface {
 	// TouchUp will end or ,more precisely, unset the touch event issued by TouchDown
 	TouchUp() error
 
+	// WheelHiRes will simulate a vertical high-resolution wheel movement, where 120 units amount to a single notch.
+	WheelHiRes(delta int32) error
+
 	// FetchSyspath will return the syspath to the device file.
 	FetchSyspath() (string, error)
 
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
 	io.Closer
 }
 
 type vTouchPad struct {
 	name       []byte
-	deviceFile *os.File
+	deviceFile *device
+	hiRes      hiResAccumulator
+	easing     Easing
+	drag       dragTiming
+	onClose    closeHooks
+	mu         deviceMutex
+
+	// the last position the cursor was moved to
+	x          int32
+	y          int32
+	positioned bool
 }
 
 // CreateTouchPad will create a new touchpad device. note that you will need to define the x and y-axis boundaries
-// (min and max) within which the cursor maybe moved around.
-func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (TouchPad, error) {
+// (min and max) within which the cursor maybe moved around. The touch pad reports a single contact only, so it does not
+// report the number of fingers on the pad (BTN_TOOL_DOUBLETAP and the like). Use CreateClickPad for multi-finger
+// gestures that are recognized by their finger count.
+func CreateTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts ...DeviceOption) (TouchPad, error) {
 	err := validateDevicePath(path)
 	if err != nil {
 		return nil, err
 	}
-	err = validateUinputName(name)
+	return createVTouchPad(path, name, minX, maxX, minY, maxY, opts)
+}
+
+// CreateTouchPadFromFile will create a new touch pad just like CreateTouchPad, but uses the given uinput device file,
+// which has been opened by the caller already. The touch pad takes ownership of the file (see CreateKeyboardFromFile).
+func CreateTouchPadFromFile(file *os.File, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts ...DeviceOption) (TouchPad, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVTouchPad(file.Name(), name, minX, maxX, minY, maxY, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, opts []DeviceOption) (TouchPad, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
 	if err != nil {
 		return nil, err
 	}
 
-	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY)
+	fd, err := createTouchPad(path, name, minX, maxX, minY, maxY, options)
 	if err != nil {
 		return nil, err
 	}
 
-	return vTouchPad{name: name, deviceFile: fd}, nil
+	return &vTouchPad{name: name, deviceFile: fd, easing: options.easing, drag: options.drag, mu: newDeviceMutex(options)}, nil
 }
 
-func (vTouch vTouchPad) MoveTo(x int32, y int32) error {
-	return sendAbsEvent(vTouch.deviceFile, x, y)
+// NewTouchPad is the same as CreateTouchPad, but takes the name as a string, which is truncated if it exceeds 80 bytes
+// (see WithNameTruncation).
+func NewTouchPad(path string, name string, minX int32, maxX int32, minY int32, maxY int32, opts ...DeviceOption) (TouchPad, error) {
+	return CreateTouchPad(path, []byte(name), minX, maxX, minY, maxY, withStringName(opts)...)
+}
+
+func (vTouch *vTouchPad) MoveTo(x int32, y int32) error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	return vTouch.moveTo(x, y)
+}
+
+// moveTo moves the cursor to the given position and keeps track of it. The device needs to be locked by the caller.
+func (vTouch *vTouchPad) moveTo(x int32, y int32) error {
+	err := sendAbsEvent(vTouch.deviceFile, x, y)
+	if err != nil {
+		return err
+	}
+	vTouch.x, vTouch.y, vTouch.positioned = x, y, true
+	return nil
 }
 
-func (vTouch vTouchPad) LeftClick() error {
+func (vTouch *vTouchPad) LeftClick() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	err := sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the LeftClick event: %v", err)
+		return fmt.Errorf("failed to issue the LeftClick event: %w", err)
 	}
 
 	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
 }
 
-func (vTouch vTouchPad) RightClick() error {
+func (vTouch *vTouchPad) RightClick() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	err := sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
 	if err != nil {
-		return fmt.Errorf("failed to issue the RightClick event: %v", err)
+		return fmt.Errorf("failed to issue the RightClick event: %w", err)
 	}
 
 	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
@@ -94,62 +176,108 @@ func (vTouch vTouchPad) RightClick() error {
 
 // LeftPress will simulate a press of the left mouse button. Note that the button will not be released until
 // LeftRelease is invoked.
-func (vTouch vTouchPad) LeftPress() error {
+func (vTouch *vTouchPad) LeftPress() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStatePressed)
 }
 
 // LeftRelease will simulate the release of the left mouse button.
-func (vTouch vTouchPad) LeftRelease() error {
+func (vTouch *vTouchPad) LeftRelease() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnLeft}, btnStateReleased)
 }
 
 // RightPress will simulate the press of the right mouse button. Note that the button will not be released until
 // RightRelease is invoked.
-func (vTouch vTouchPad) RightPress() error {
+func (vTouch *vTouchPad) RightPress() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStatePressed)
 }
 
 // RightRelease will simulate the release of the right mouse button.
-func (vTouch vTouchPad) RightRelease() error {
+func (vTouch *vTouchPad) RightRelease() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	return sendBtnEvent(vTouch.deviceFile, []int{evMouseBtnRight}, btnStateReleased)
 }
 
-func (vTouch vTouchPad) TouchDown() error {
+func (vTouch *vTouchPad) TouchDown() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	return sendBtnEvent(vTouch.deviceFile, []int{evBtnTouch}, btnStatePressed)
 }
 
-func (vTouch vTouchPad) TouchUp() error {
+func (vTouch *vTouchPad) TouchUp() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
 	return sendBtnEvent(vTouch.deviceFile, []int{evBtnTouch}, btnStateReleased)
 }
 
-func (vTouch vTouchPad) Close() error {
-	return closeDevice(vTouch.deviceFile)
+// WheelHiRes will simulate a vertical high-resolution wheel movement just like the mouse does (see Mouse.WheelHiRes),
+// which allows to scroll smoothly while using absolute positioning.
+func (vTouch *vTouchPad) WheelHiRes(delta int32) error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	if delta == 0 {
+		return nil
+	}
+	return sendWheelEvent(vTouch.deviceFile, false, vTouch.hiRes.add(delta), delta)
+}
+
+func (vTouch *vTouchPad) Close() error {
+	err := closeDevice(vTouch.deviceFile)
+	vTouch.onClose.run()
+	return err
 }
 
-func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32) (fd *os.File, err error) {
-	deviceFile, err := createDeviceFile(path)
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vTouch *vTouchPad) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	return sendRawEvent(vTouch.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vTouch *vTouchPad) Sync() error {
+	vTouch.mu.Lock()
+	defer vTouch.mu.Unlock()
+	return syncEvents(vTouch.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vTouch *vTouchPad) OnClose(callback func()) {
+	vTouch.onClose.add(callback)
+}
+
+func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
 	if err != nil {
-		return nil, fmt.Errorf("could not create absolute axis input device: %v", err)
+		return nil, fmt.Errorf("could not create absolute axis input device: %w", err)
 	}
 
 	err = registerDevice(deviceFile, uintptr(evKey))
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register key device: %v", err)
+		return nil, fmt.Errorf("failed to register key device: %w", err)
 	}
 	// register button events (in order to enable left and right click)
 	for _, event := range []int{evMouseBtnLeft, evMouseBtnRight, evBtnTouch} {
 		err = ioctl(deviceFile, uiSetKeyBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register button event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register button event %v: %w", event, err)
 		}
 	}
 
 	err = registerDevice(deviceFile, uintptr(evAbs))
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to register absolute axis input device: %v", err)
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
 	}
 
 	// register x and y-axis events
@@ -157,7 +285,21 @@ func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
 		if err != nil {
 			_ = deviceFile.Close()
-			return nil, fmt.Errorf("failed to register absolute axis event %v: %v", event, err)
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	// register wheel events (in order to enable scrolling)
+	err = registerDevice(deviceFile, uintptr(evRel))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register relative axis input device: %w", err)
+	}
+	for _, event := range []int{relWheel, relWheelHiRes} {
+		err = ioctl(deviceFile, uiSetRelBit, uintptr(event))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register wheel event %v: %w", event, err)
 		}
 	}
 
@@ -171,17 +313,13 @@ func createTouchPad(path string, name []byte, minX int32, maxX int32, minY int32
 
 	return createUsbDevice(deviceFile,
 		uinputUserDev{
-			Name: toUinputName(name),
-			ID: inputID{
-				Bustype: busUsb,
-				Vendor:  0x4711,
-				Product: 0x0817,
-				Version: 1},
+			Name:   toUinputName(name),
+			ID:     options.inputID(0x0817),
 			Absmin: absMin,
-			Absmax: absMax})
+			Absmax: absMax}, options)
 }
 
-func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
+func sendAbsEvent(deviceFile *device, xPos int32, yPos int32) error { // TODO: Perhaps move this to a more generic function? This conflicts with the gamepad ABS events which only have one value.
 	var ev [2]inputEvent
 	ev[0].Type = evAbs
 	ev[0].Code = absX
@@ -200,20 +338,20 @@ func sendAbsEvent(deviceFile *os.File, xPos int32, yPos int32) error { // TODO:
 	ev[1].Value = yPos
 
 	for _, iev := range ev {
-		buf, err := inputEventToBuffer(iev)
+		err := writeEvent(deviceFile, iev)
 		if err != nil {
-			return fmt.Errorf("writing abs event failed: %v", err)
-		}
-
-		_, err = deviceFile.Write(buf)
-		if err != nil {
-			return fmt.Errorf("failed to write abs event to device file: %v", err)
+			return fmt.Errorf("failed to write abs event to device file: %w", err)
 		}
 	}
 
 	return syncEvents(deviceFile)
 }
 
-func (vTouch vTouchPad) FetchSyspath() (string, error) {
-	return fetchSyspath(vTouch.deviceFile)
+func (vTouch *vTouchPad) FetchSyspath() (string, error) {
+	return lookupSyspath(vTouch.deviceFile.File, vTouch.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vTouch *vTouchPad) EventNode() (string, error) {
+	return lookupEventNode(vTouch.deviceFile.File, vTouch.name)
 }
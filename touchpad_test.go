@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestBasicTouchPadMoves(t *testing.T) {
@@ -282,3 +284,99 @@ func TestTouchPadSyspath(t *testing.T) {
 
 	t.Logf("Syspath: %s", sysPath)
 }
+
+func TestTouchPadWheelHiRes(t *testing.T) {
+	dev, err := CreateTouchPad("/dev/uinput", []byte("Test TouchPad"), 0, 1024, 0, 768)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch pad. Last error was: %s\n", err)
+	}
+	defer dev.Close()
+
+	err = dev.WheelHiRes(-60)
+	if err != nil {
+		t.Fatalf("Failed to scroll. Last error was: %s\n", err)
+	}
+}
+
+func TestCreateTouchPadFromFileFailsOnNilFile(t *testing.T) {
+	_, err := CreateTouchPadFromFile(nil, []byte("Test TouchPad"), 0, 1024, 0, 768)
+	if err == nil {
+		t.Fatalf("Expected touch pad creation to fail due to a missing device file, but got no error.")
+	}
+}
+
+func TestConcurrentTouchPadMovesDoNotInterleave(t *testing.T) {
+	fake := NewFake()
+	touchPad, err := fake.CreateTouchPad(0, 1024, 0, 768, WithEasing(EaseLinear))
+	if err != nil {
+		t.Fatalf("Failed to create fake touch pad: %v", err)
+	}
+	defer touchPad.Close()
+	if err := touchPad.MoveTo(500, 500); err != nil {
+		t.Fatalf("Failed to move cursor: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := int32(1); i <= 4; i++ {
+		wg.Add(1)
+		go func(i int32) {
+			defer wg.Done()
+			for j := int32(0); j < 20; j++ {
+				err := touchPad.MoveTo(i*100+j, i*100+j)
+				if err == nil {
+					err = touchPad.GlideTo(i*100, j, 2*motionInterval)
+				}
+				if err != nil {
+					t.Errorf("Failed to move cursor: %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	events := fake.Events()
+	if len(events)%3 != 0 {
+		t.Fatalf("Expected frames of three events, but got %d events", len(events))
+	}
+	for i := 0; i < len(events); i += 3 {
+		if events[i].Code != absX || events[i+1].Code != absY || events[i+2].Type != evSyn {
+			t.Fatalf("Expected a complete movement at position %d, but got %+v", i, events[i:i+3])
+		}
+	}
+}
+
+func TestConcurrentTouchPadClicksDoNotInterleave(t *testing.T) {
+	fake := NewFake()
+	touchPad, err := fake.CreateTouchPad(0, 1024, 0, 768)
+	if err != nil {
+		t.Fatalf("Failed to create fake touch pad: %v", err)
+	}
+	defer touchPad.Close()
+
+	var wg sync.WaitGroup
+	for _, click := range []func() error{touchPad.LeftClick, touchPad.RightClick} {
+		wg.Add(1)
+		go func(click func() error) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if err := click(); err != nil {
+					t.Errorf("Failed to click: %v", err)
+					return
+				}
+				time.Sleep(time.Microsecond)
+			}
+		}(click)
+	}
+	wg.Wait()
+
+	events := fake.Events()
+	if len(events) != 2*50*4 {
+		t.Fatalf("Expected %d events, but got %d", 2*50*4, len(events))
+	}
+	for i := 0; i < len(events); i += 4 {
+		if events[i].Code != events[i+2].Code || events[i].Value != btnStatePressed || events[i+2].Value != btnStateReleased {
+			t.Fatalf("Expected a complete click at position %d, but got %+v", i, events[i:i+4])
+		}
+	}
+}
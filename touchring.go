@@ -0,0 +1,177 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// A TouchRing is an input device that reports the absolute position of a ring (or strip), like the touch rings found on
+// many drawing tablets. Just like real tablet pads, the position is reported using the ABS_WHEEL axis.
+type TouchRing interface {
+	// SetRing will report the given absolute position of the ring.
+	SetRing(value int32) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
+	io.Closer
+}
+
+type vTouchRing struct {
+	name       []byte
+	deviceFile *device
+	min        int32
+	max        int32
+	onClose    closeHooks
+	mu         deviceMutex
+}
+
+// CreateTouchRing will create a new touch ring device. The range of positions the ring may report needs to be defined
+// upon creation (e.g. 0 to 71 for a ring with 72 positions).
+func CreateTouchRing(path string, name []byte, min int32, max int32, opts ...DeviceOption) (TouchRing, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return createVTouchRing(path, name, min, max, opts)
+}
+
+// CreateTouchRingFromFile will create a new touch ring just like CreateTouchRing, but uses the given uinput device
+// file, which has been opened by the caller already. The touch ring takes ownership of the file (see
+// CreateKeyboardFromFile).
+func CreateTouchRingFromFile(file *os.File, name []byte, min int32, max int32, opts ...DeviceOption) (TouchRing, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVTouchRing(file.Name(), name, min, max, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVTouchRing(path string, name []byte, min int32, max int32, opts []DeviceOption) (TouchRing, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+	if min >= max {
+		return nil, fmt.Errorf("invalid ring range. Minimum %d must be less than maximum %d", min, max)
+	}
+
+	fd, err := createTouchRing(path, name, min, max, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vTouchRing{name: name, deviceFile: fd, min: min, max: max, mu: newDeviceMutex(options)}, nil
+}
+
+// NewTouchRing is the same as CreateTouchRing, but takes the name as a string, which is truncated if it exceeds 80
+// bytes (see WithNameTruncation).
+func NewTouchRing(path string, name string, min int32, max int32, opts ...DeviceOption) (TouchRing, error) {
+	return CreateTouchRing(path, []byte(name), min, max, withStringName(opts)...)
+}
+
+// SetRing will report the given absolute position of the ring, which needs to be within the range defined upon creation.
+func (vr *vTouchRing) SetRing(value int32) error {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	if value < vr.min || value > vr.max {
+		return fmt.Errorf("ring position %d is out of range. Expected a value between %d and %d", value, vr.min, vr.max)
+	}
+
+	err := writeEvent(vr.deviceFile, inputEvent{
+		Type:  evAbs,
+		Code:  absWheel,
+		Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to write abs event to device file: %w", err)
+	}
+
+	return syncEvents(vr.deviceFile)
+}
+
+// FetchSyspath will return the syspath to the device file.
+func (vr *vTouchRing) FetchSyspath() (string, error) {
+	return lookupSyspath(vr.deviceFile.File, vr.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vr *vTouchRing) EventNode() (string, error) {
+	return lookupEventNode(vr.deviceFile.File, vr.name)
+}
+
+// Close closes the device and releases the device.
+func (vr *vTouchRing) Close() error {
+	err := closeDevice(vr.deviceFile)
+	vr.onClose.run()
+	return err
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vr *vTouchRing) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	return sendRawEvent(vr.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vr *vTouchRing) Sync() error {
+	vr.mu.Lock()
+	defer vr.mu.Unlock()
+	return syncEvents(vr.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vr *vTouchRing) OnClose(callback func()) {
+	vr.onClose.add(callback)
+}
+
+func createTouchRing(path string, name []byte, min int32, max int32, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not create touch ring input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register touch ring input device: %w", err)
+	}
+
+	err = ioctl(deviceFile, uiSetAbsBit, uintptr(absWheel))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register ring events: %w", err)
+	}
+
+	var absMin [absSize]int32
+	absMin[absWheel] = min
+
+	var absMax [absSize]int32
+	absMax[absWheel] = max
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     options.inputID(0x0819),
+			Absmin: absMin,
+			Absmax: absMax}, options)
+}
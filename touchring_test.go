@@ -0,0 +1,84 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestTouchRingPositions(t *testing.T) {
+	ring, err := CreateTouchRing("/dev/uinput", []byte("Test TouchRing"), 0, 71)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch ring. Last error was: %s\n", err)
+	}
+	defer ring.Close()
+
+	for _, value := range []int32{0, 36, 71} {
+		err = ring.SetRing(value)
+		if err != nil {
+			t.Fatalf("Failed to set ring position %d. Last error was: %s\n", value, err)
+		}
+	}
+}
+
+func TestTouchRingFailsOnValueOutOfRange(t *testing.T) {
+	ring := &vTouchRing{min: 0, max: 71}
+	for _, value := range []int32{-1, 72} {
+		err := ring.SetRing(value)
+		if err == nil {
+			t.Fatalf("Expected SetRing(%d) to fail due to a value out of range, but got no error.", value)
+		}
+	}
+}
+
+func TestTouchRingFailsIfDeviceIsClosed(t *testing.T) {
+	ring, err := CreateTouchRing("/dev/uinput", []byte("Test TouchRing"), 0, 71)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch ring. Last error was: %s\n", err)
+	}
+	_ = ring.Close()
+
+	err = ring.SetRing(1)
+	if err == nil {
+		t.Fatalf("Expected error due to closed device, but no error was returned.")
+	}
+}
+
+func TestTouchRingCreationFailsOnInvalidRange(t *testing.T) {
+	_, err := CreateTouchRing("/dev/uinput", []byte("TouchRingDevice"), 10, 10)
+	if err == nil {
+		t.Fatalf("Expected creation to fail due to an invalid range, but got no error.")
+	}
+}
+
+func TestTouchRingCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateTouchRing("", []byte("TouchRingDevice"), 0, 71)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchRingCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-touchring-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register touch ring input device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateTouchRing(file.Name(), []byte("TouchRingDevice"), 0, 71)
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchRingCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreateTouchRing("/dev/uinput", []byte(name), 0, 71)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
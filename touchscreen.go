@@ -0,0 +1,411 @@
+package uinput
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// A TouchScreen is a multi-touch input device that reports the position of several contacts (fingers) at once, using
+// the multi-touch protocol type B (see https://www.kernel.org/doc/Documentation/input/multi-touch-protocol.txt).
+// Each contact is assigned to a slot, which identifies the contact until it is lifted again.
+// The gestures of a touch screen are reported as plain touch events, which applications interpret on their own (e.g. a
+// pinch zooming into a web page). libinput only recognizes gestures on touch pads, so desktop gestures (e.g. swiping
+// between workspaces) need to be performed using a ClickPad instead.
+type TouchScreen interface {
+	// TouchDown will put a new contact in the given slot down at the given position.
+	TouchDown(slot int, x int32, y int32) error
+
+	// TouchMove will move the contact in the given slot to the given position.
+	TouchMove(slot int, x int32, y int32) error
+
+	// TouchUp will lift the contact in the given slot.
+	TouchUp(slot int) error
+
+	// TwoFingerScroll will perform a scroll gesture, moving two contacts by the given distance.
+	TwoFingerScroll(dx int32, dy int32) error
+
+	// Pinch will perform a pinch gesture, changing the distance between two contacts by the given factor.
+	Pinch(scale float64) error
+
+	// Swipe will perform a swipe gesture using the given number of contacts.
+	Swipe(fingers int, direction SwipeDirection) error
+
+	// Rotate will perform a rotation gesture, turning two contacts around their center by the given angle in degrees.
+	Rotate(degrees float64) error
+
+	// FetchSyspath will return the syspath to the device file.
+	FetchSyspath() (string, error)
+
+	// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+	EventNode() (string, error)
+
+	// SendRawEvent will send a single event of the given type and code to the device, in order to emit events that are
+	// not covered by the functions above. Call Sync in order to terminate a set of events.
+	SendRawEvent(evType uint16, code uint16, value int32) error
+
+	// Sync will terminate a set of events sent by SendRawEvent.
+	Sync() error
+
+	// OnClose registers a callback that is invoked when the device is closed.
+	OnClose(callback func())
+
+	io.Closer
+}
+
+// maxTrackingID is the highest tracking id assigned to a contact, before starting over at 0.
+const maxTrackingID = 0xffff
+
+type vTouchScreen struct {
+	name       []byte
+	deviceFile *device
+	onClose    closeHooks
+	mu         deviceMutex
+
+	minX  int32
+	maxX  int32
+	minY  int32
+	maxY  int32
+	slots int
+
+	// the slots that currently hold a contact
+	active         map[int]bool
+	nextTrackingID int32
+	// whether the number of contacts is reported using the BTN_TOOL_* events, which touch pads do (see ClickPad)
+	countContacts bool
+}
+
+// CreateTouchScreen will create a new multi-touch screen. Just like for the touch pad, the x and y-axis boundaries (min
+// and max) need to be defined upon creation, along with the number of slots, which is the maximum number of contacts
+// that may touch the screen at the same time.
+func CreateTouchScreen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (TouchScreen, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return createVTouchScreen(path, name, minX, maxX, minY, maxY, slots, opts)
+}
+
+// CreateTouchScreenFromFile will create a new touch screen just like CreateTouchScreen, but uses the given uinput
+// device file, which has been opened by the caller already. The touch screen takes ownership of the file (see
+// CreateKeyboardFromFile).
+func CreateTouchScreenFromFile(file *os.File, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (TouchScreen, error) {
+	err := validateDeviceFile(file)
+	if err != nil {
+		return nil, err
+	}
+	device, err := createVTouchScreen(file.Name(), name, minX, maxX, minY, maxY, slots, withDeviceFile(opts, file))
+	releaseDeviceFile(file, err)
+	return device, err
+}
+
+func createVTouchScreen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, opts []DeviceOption) (TouchScreen, error) {
+	options := newDeviceOptions(opts)
+	name, err := prepareUinputName(name, options)
+	if err != nil {
+		return nil, err
+	}
+	if minX >= maxX || minY >= maxY {
+		return nil, fmt.Errorf("invalid screen area. Minimum values must be less than maximum values")
+	}
+	if slots <= 0 {
+		return nil, fmt.Errorf("%d is not a valid number of slots. Expected a positive value", slots)
+	}
+
+	fd, err := createTouchScreen(path, name, minX, maxX, minY, maxY, slots, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vTouchScreen{name: name, deviceFile: fd, minX: minX, maxX: maxX, minY: minY, maxY: maxY, slots: slots, active: make(map[int]bool),
+		mu: newDeviceMutex(options)}, nil
+}
+
+// NewTouchScreen is the same as CreateTouchScreen, but takes the name as a string, which is truncated if it exceeds 80
+// bytes (see WithNameTruncation).
+func NewTouchScreen(path string, name string, minX int32, maxX int32, minY int32, maxY int32, slots int, opts ...DeviceOption) (TouchScreen, error) {
+	return CreateTouchScreen(path, []byte(name), minX, maxX, minY, maxY, slots, withStringName(opts)...)
+}
+
+// TouchDown will put a new contact down at the given position. The slot must not hold a contact already. The first
+// contact on the screen will also cause a BTN_TOUCH press to be reported.
+func (vs *vTouchScreen) TouchDown(slot int, x int32, y int32) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	f := vs.frame()
+	err := f.touchDown(slot, x, y)
+	if err != nil {
+		return err
+	}
+	return f.send()
+}
+
+// TouchMove will move the contact in the given slot to the given position.
+func (vs *vTouchScreen) TouchMove(slot int, x int32, y int32) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	f := vs.frame()
+	err := f.touchMove(slot, x, y)
+	if err != nil {
+		return err
+	}
+	return f.send()
+}
+
+// TouchUp will lift the contact in the given slot. Lifting the last contact on the screen will also cause a BTN_TOUCH
+// release to be reported.
+func (vs *vTouchScreen) TouchUp(slot int) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	f := vs.frame()
+	err := f.touchUp(slot)
+	if err != nil {
+		return err
+	}
+	return f.send()
+}
+
+// A touchFrame collects the events of one or more contact changes, which are sent within a single frame. The changes
+// are tracked by the frame and only applied to the contacts of the screen once the frame has been written, so that a
+// failed write leaves the screen in its previous state (e.g. a contact that failed to be put down is assigned a
+// tracking id again when it is put down the next time).
+type touchFrame struct {
+	vs             *vTouchScreen
+	events         []inputEvent
+	active         map[int]bool
+	nextTrackingID int32
+}
+
+// frame returns an empty frame, starting from the current contacts of the screen.
+func (vs *vTouchScreen) frame() *touchFrame {
+	active := make(map[int]bool, len(vs.active))
+	for slot := range vs.active {
+		active[slot] = true
+	}
+	return &touchFrame{vs: vs, active: active, nextTrackingID: vs.nextTrackingID}
+}
+
+// send writes the events of the frame followed by a sync and applies the changes of the frame to the contacts of the
+// screen, once the events have been written.
+func (f *touchFrame) send() error {
+	if f.vs.countContacts {
+		f.events = append(f.events, contactCountEvents(len(f.vs.active), len(f.active))...)
+	}
+	err := f.vs.sendEvents(f.events)
+	if err != nil {
+		return err
+	}
+	f.vs.active = f.active
+	f.vs.nextTrackingID = f.nextTrackingID
+	return nil
+}
+
+// touchDown adds the events that put a new contact down in the given slot to the frame.
+func (f *touchFrame) touchDown(slot int, x int32, y int32) error {
+	err := f.vs.validateSlot(slot)
+	if err != nil {
+		return fmt.Errorf("failed to perform TouchDown: %w", err)
+	}
+	if f.active[slot] {
+		return fmt.Errorf("failed to perform TouchDown. Slot %d already holds a contact", slot)
+	}
+	err = f.vs.validatePosition(x, y)
+	if err != nil {
+		return fmt.Errorf("failed to perform TouchDown: %w", err)
+	}
+
+	f.events = append(f.events,
+		inputEvent{Type: evAbs, Code: absMTSlot, Value: int32(slot)},
+		inputEvent{Type: evAbs, Code: absMTTrackingID, Value: f.nextTrackingID},
+		inputEvent{Type: evAbs, Code: absMTPositionX, Value: x},
+		inputEvent{Type: evAbs, Code: absMTPositionY, Value: y},
+	)
+	if len(f.active) == 0 {
+		f.events = append(f.events, inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStatePressed})
+	}
+	f.active[slot] = true
+	f.nextTrackingID = (f.nextTrackingID + 1) % (maxTrackingID + 1)
+	f.events = append(f.events, f.pointerEvents(slot, x, y)...)
+	return nil
+}
+
+// touchMove adds the events that move the contact in the given slot to the frame.
+func (f *touchFrame) touchMove(slot int, x int32, y int32) error {
+	err := f.vs.validateSlot(slot)
+	if err != nil {
+		return fmt.Errorf("failed to perform TouchMove: %w", err)
+	}
+	if !f.active[slot] {
+		return fmt.Errorf("failed to perform TouchMove. Slot %d does not hold a contact", slot)
+	}
+	err = f.vs.validatePosition(x, y)
+	if err != nil {
+		return fmt.Errorf("failed to perform TouchMove: %w", err)
+	}
+
+	f.events = append(f.events,
+		inputEvent{Type: evAbs, Code: absMTSlot, Value: int32(slot)},
+		inputEvent{Type: evAbs, Code: absMTPositionX, Value: x},
+		inputEvent{Type: evAbs, Code: absMTPositionY, Value: y},
+	)
+	f.events = append(f.events, f.pointerEvents(slot, x, y)...)
+	return nil
+}
+
+// touchUp adds the events that lift the contact in the given slot to the frame.
+func (f *touchFrame) touchUp(slot int) error {
+	err := f.vs.validateSlot(slot)
+	if err != nil {
+		return fmt.Errorf("failed to perform TouchUp: %w", err)
+	}
+	if !f.active[slot] {
+		return fmt.Errorf("failed to perform TouchUp. Slot %d does not hold a contact", slot)
+	}
+
+	f.events = append(f.events,
+		inputEvent{Type: evAbs, Code: absMTSlot, Value: int32(slot)},
+		inputEvent{Type: evAbs, Code: absMTTrackingID, Value: -1},
+	)
+	delete(f.active, slot)
+	if len(f.active) == 0 {
+		f.events = append(f.events, inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStateReleased})
+	}
+	return nil
+}
+
+// pointerEvents returns the single-touch ABS_X and ABS_Y events that emulate a pointer for consumers that do not
+// support multi-touch. The pointer follows the contact in the lowest active slot.
+func (f *touchFrame) pointerEvents(slot int, x int32, y int32) []inputEvent {
+	for s := 0; s < slot; s++ {
+		if f.active[s] {
+			return nil
+		}
+	}
+	return []inputEvent{
+		{Type: evAbs, Code: absX, Value: x},
+		{Type: evAbs, Code: absY, Value: y},
+	}
+}
+
+// FetchSyspath will return the syspath to the device file.
+func (vs *vTouchScreen) FetchSyspath() (string, error) {
+	return lookupSyspath(vs.deviceFile.File, vs.name)
+}
+
+// EventNode will return the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func (vs *vTouchScreen) EventNode() (string, error) {
+	return lookupEventNode(vs.deviceFile.File, vs.name)
+}
+
+// Close closes the device and releases the device.
+func (vs *vTouchScreen) Close() error {
+	err := closeDevice(vs.deviceFile)
+	vs.onClose.run()
+	return err
+}
+
+// SendRawEvent will send a single event of the given type and code to the device. Call Sync in order to terminate a
+// set of events.
+func (vs *vTouchScreen) SendRawEvent(evType uint16, code uint16, value int32) error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	return sendRawEvent(vs.deviceFile, evType, code, value)
+}
+
+// Sync will terminate a set of events sent by SendRawEvent.
+func (vs *vTouchScreen) Sync() error {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	return syncEvents(vs.deviceFile)
+}
+
+// OnClose registers a callback that is invoked by Close after the device has been closed. Callbacks are invoked in
+// reverse order of registration.
+func (vs *vTouchScreen) OnClose(callback func()) {
+	vs.onClose.add(callback)
+}
+
+func (vs *vTouchScreen) validateSlot(slot int) error {
+	if slot < 0 || slot >= vs.slots {
+		return fmt.Errorf("slot %d is out of range. Expected a value between 0 and %d", slot, vs.slots-1)
+	}
+	return nil
+}
+
+func (vs *vTouchScreen) validatePosition(x int32, y int32) error {
+	if x < vs.minX || x > vs.maxX || y < vs.minY || y > vs.maxY {
+		return fmt.Errorf("position (%d, %d) is outside of the screen area", x, y)
+	}
+	return nil
+}
+
+// sendEvents writes the events of a frame and terminates it with a SYN_REPORT. Since contacts are reported using slots
+// (protocol type B), the contacts within a frame are not separated by SYN_MT_REPORT, which only protocol type A uses.
+func (vs *vTouchScreen) sendEvents(events []inputEvent) error {
+	for _, ev := range events {
+		err := writeEvent(vs.deviceFile, ev)
+		if err != nil {
+			return fmt.Errorf("failed to write touch event to device file: %w", err)
+		}
+	}
+	return syncEvents(vs.deviceFile)
+}
+
+func createTouchScreen(path string, name []byte, minX int32, maxX int32, minY int32, maxY int32, slots int, options deviceOptions) (fd *device, err error) {
+	deviceFile, err := openDeviceFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not create touch screen input device: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evKey))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register key device: %w", err)
+	}
+	err = ioctl(deviceFile, uiSetKeyBit, uintptr(evBtnTouch))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register touch event: %w", err)
+	}
+
+	err = registerDevice(deviceFile, uintptr(evAbs))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register absolute axis input device: %w", err)
+	}
+	for _, event := range []int{absX, absY, absMTSlot, absMTTrackingID, absMTPositionX, absMTPositionY} {
+		err = ioctl(deviceFile, uiSetAbsBit, uintptr(event))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register absolute axis event %v: %w", event, err)
+		}
+	}
+
+	// mark the device as a touch screen (as opposed to a touch pad, which moves a cursor indirectly)
+	err = ioctl(deviceFile, uiSetPropBit, uintptr(inputPropDirect))
+	if err != nil {
+		_ = deviceFile.Close()
+		return nil, fmt.Errorf("failed to register direct input property: %w", err)
+	}
+
+	var absMin [absSize]int32
+	absMin[absX] = minX
+	absMin[absY] = minY
+	absMin[absMTPositionX] = minX
+	absMin[absMTPositionY] = minY
+
+	var absMax [absSize]int32
+	absMax[absX] = maxX
+	absMax[absY] = maxY
+	absMax[absMTPositionX] = maxX
+	absMax[absMTPositionY] = maxY
+	absMax[absMTSlot] = int32(slots - 1)
+	absMax[absMTTrackingID] = maxTrackingID
+
+	return createUsbDevice(deviceFile,
+		uinputUserDev{
+			Name:   toUinputName(name),
+			ID:     options.inputID(0x081a),
+			Absmin: absMin,
+			Absmax: absMax}, options)
+}
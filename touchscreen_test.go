@@ -0,0 +1,274 @@
+package uinput
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestTouchScreenMultiTouchGesture(t *testing.T) {
+	ts, err := CreateTouchScreen("/dev/uinput", []byte("Test TouchScreen"), 0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create the virtual touch screen. Last error was: %s\n", err)
+	}
+	defer ts.Close()
+
+	err = ts.TouchDown(0, 100, 100)
+	if err != nil {
+		t.Fatalf("Failed to put down first contact. Last error was: %s\n", err)
+	}
+	err = ts.TouchDown(1, 200, 200)
+	if err != nil {
+		t.Fatalf("Failed to put down second contact. Last error was: %s\n", err)
+	}
+	err = ts.TouchMove(1, 300, 300)
+	if err != nil {
+		t.Fatalf("Failed to move second contact. Last error was: %s\n", err)
+	}
+	for slot := 0; slot < 2; slot++ {
+		err = ts.TouchUp(slot)
+		if err != nil {
+			t.Fatalf("Failed to lift contact %d. Last error was: %s\n", slot, err)
+		}
+	}
+}
+
+func TestTouchScreenEmitsSlotProtocol(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vs := &vTouchScreen{deviceFile: &device{File: file}, maxX: 1024, maxY: 768, slots: 2, active: make(map[int]bool)}
+
+	if err := vs.TouchDown(0, 10, 20); err != nil {
+		t.Fatalf("Failed to put down first contact: %v", err)
+	}
+	if err := vs.TouchDown(1, 30, 40); err != nil {
+		t.Fatalf("Failed to put down second contact: %v", err)
+	}
+	if err := vs.TouchUp(0); err != nil {
+		t.Fatalf("Failed to lift first contact: %v", err)
+	}
+	if err := vs.TouchUp(1); err != nil {
+		t.Fatalf("Failed to lift second contact: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	expected := []inputEvent{
+		{Type: evAbs, Code: absMTSlot, Value: 0},
+		{Type: evAbs, Code: absMTTrackingID, Value: 0},
+		{Type: evAbs, Code: absMTPositionX, Value: 10},
+		{Type: evAbs, Code: absMTPositionY, Value: 20},
+		{Type: evKey, Code: evBtnTouch, Value: btnStatePressed},
+		{Type: evAbs, Code: absX, Value: 10},
+		{Type: evAbs, Code: absY, Value: 20},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absMTSlot, Value: 1},
+		{Type: evAbs, Code: absMTTrackingID, Value: 1},
+		{Type: evAbs, Code: absMTPositionX, Value: 30},
+		{Type: evAbs, Code: absMTPositionY, Value: 40},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absMTSlot, Value: 0},
+		{Type: evAbs, Code: absMTTrackingID, Value: -1},
+		{Type: evSyn, Code: synReport},
+		{Type: evAbs, Code: absMTSlot, Value: 1},
+		{Type: evAbs, Code: absMTTrackingID, Value: -1},
+		{Type: evKey, Code: evBtnTouch, Value: btnStateReleased},
+		{Type: evSyn, Code: synReport},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, but got %d: %+v", len(expected), len(events), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("Expected event %+v at position %d, but got %+v", expected[i], i, events[i])
+		}
+	}
+}
+
+// Multi-touch devices report their contacts using slots (protocol type B), so their frames are terminated by a single
+// SYN_REPORT and never separated by SYN_MT_REPORT, which only protocol type A uses.
+func TestMultiTouchDevicesOnlyEmitSynReport(t *testing.T) {
+	fake := NewFake()
+	ts, err := fake.CreateTouchScreen(0, 1024, 0, 768, 2)
+	if err != nil {
+		t.Fatalf("Failed to create fake touch screen: %v", err)
+	}
+	defer ts.Close()
+	cp, err := fake.CreateClickPad(0, 1024, 0, 768, 4)
+	if err != nil {
+		t.Fatalf("Failed to create fake click pad: %v", err)
+	}
+	defer cp.Close()
+
+	if err := ts.TouchDown(0, 100, 100); err != nil {
+		t.Fatalf("Failed to put down first contact: %v", err)
+	}
+	if err := ts.TouchDown(1, 200, 200); err != nil {
+		t.Fatalf("Failed to put down second contact: %v", err)
+	}
+	if err := ts.TouchUp(0); err != nil {
+		t.Fatalf("Failed to lift first contact: %v", err)
+	}
+	if err := ts.TouchUp(1); err != nil {
+		t.Fatalf("Failed to lift second contact: %v", err)
+	}
+	if err := cp.Swipe(3, SwipeLeft); err != nil {
+		t.Fatalf("Failed to swipe: %v", err)
+	}
+
+	syncs := 0
+	for _, ev := range fake.Events() {
+		if ev.Type != evSyn {
+			continue
+		}
+		if ev.Code != synReport {
+			t.Fatalf("Expected only SYN_REPORT sync events, but got code %d", ev.Code)
+		}
+		syncs++
+	}
+	if syncs == 0 {
+		t.Fatalf("Expected frames to be terminated by SYN_REPORT, but no sync events were sent")
+	}
+}
+
+func TestTouchScreenFailsOnInvalidSlotUsage(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vs := &vTouchScreen{deviceFile: &device{File: file}, maxX: 1024, maxY: 768, slots: 2, active: make(map[int]bool)}
+
+	if err := vs.TouchDown(2, 0, 0); err == nil {
+		t.Fatalf("Expected TouchDown to fail for a slot out of range, but got no error.")
+	}
+	if err := vs.TouchMove(0, 0, 0); err == nil {
+		t.Fatalf("Expected TouchMove to fail for a slot without contact, but got no error.")
+	}
+	if err := vs.TouchUp(0); err == nil {
+		t.Fatalf("Expected TouchUp to fail for a slot without contact, but got no error.")
+	}
+	if err := vs.TouchDown(0, 2000, 0); err == nil {
+		t.Fatalf("Expected TouchDown to fail for a position outside of the screen, but got no error.")
+	}
+	if err := vs.TouchDown(0, 0, 0); err != nil {
+		t.Fatalf("Failed to put down contact: %v", err)
+	}
+	if err := vs.TouchDown(0, 0, 0); err == nil {
+		t.Fatalf("Expected TouchDown to fail for a slot that already holds a contact, but got no error.")
+	}
+}
+
+func TestTouchScreenKeepsContactsOnFailedWrite(t *testing.T) {
+	closed := createTestEventFile(t)
+	_ = closed.Close()
+	vs := &vTouchScreen{deviceFile: &device{File: closed}, maxX: 1024, maxY: 768, slots: 2, active: make(map[int]bool)}
+
+	if err := vs.TouchDown(0, 10, 20); err == nil {
+		t.Fatalf("Expected TouchDown to fail due to a closed device, but got no error.")
+	}
+	if len(vs.active) != 0 || vs.nextTrackingID != 0 {
+		t.Fatalf("Expected the failed contact not to be tracked, but got %v (next tracking id %d)", vs.active, vs.nextTrackingID)
+	}
+
+	file := createTestEventFile(t)
+	defer file.Close()
+	vs.deviceFile = &device{File: file}
+	if err := vs.TouchDown(0, 10, 20); err != nil {
+		t.Fatalf("Failed to put down contact after a failed write: %v", err)
+	}
+	events := readTestEvents(t, file)
+	if len(events) < 5 || events[1] != (inputEvent{Type: evAbs, Code: absMTTrackingID, Value: 0}) ||
+		events[4] != (inputEvent{Type: evKey, Code: evBtnTouch, Value: btnStatePressed}) {
+		t.Fatalf("Expected the contact to be put down with a tracking id, but got %+v", events)
+	}
+}
+
+func TestTouchScreenCreationFailsOnInvalidSlots(t *testing.T) {
+	_, err := CreateTouchScreen("/dev/uinput", []byte("TouchScreenDevice"), 0, 1024, 0, 768, 0)
+	if err == nil {
+		t.Fatalf("Expected creation to fail due to an invalid number of slots, but got no error.")
+	}
+}
+
+func TestTouchScreenCreationFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := CreateTouchScreen("", []byte("TouchScreenDevice"), 0, 1024, 0, 768, 2)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchScreenCreationFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-touchscreen-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer file.Close()
+
+	expected := "failed to register key device: failed to close device: inappropriate ioctl for device"
+	_, err = CreateTouchScreen(file.Name(), []byte("TouchScreenDevice"), 0, 1024, 0, 768, 2)
+	if err == nil || !(expected == err.Error()) {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestTouchScreenCreationFailsIfNameIsTooLong(t *testing.T) {
+	name := "adsfdsferqewoirueworiuejdsfjdfa;ljoewrjeworiewuoruew;rj;kdlfjoeai;jfewoaifjef;das"
+	expected := fmt.Sprintf("device name %s is too long (maximum of %d characters allowed)", name, uinputMaxNameSize)
+	_, err := CreateTouchScreen("/dev/uinput", []byte(name), 0, 1024, 0, 768, 2)
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestConcurrentTouchScreenContactsDoNotInterleave(t *testing.T) {
+	fake := NewFake()
+	ts, err := fake.CreateTouchScreen(0, 1024, 0, 768, 4)
+	if err != nil {
+		t.Fatalf("Failed to create fake touch screen: %v", err)
+	}
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	for slot := 0; slot < 4; slot++ {
+		wg.Add(1)
+		go func(slot int) {
+			defer wg.Done()
+			for i := int32(0); i < 20; i++ {
+				err := ts.TouchDown(slot, i, i)
+				if err == nil {
+					err = ts.TouchMove(slot, i+1, i+1)
+				}
+				if err == nil {
+					err = ts.TouchUp(slot)
+				}
+				if err != nil {
+					t.Errorf("Failed to touch screen: %v", err)
+					return
+				}
+			}
+		}(slot)
+	}
+	wg.Wait()
+
+	// each frame changes a single contact, so it starts with selecting the slot of the contact
+	frameStart := true
+	trackingIDs := make(map[int32]bool)
+	for i, ev := range fake.Events() {
+		if frameStart && (ev.Type != evAbs || ev.Code != absMTSlot) {
+			t.Fatalf("Expected event %d to select a slot, but got %+v", i, ev)
+		}
+		if ev.Code == absMTSlot && !frameStart {
+			t.Fatalf("Expected a single contact to be changed per frame, but event %d selects another slot", i)
+		}
+		if ev.Type == evAbs && ev.Code == absMTTrackingID && ev.Value >= 0 {
+			if trackingIDs[ev.Value] {
+				t.Fatalf("Expected unique tracking ids, but %d was assigned twice", ev.Value)
+			}
+			trackingIDs[ev.Value] = true
+		}
+		frameStart = ev.Type == evSyn
+	}
+	if len(trackingIDs) != 4*20 {
+		t.Fatalf("Expected %d contacts to be put down, but got %d", 4*20, len(trackingIDs))
+	}
+}
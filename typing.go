@@ -0,0 +1,204 @@
+package uinput
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// A TypingOption configures how TypeHuman types text.
+type TypingOption func(*typingOptions)
+
+type typingOptions struct {
+	delay    func() time.Duration
+	hold     func() time.Duration
+	typoRate float64
+	rng      *rand.Rand
+}
+
+// Default timing of TypeHuman, which roughly matches an average typist.
+const (
+	defaultTypingDelay       = 120 * time.Millisecond
+	defaultTypingDelayStdDev = 40 * time.Millisecond
+	defaultHoldDuration      = 80 * time.Millisecond
+	defaultHoldStdDev        = 20 * time.Millisecond
+	defaultTypoRate          = 0.02
+	// typoNoticeDelay is the additional pause before a typo is corrected, since it takes a moment to notice.
+	typoNoticeDelay = 200 * time.Millisecond
+)
+
+// WithTypingDelay sets the delay between two key presses, which is normally distributed using the given mean and
+// standard deviation (120ms and 40ms by default).
+func WithTypingDelay(mean time.Duration, stdDev time.Duration) TypingOption {
+	return func(o *typingOptions) {
+		o.delay = o.normal(mean, stdDev)
+	}
+}
+
+// WithTypingDelayFunc sets a function that returns the delay between two key presses, which allows to use any
+// distribution of delays (e.g. one measured from a real typist).
+func WithTypingDelayFunc(delay func() time.Duration) TypingOption {
+	return func(o *typingOptions) {
+		o.delay = delay
+	}
+}
+
+// WithHoldDuration sets the time a key is held down, which is normally distributed using the given mean and standard
+// deviation (80ms and 20ms by default).
+func WithHoldDuration(mean time.Duration, stdDev time.Duration) TypingOption {
+	return func(o *typingOptions) {
+		o.hold = o.normal(mean, stdDev)
+	}
+}
+
+// WithTypoRate sets the probability of a typo per character, between 0 and 1 (0.02 by default). A typo presses a
+// neighboring key instead, which is corrected using backspace right away, so that the typed text is still correct.
+func WithTypoRate(rate float64) TypingOption {
+	return func(o *typingOptions) {
+		o.typoRate = rate
+	}
+}
+
+// WithTypingSeed sets the seed of the random numbers used by TypeHuman, which makes the timing and typos reproducible.
+func WithTypingSeed(seed int64) TypingOption {
+	return func(o *typingOptions) {
+		o.rng.Seed(seed)
+	}
+}
+
+func newTypingOptions(opts []TypingOption) typingOptions {
+	o := typingOptions{typoRate: defaultTypoRate, rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	o.delay = o.normal(defaultTypingDelay, defaultTypingDelayStdDev)
+	o.hold = o.normal(defaultHoldDuration, defaultHoldStdDev)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// normal returns a function drawing durations from a normal distribution, which are cut off at zero.
+func (o *typingOptions) normal(mean time.Duration, stdDev time.Duration) func() time.Duration {
+	rng := o.rng
+	return func() time.Duration {
+		d := mean + time.Duration(rng.NormFloat64()*float64(stdDev))
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+}
+
+// typoNeighbors maps keys to the keys next to them on the same row of the keyboard, which are mistakenly hit by a typo.
+var typoNeighbors = func() map[int][]int {
+	m := make(map[int][]int)
+	for _, row := range usRows {
+		for i, key := range row.keys {
+			if i > 0 {
+				m[key] = append(m[key], row.keys[i-1])
+			}
+			if i < len(row.keys)-1 {
+				m[key] = append(m[key], row.keys[i+1])
+			}
+		}
+	}
+	return m
+}()
+
+// TypeHuman will type the given text like a human would, using the keyboard layout configured with WithLayout. The
+// delay between key presses and the time each key is held down vary randomly, and occasionally a neighboring key is hit
+// and corrected using backspace. This avoids the robotic timing of Type, which may skew the results of UX tests or
+// load simulations. The timing and typos may be configured using TypingOptions.
+func (vk *vKeyboard) TypeHuman(text string, opts ...TypingOption) error {
+	layout := vk.options.layout
+	if layout == nil {
+		return fmt.Errorf("failed to perform TypeHuman. The keyboard layout must not be nil")
+	}
+	o := newTypingOptions(opts)
+	if o.typoRate < 0 || o.typoRate > 1 {
+		return fmt.Errorf("failed to perform TypeHuman. Typo rate %v is not between 0 and 1", o.typoRate)
+	}
+
+	var chars [][]shortcut
+	for _, r := range text {
+		s, err := vk.runeShortcuts("TypeHuman", layout, r)
+		if err != nil {
+			return err
+		}
+		chars = append(chars, s)
+	}
+
+	for i, shortcuts := range chars {
+		if i > 0 {
+			time.Sleep(o.delay())
+		}
+		if len(shortcuts) == 1 && len(shortcuts[0].modifiers) == 0 && o.rng.Float64() < o.typoRate {
+			err := vk.typeTypo(shortcuts[0].key, &o)
+			if err != nil {
+				return err
+			}
+		}
+		for j, sc := range shortcuts {
+			if j > 0 {
+				time.Sleep(o.delay())
+			}
+			err := vk.holdShortcut(sc, o.hold())
+			if err != nil {
+				return fmt.Errorf("failed to type key %d: %w", sc.key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// typeTypo hits a key next to the given one and corrects it using backspace. Keys without neighbors are not mistyped.
+func (vk *vKeyboard) typeTypo(key int, o *typingOptions) error {
+	neighbors := typoNeighbors[key]
+	if len(neighbors) == 0 {
+		return nil
+	}
+	wrong := neighbors[o.rng.Intn(len(neighbors))]
+	err := vk.holdShortcut(shortcut{key: wrong}, o.hold())
+	if err != nil {
+		return fmt.Errorf("failed to type key %d: %w", wrong, err)
+	}
+	time.Sleep(o.delay() + typoNoticeDelay)
+	err = vk.holdShortcut(shortcut{key: KeyBackspace}, o.hold())
+	if err != nil {
+		return fmt.Errorf("failed to correct typo: %w", err)
+	}
+	time.Sleep(o.delay())
+	return nil
+}
+
+// holdShortcut presses the modifiers and the key of the shortcut, holds the key down for the given duration and
+// releases all keys in reverse order. Keys are released even if sending an event fails. The device is locked until all
+// keys have been released, while the delays between the shortcuts leave room for concurrent calls.
+func (vk *vKeyboard) holdShortcut(sc shortcut, hold time.Duration) error {
+	vk.mu.Lock()
+	defer vk.mu.Unlock()
+	for i, modifier := range sc.modifiers {
+		err := vk.keyDown(modifier)
+		if err != nil {
+			vk.releaseModifiers(sc.modifiers[:i])
+			return fmt.Errorf("failed to press modifier key %d: %w", modifier, err)
+		}
+	}
+	err := vk.keyDown(sc.key)
+	if err != nil {
+		vk.releaseModifiers(sc.modifiers)
+		return err
+	}
+	time.Sleep(hold)
+	err = vk.keyUp(sc.key)
+	if err != nil {
+		vk.releaseModifiers(sc.modifiers)
+		return err
+	}
+	for i := len(sc.modifiers) - 1; i >= 0; i-- {
+		err = vk.keyUp(sc.modifiers[i])
+		if err != nil {
+			return fmt.Errorf("failed to release modifier key %d: %w", sc.modifiers[i], err)
+		}
+	}
+	return nil
+}
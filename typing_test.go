@@ -0,0 +1,97 @@
+package uinput
+
+import (
+	"testing"
+	"time"
+)
+
+// typedText replays the key presses of the given events on the US layout, applying backspaces.
+func typedText(t *testing.T, events []inputEvent) string {
+	chars := make(map[int]rune)
+	for _, row := range usRows {
+		for i, key := range row.keys {
+			chars[key] = []rune(row.plain)[i]
+		}
+	}
+	var text []rune
+	for _, ev := range events {
+		if ev.Type != evKey || ev.Value != btnStatePressed {
+			continue
+		}
+		if ev.Code == KeyBackspace {
+			text = text[:len(text)-1]
+			continue
+		}
+		r, ok := chars[int(ev.Code)]
+		if !ok {
+			t.Fatalf("Unexpected key press %d", ev.Code)
+		}
+		text = append(text, r)
+	}
+	return string(text)
+}
+
+func TestTypeHumanCorrectsTypos(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.TypeHuman("hello", WithTypingSeed(1), WithTypoRate(1), WithTypingDelay(0, 0), WithHoldDuration(0, 0))
+	if err != nil {
+		t.Fatalf("Failed to type text: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	backspaces := 0
+	for _, ev := range events {
+		if ev.Code == KeyBackspace && ev.Value == btnStatePressed {
+			backspaces++
+		}
+	}
+	if backspaces != 5 {
+		t.Fatalf("Expected a corrected typo for each character, but got %d backspaces", backspaces)
+	}
+	if text := typedText(t, events); text != "hello" {
+		t.Fatalf("Expected the typed text to be corrected to %q, but got %q", "hello", text)
+	}
+}
+
+func TestTypeHumanHoldsKeys(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	hold := 5 * time.Millisecond
+	start := time.Now()
+	err := vk.TypeHuman("abc", WithTypoRate(0), WithTypingDelay(0, 0), WithHoldDuration(hold, 0))
+	if err != nil {
+		t.Fatalf("Failed to type text: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 3*hold {
+		t.Fatalf("Expected each key to be held for %v, but typing took %v", hold, elapsed)
+	}
+	if text := typedText(t, readTestEvents(t, file)); text != "abc" {
+		t.Fatalf("Expected %q to be typed, but got %q", "abc", text)
+	}
+}
+
+func TestTypingDelaysAreReproducible(t *testing.T) {
+	first := newTypingOptions([]TypingOption{WithTypingSeed(42)})
+	second := newTypingOptions([]TypingOption{WithTypingSeed(42)})
+	for i := 0; i < 10; i++ {
+		a, b := first.delay(), second.delay()
+		if a != b {
+			t.Fatalf("Expected the same delays for the same seed, but got %v and %v", a, b)
+		}
+		if a < 0 {
+			t.Fatalf("Expected delays not to be negative, but got %v", a)
+		}
+	}
+}
+
+func TestTypeHumanFailsOnInvalidTypoRate(t *testing.T) {
+	vk := &vKeyboard{options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+	if err := vk.TypeHuman("a", WithTypoRate(2)); err == nil {
+		t.Fatalf("Expected TypeHuman to fail due to an invalid typo rate, but got no error.")
+	}
+}
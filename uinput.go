@@ -73,28 +73,91 @@ device. Here are a few examples of how to use the virtual touch pad:
 	3. Close the device
 		Example: err = vt.Close()
 
+Devices that send events at a high rate (like a gamepad emulated at 1 kHz) should use an EventBatch instead of the
+functions above, which send each event using a write of its own. A batch that is reused for every frame keeps its
+buffer, so that it only allocates until it has grown to the size of the largest frame (see EventBatch.Grow):
+
+	batch := gamepad.Batch().Grow(3)
+	for frame := range frames {
+		err = batch.Event(0x03, uinput.AbsX, frame.X).Event(0x03, uinput.AbsY, frame.Y).Flush() // 0x03 is EV_ABS
+	}
+
+Several batches of the same device may be sent using a single vectored write (see FlushBatches). The benchmarks of
+the package measure the events per second of these paths (go test -bench .).
+
 */
 package uinput
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 )
 
+// openDevices keeps track of the number of devices that have been created, but not yet closed.
+var openDevices int64
+
+// OpenDeviceCount returns the number of virtual devices that have been created by this package and are not yet closed.
+// This may be used to detect devices that were never closed (e.g. in the teardown of tests).
+func OpenDeviceCount() int {
+	return int(atomic.LoadInt64(&openDevices))
+}
+
 func validateDevicePath(path string) error {
+	err := checkPlatform()
+	if err != nil {
+		return err
+	}
 	if path == "" {
 		return errors.New("device path must not be empty")
 	}
-	_, err := os.Stat(path)
+	_, err = os.Stat(path)
 	return err
 }
 
+// validateDeviceFile checks a device file that has been opened by the caller, which replaces the device path.
+func validateDeviceFile(file *os.File) error {
+	err := checkPlatform()
+	if err != nil {
+		return err
+	}
+	if file == nil {
+		return errors.New("device file must not be nil")
+	}
+	return nil
+}
+
+// releaseDeviceFile closes the device file passed by the caller if creating the device failed. The device takes
+// ownership of the file, so the file has to be closed on every failure, including those that occur before the file is
+// used (e.g. an invalid name). Closing a file that the device creation has closed already has no effect.
+func releaseDeviceFile(file *os.File, err error) {
+	if err != nil {
+		_ = file.Close()
+	}
+}
+
+// withDeviceFile makes the device use the given device file instead of opening the device path. The option is
+// appended to the options of the caller, so that it cannot be overridden.
+func withDeviceFile(opts []DeviceOption, file *os.File) []DeviceOption {
+	return append(append([]DeviceOption{}, opts...), func(o *deviceOptions) {
+		o.deviceFile = file
+	})
+}
+
 func validateUinputName(name []byte) error {
 	if name == nil || len(name) == 0 {
 		return errors.New("device name may not be empty")
@@ -105,6 +168,131 @@ func validateUinputName(name []byte) error {
 	return nil
 }
 
+// prepareUinputName validates the given device name, after truncating it if name truncation is enabled (see
+// WithNameTruncation). All devices prepare their name this way, which makes it the place to check for name collisions
+// (see WithNameCollisionCheck).
+func prepareUinputName(name []byte, options deviceOptions) ([]byte, error) {
+	if options.truncateName {
+		name = truncateUinputName(name)
+	}
+	err := validateUinputName(name)
+	if err != nil {
+		return name, err
+	}
+	if options.checkName {
+		err = validateUniqueName(name)
+	}
+	return name, err
+}
+
+// withStringName prepends the options that apply to constructors taking the device name as a string, so that they may
+// still be overridden by the given options.
+func withStringName(opts []DeviceOption) []DeviceOption {
+	return append([]DeviceOption{WithNameTruncation(true)}, opts...)
+}
+
+// truncateUinputName shortens the given name to the maximum name length, without cutting a UTF-8 encoded character in
+// half.
+func truncateUinputName(name []byte) []byte {
+	if len(name) <= uinputMaxNameSize {
+		return name
+	}
+	n := uinputMaxNameSize
+	for n > uinputMaxNameSize-utf8.UTFMax+1 && !utf8.RuneStart(name[n]) {
+		n--
+	}
+	if !utf8.RuneStart(name[n]) {
+		// not valid UTF-8 anyway
+		n = uinputMaxNameSize
+	}
+	return name[:n]
+}
+
+// ErrUnsupportedPlatform is returned upon device creation on any platform other than linux, since uinput is a linux
+// specific interface. The package still compiles on other platforms, so that it can be part of cross-platform programs.
+var ErrUnsupportedPlatform = errors.New("uinput is only supported on linux")
+
+// ErrNameCollision is returned upon device creation if the name collision check is enabled (see WithNameCollisionCheck)
+// and an input device with the same name already exists.
+var ErrNameCollision = errors.New("an input device with the same name already exists")
+
+// ErrUinputMissing is returned upon device creation if the uinput device file does not exist, which usually means that
+// the uinput kernel module is not loaded (see modprobe uinput). It is the same as os.ErrNotExist, since it originates
+// from accessing the device file.
+var ErrUinputMissing = os.ErrNotExist
+
+// ErrPermission is returned upon device creation if the uinput device file may not be opened by the current user,
+// which usually requires root or membership in a group that has access to the file (like the input group). It is the
+// same as os.ErrPermission, since it originates from opening the device file.
+var ErrPermission = os.ErrPermission
+
+// ErrKeyOutOfRange is returned if a key code is not within the range of valid key codes.
+var ErrKeyOutOfRange = errors.New("key code is out of range")
+
+// ErrDeviceClosed is returned if a device is used after it has been closed. It is the same as os.ErrClosed, since it
+// originates from the closed device file.
+var ErrDeviceClosed = os.ErrClosed
+
+// A sentinelError is an error with a message of its own, which matches a sentinel error (like ErrKeyOutOfRange) using
+// errors.Is, while still wrapping the error that caused it (if any). This allows to keep the existing error messages.
+type sentinelError struct {
+	msg      string
+	sentinel error
+	cause    error
+}
+
+func sentinelErrorf(sentinel error, format string, args ...interface{}) error {
+	return &sentinelError{msg: fmt.Sprintf(format, args...), sentinel: sentinel}
+}
+
+func (e *sentinelError) Error() string {
+	return e.msg
+}
+
+func (e *sentinelError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e *sentinelError) Unwrap() error {
+	return e.cause
+}
+
+// inputDevicesPath is the list of input devices checked by validateUniqueName, which is a variable for testing.
+var inputDevicesPath = "/proc/bus/input/devices"
+
+func validateUniqueName(name []byte) error {
+	devices, err := os.Open(inputDevicesPath)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing input devices: %w", err)
+	}
+	defer devices.Close()
+
+	exists, err := inputDeviceExists(devices, string(name))
+	if err != nil {
+		return fmt.Errorf("failed to check for existing input devices: %w", err)
+	}
+	if exists {
+		return ErrNameCollision
+	}
+	return nil
+}
+
+// inputDeviceExists checks whether the device list (in the format of /proc/bus/input/devices) contains a device
+// with the given name.
+func inputDeviceExists(devices io.Reader, name string) (bool, error) {
+	scanner := bufio.NewScanner(devices)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "N: Name=") {
+			continue
+		}
+		if strings.Trim(strings.TrimPrefix(line, "N: Name="), "\"") == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
 func toUinputName(name []byte) (uinputName [uinputMaxNameSize]byte) {
 	var fixedSizeName [uinputMaxNameSize]byte
 	copy(fixedSizeName[:], name)
@@ -112,55 +300,246 @@ func toUinputName(name []byte) (uinputName [uinputMaxNameSize]byte) {
 }
 
 func createDeviceFile(path string) (fd *os.File, err error) {
-	deviceFile, err := os.OpenFile(path, syscall.O_WRONLY|syscall.O_NONBLOCK, 0660)
+	deviceFile, err := os.OpenFile(path, syscall.O_RDWR|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0660)
 	if err != nil {
-		return nil, errors.New("could not open device file")
+		return nil, &sentinelError{msg: "could not open device file", cause: err}
 	}
 	return deviceFile, err
 }
 
+// openDeviceFile opens the device file just like createDeviceFile, but applies the file related device options. If
+// the caller passed a device file of its own (see CreateKeyboardFromFile), it is used instead.
+func openDeviceFile(path string, options deviceOptions) (fd *os.File, err error) {
+	deviceFile := options.deviceFile
+	if deviceFile == nil {
+		deviceFile, err = createDeviceFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !options.closeOnExec {
+		err = setCloseOnExec(deviceFile, false)
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, err
+		}
+	}
+	return deviceFile, nil
+}
+
+// closeHooks holds the callbacks registered using OnClose.
+type closeHooks []func()
+
+func (h *closeHooks) add(callback func()) {
+	*h = append(*h, callback)
+}
+
+// run invokes all callbacks in reverse order of registration and removes them. A panic raised by a callback is
+// recovered, so that it does not prevent the remaining callbacks from running.
+func (h *closeHooks) run() {
+	callbacks := *h
+	*h = nil
+	for i := len(callbacks) - 1; i >= 0; i-- {
+		runCloseHook(callbacks[i])
+	}
+}
+
+func runCloseHook(callback func()) {
+	defer func() {
+		_ = recover()
+	}()
+	callback()
+}
+
 func registerDevice(deviceFile *os.File, evType uintptr) error {
 	err := ioctl(deviceFile, uiSetEvBit, evType)
 	if err != nil {
 		defer deviceFile.Close()
-		err = releaseDevice(deviceFile)
-		if err != nil {
-			return fmt.Errorf("failed to close device: %v", err)
+		releaseErr := releaseDevice(deviceFile)
+		if releaseErr != nil {
+			return fmt.Errorf("failed to close device: %w", releaseErr)
 		}
-		return fmt.Errorf("invalid file handle returned from ioctl: %v", err)
+		return fmt.Errorf("invalid file handle returned from ioctl: %w", err)
 	}
 	return nil
 }
 
-func createUsbDevice(deviceFile *os.File, dev uinputUserDev) (fd *os.File, err error) {
-	buf := new(bytes.Buffer)
-	err = binary.Write(buf, binary.LittleEndian, dev)
+func createUsbDevice(deviceFile *os.File, dev uinputUserDev, options deviceOptions) (fd *device, err error) {
+	return createUsbDeviceWithResolution(deviceFile, dev, [absSize]int32{}, options)
+}
+
+// createUsbDeviceWithResolution creates the device described by dev, reporting the given resolution for its absolute
+// axes. The device is set up using UI_DEV_SETUP and UI_ABS_SETUP, which are available since kernel 4.5. On older
+// kernels, the device is set up by writing dev to the device file, which does not support a resolution.
+func createUsbDeviceWithResolution(deviceFile *os.File, dev uinputUserDev, absRes [absSize]int32, options deviceOptions) (fd *device, err error) {
+	for _, prop := range options.properties {
+		err = ioctl(deviceFile, uiSetPropBit, uintptr(prop))
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, fmt.Errorf("failed to register property %d: %w", prop, err)
+		}
+	}
+
+	if options.phys != "" {
+		err = setPhys(deviceFile, options.phys)
+		if err != nil {
+			_ = deviceFile.Close()
+			return nil, err
+		}
+	}
+
+	err = applyAxisOptions(&dev, &absRes, options)
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to write user device buffer: %v", err)
+		return nil, err
 	}
-	err = ioctl(deviceFile, uiDevSetup, uintptr(unsafe.Pointer(buf)))
+
+	err = setupDevice(deviceFile, dev, absRes)
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to write uidev struct to device file: %v", err)
+		return nil, err
 	}
 
 	err = ioctl(deviceFile, uiDevCreate, uintptr(0))
 	if err != nil {
 		_ = deviceFile.Close()
-		return nil, fmt.Errorf("failed to create device: %v", err)
+		return nil, fmt.Errorf("failed to create device: %w", err)
 	}
 
-	time.Sleep(time.Millisecond * 200)
+	atomic.AddInt64(&openDevices, 1)
+	fd = newDevice(deviceFile, options)
+	if options.readyTimeout <= 0 {
+		time.Sleep(time.Millisecond * 200)
+		return fd, err
+	}
 
-	return deviceFile, err
+	err = waitUntilReady(deviceFile, bytes.TrimRight(dev.Name[:], "\x00"), options.readyTimeout)
+	if err != nil {
+		_ = closeDevice(fd)
+		return nil, err
+	}
+	return fd, nil
+}
+
+// readyPollInterval is the interval in which waitUntilReady checks whether a device is ready.
+const readyPollInterval = 10 * time.Millisecond
+
+// waitUntilReady waits until the event node of a newly created device has appeared and can be opened, which means that
+// udev has finished setting up the device. Otherwise, consumers might miss events sent right after device creation.
+func waitUntilReady(deviceFile *os.File, name []byte, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := eventNodeReady(deviceFile, name)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("device was not ready within %v: %w", timeout, err)
+		}
+		time.Sleep(readyPollInterval)
+	}
+}
+
+func eventNodeReady(deviceFile *os.File, name []byte) error {
+	node, err := lookupEventNode(deviceFile, name)
+	if err != nil {
+		return err
+	}
+	eventFile, err := os.OpenFile(node, syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open event node: %w", err)
+	}
+	return eventFile.Close()
+}
+
+// setPhys sets the physical path of the device, which the kernel copies from the given NUL-terminated string.
+func setPhys(deviceFile *os.File, phys string) error {
+	buf := append([]byte(phys), 0)
+	err := ioctl(deviceFile, uiSetPhys, uintptr(unsafe.Pointer(&buf[0])))
+	if err != nil {
+		return fmt.Errorf("failed to set physical path: %w", err)
+	}
+	return nil
 }
 
-func closeDevice(deviceFile *os.File) (err error) {
-	err = releaseDevice(deviceFile)
+// applyAxisOptions applies the axis settings made using WithAxis to the absolute axes of the device.
+func applyAxisOptions(dev *uinputUserDev, absRes *[absSize]int32, options deviceOptions) error {
+	for axis, r := range options.axes {
+		if int(axis) >= absSize {
+			return fmt.Errorf("absolute axis %d is out of range", axis)
+		}
+		if r.Min != 0 || r.Max != 0 {
+			if r.Min >= r.Max {
+				return fmt.Errorf("invalid range of absolute axis %d. Minimum %d must be less than maximum %d", axis, r.Min, r.Max)
+			}
+			dev.Absmin[axis] = r.Min
+			dev.Absmax[axis] = r.Max
+		}
+		dev.Absfuzz[axis] = r.Fuzz
+		dev.Absflat[axis] = r.Flat
+		absRes[axis] = r.Resolution
+	}
+	return nil
+}
+
+func setupDevice(deviceFile *os.File, dev uinputUserDev, absRes [absSize]int32) error {
+	version, err := interfaceVersion(deviceFile)
+	if err != nil || version < UinputVersionDevSetup {
+		// fall back to the legacy setup, which is the only option for kernels prior to 4.5
+		return writeUserDev(deviceFile, dev)
+	}
+
+	setup := uinputSetup{ID: dev.ID, Name: dev.Name, FFEffectsMax: dev.EffectsMax}
+	err = ioctl(deviceFile, uiDevSetup, uintptr(unsafe.Pointer(&setup)))
+	if err != nil {
+		return fmt.Errorf("failed to set up device: %w", err)
+	}
+
+	for axis := 0; axis < absSize; axis++ {
+		info := inputAbsinfo{
+			Minimum:    dev.Absmin[axis],
+			Maximum:    dev.Absmax[axis],
+			Fuzz:       dev.Absfuzz[axis],
+			Flat:       dev.Absflat[axis],
+			Resolution: absRes[axis],
+		}
+		if info == (inputAbsinfo{}) {
+			continue
+		}
+		absSetup := uinputAbsSetup{Code: uint16(axis), Absinfo: info}
+		err = ioctl(deviceFile, uiAbsSetup, uintptr(unsafe.Pointer(&absSetup)))
+		if err != nil {
+			return fmt.Errorf("failed to set up absolute axis %d: %w", axis, err)
+		}
+	}
+	return nil
+}
+
+func writeUserDev(deviceFile *os.File, dev uinputUserDev) (err error) {
+	buf := new(bytes.Buffer)
+	err = binary.Write(buf, nativeEndian, dev)
+	if err != nil {
+		return fmt.Errorf("failed to write user device buffer: %w", err)
+	}
+	_, err = deviceFile.Write(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write uidev struct to device file: %w", err)
+	}
+	return nil
+}
+
+// closeDevice flushes and destroys the device before closing the device file. Fake devices are closed right away, since
+// there is nothing to destroy.
+func closeDevice(deviceFile *device) (err error) {
+	if deviceFile.fake != nil {
+		return deviceFile.fake.Close()
+	}
+	flushDevice(deviceFile)
+	err = releaseDevice(deviceFile.File)
 	if err != nil {
-		return fmt.Errorf("failed to close device: %v", err)
+		return fmt.Errorf("failed to close device: %w", err)
 	}
+	atomic.AddInt64(&openDevices, -1)
 	return deviceFile.Close()
 }
 
@@ -168,63 +547,230 @@ func releaseDevice(deviceFile *os.File) (err error) {
 	return ioctl(deviceFile, uiDevDestroy, uintptr(0))
 }
 
+// sysVirtualInputDir is the sysfs directory holding all virtual input devices, including the ones created by uinput.
+const sysVirtualInputDir = "/sys/devices/virtual/input/"
+
 func fetchSyspath(deviceFile *os.File) (string, error) {
-	sysInputDir := "/sys/devices/virtual/input/"
+	sysInputDir := sysVirtualInputDir
 	// 64 for name + 1 for null byte
 	path := make([]byte, 65)
 	err := ioctl(deviceFile, uiGetSysname, uintptr(unsafe.Pointer(&path[0])))
 
-	sysInputDir = sysInputDir + string(path)
+	sysInputDir = sysInputDir + string(bytes.TrimRight(path, "\x00"))
 	return sysInputDir, err
 }
 
+// lookupSyspath returns the syspath of the device. If UI_GET_SYSNAME is not supported (kernels prior to 3.15), the
+// virtual input devices in sysfs are searched for a device with the given name instead. In case several devices share
+// the name, the most recently created one is returned.
+func lookupSyspath(deviceFile *os.File, name []byte) (string, error) {
+	sysPath, err := fetchSyspath(deviceFile)
+	if err == nil {
+		return sysPath, nil
+	}
+	sysPath, lookupErr := findSyspathByName(sysVirtualInputDir, name)
+	if lookupErr != nil {
+		return "", fmt.Errorf("failed to fetch syspath: %w", err)
+	}
+	return sysPath, nil
+}
+
+func findSyspathByName(sysInputDir string, name []byte) (string, error) {
+	entries, err := ioutil.ReadDir(sysInputDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input device directory: %w", err)
+	}
+	sysPath := ""
+	latest := -1
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "input") {
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimPrefix(entry.Name(), "input"))
+		if err != nil || number <= latest {
+			continue
+		}
+		content, err := ioutil.ReadFile(filepath.Join(sysInputDir, entry.Name(), "name"))
+		if err != nil || strings.TrimRight(string(content), "\n") != string(name) {
+			continue
+		}
+		sysPath = filepath.Join(sysInputDir, entry.Name())
+		latest = number
+	}
+	if sysPath == "" {
+		return "", fmt.Errorf("no input device named %s found", name)
+	}
+	return sysPath, nil
+}
+
+// lookupEventNode returns the path to the evdev node of the device, using lookupSyspath in order to find the device.
+func lookupEventNode(deviceFile *os.File, name []byte) (string, error) {
+	sysPath, err := lookupSyspath(deviceFile, name)
+	if err != nil {
+		return "", err
+	}
+	return findEventNode(sysPath)
+}
+
+// fetchEventNode returns the path to the evdev node (/dev/input/eventX) the kernel assigned to the device.
+func fetchEventNode(deviceFile *os.File) (string, error) {
+	sysPath, err := fetchSyspath(deviceFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch syspath: %w", err)
+	}
+	return findEventNode(sysPath)
+}
+
+func findEventNode(sysPath string) (string, error) {
+	entries, err := ioutil.ReadDir(sysPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read device directory: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "event") {
+			return "/dev/input/" + entry.Name(), nil
+		}
+	}
+	return "", fmt.Errorf("no event node found for device %s", sysPath)
+}
+
+// openEventNode opens the evdev node of the device for reading the events of the device back, without keeping other
+// consumers from receiving them.
+func openEventNode(deviceFile *os.File) (*os.File, error) {
+	node, err := fetchEventNode(deviceFile)
+	if err != nil {
+		return nil, err
+	}
+	eventFile, err := os.OpenFile(node, syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event node: %w", err)
+	}
+	return eventFile, nil
+}
+
+// grabEventNode opens the evdev node of the device and grabs it, so that the events of the device are exclusively
+// delivered to the returned file.
+func grabEventNode(deviceFile *os.File) (*os.File, error) {
+	eventFile, err := openEventNode(deviceFile)
+	if err != nil {
+		return nil, err
+	}
+	err = ioctl(eventFile, evIOCGrab, uintptr(1))
+	if err != nil {
+		_ = eventFile.Close()
+		return nil, fmt.Errorf("failed to grab event node: %w", err)
+	}
+	return eventFile, nil
+}
+
+func ungrabEventNode(eventFile *os.File) error {
+	err := ioctl(eventFile, evIOCGrab, uintptr(0))
+	if err != nil {
+		_ = eventFile.Close()
+		return fmt.Errorf("failed to release grab of event node: %w", err)
+	}
+	return eventFile.Close()
+}
+
 // Note that mice and touch pads do have buttons as well. Therefore, this function is used
 // by all currently available devices and resides in the main source file.
-func sendBtnEvent(deviceFile *os.File, keys []int, btnState int) (err error) {
+func sendBtnEvent(deviceFile *device, keys []int, btnState int) (err error) {
+	err = writeBtnEvents(deviceFile, keys, btnState)
+	if err != nil {
+		return err
+	}
+	return syncEvents(deviceFile)
+}
+
+// writeBtnEvents writes the button events without terminating them with a sync event.
+func writeBtnEvents(deviceFile *device, keys []int, btnState int) error {
 	for _, key := range keys {
-		buf, err := inputEventToBuffer(inputEvent{
-			Time:  syscall.Timeval{Sec: 0, Usec: 0},
-			Type:  evKey,
-			Code:  uint16(key),
-			Value: int32(btnState)})
-		if err != nil {
-			return fmt.Errorf("key event could not be set: %v", err)
-		}
-		_, err = deviceFile.Write(buf)
+		err := writeEvent(deviceFile, inputEvent{Type: evKey, Code: uint16(key), Value: int32(btnState)})
 		if err != nil {
-			return fmt.Errorf("writing btnEvent structure to the device file failed: %v", err)
+			return fmt.Errorf("writing btnEvent structure to the device file failed: %w", err)
 		}
 	}
-	return syncEvents(deviceFile)
+	return nil
 }
 
-func syncEvents(deviceFile *os.File) (err error) {
-	buf, err := inputEventToBuffer(inputEvent{
-		Time:  syscall.Timeval{Sec: 0, Usec: 0},
-		Type:  evSyn,
-		Code:  uint16(synReport),
-		Value: 0})
+// A deviceMutex serializes the calls made to a device, so that the events of concurrent calls do not interleave and the
+// state tracked for the device stays consistent. Locking is skipped if disabled using WithConcurrencySafe.
+type deviceMutex struct {
+	mu       sync.Mutex
+	disabled bool
+}
+
+func newDeviceMutex(options deviceOptions) deviceMutex {
+	return deviceMutex{disabled: !options.concurrent}
+}
+
+func (m *deviceMutex) Lock() {
+	if !m.disabled {
+		m.mu.Lock()
+	}
+}
+
+func (m *deviceMutex) Unlock() {
+	if !m.disabled {
+		m.mu.Unlock()
+	}
+}
+
+// sendRawEvent writes a single event of the given type and code to the device file, without terminating it.
+func sendRawEvent(deviceFile *device, evType uint16, code uint16, value int32) error {
+	err := writeEvent(deviceFile, inputEvent{Type: evType, Code: code, Value: value})
 	if err != nil {
-		return fmt.Errorf("writing sync event failed: %v", err)
+		return fmt.Errorf("failed to write event to device file: %w", err)
 	}
-	_, err = deviceFile.Write(buf)
-	return err
+	return nil
+}
+
+func syncEvents(deviceFile *device) (err error) {
+	return writeSyncEvent(deviceFile, synReport)
 }
 
+func writeSyncEvent(deviceFile *device, code uint16) error {
+	return writeEvent(deviceFile, inputEvent{Type: evSyn, Code: code})
+}
+
+// inputEventToBuffer encodes the event into a new buffer. Frequently sent events should rather be appended to a reused
+// buffer using appendInputEvent.
 func inputEventToBuffer(iev inputEvent) (buffer []byte, err error) {
-	buf := bytes.NewBuffer(make([]byte, 0, 24))
-	err = binary.Write(buf, binary.LittleEndian, iev)
+	return appendInputEvent(make([]byte, 0, EventSize), iev), nil
+}
+
+func bufferToInputEvent(buffer []byte) (iev inputEvent, err error) {
+	iev, err = decodeInputEvent(buffer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to write input event to buffer: %v", err)
+		return inputEvent{}, fmt.Errorf("failed to read input event from buffer: %w", err)
 	}
-	return buf.Bytes(), nil
+	return iev, nil
 }
 
-// original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
-func ioctl(deviceFile *os.File, cmd, ptr uintptr) error {
-	_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, deviceFile.Fd(), cmd, ptr)
-	if errorCode != 0 {
-		return errorCode
+// readEvent waits for at most the given timeout for an event that is sent back to the device by the kernel
+// (LED changes, for example). If no event arrives in time, ok will be false.
+func readEvent(deviceFile *os.File, timeout time.Duration) (iev inputEvent, ok bool, err error) {
+	fd := deviceFile.Fd()
+	readable, err := pollReadable(fd, timeout)
+	if err != nil || !readable {
+		return inputEvent{}, false, err
 	}
-	return nil
+
+	buf := make([]byte, EventSize)
+	n, err := readFd(fd, buf)
+	if err == syscall.EAGAIN {
+		return inputEvent{}, false, nil
+	}
+	if err != nil {
+		return inputEvent{}, false, fmt.Errorf("failed to read event from device file: %w", err)
+	}
+	if n != len(buf) {
+		return inputEvent{}, false, fmt.Errorf("short read from device file: got %d of %d bytes", n, len(buf))
+	}
+
+	iev, err = bufferToInputEvent(buf)
+	if err != nil {
+		return inputEvent{}, false, err
+	}
+	return iev, true, nil
 }
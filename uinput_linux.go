@@ -0,0 +1,103 @@
+//go:build linux
+// +build linux
+
+package uinput
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+func checkPlatform() error {
+	return nil
+}
+
+// setCloseOnExec sets or clears the FD_CLOEXEC flag of the given file.
+func setCloseOnExec(deviceFile *os.File, enabled bool) error {
+	flags := 0
+	if enabled {
+		flags = syscall.FD_CLOEXEC
+	}
+	_, _, errorCode := syscall.Syscall(syscall.SYS_FCNTL, deviceFile.Fd(), syscall.F_SETFD, uintptr(flags))
+	if errorCode != 0 {
+		return fmt.Errorf("failed to set close-on-exec flag: %v", errorCode)
+	}
+	return nil
+}
+
+func readFd(fd uintptr, buf []byte) (int, error) {
+	return syscall.Read(int(fd), buf)
+}
+
+func writeFd(fd uintptr, buf []byte) (int, error) {
+	return syscall.Write(int(fd), buf)
+}
+
+// writevFd writes all buffers using a single writev call. Empty buffers are skipped.
+func writevFd(fd uintptr, bufs [][]byte) (int, error) {
+	iovecs := make([]syscall.Iovec, 0, len(bufs))
+	for _, buf := range bufs {
+		if len(buf) == 0 {
+			continue
+		}
+		iov := syscall.Iovec{Base: &buf[0]}
+		iov.SetLen(len(buf))
+		iovecs = append(iovecs, iov)
+	}
+	if len(iovecs) == 0 {
+		return 0, nil
+	}
+	n, _, errorCode := syscall.Syscall(syscall.SYS_WRITEV, fd, uintptr(unsafe.Pointer(&iovecs[0])), uintptr(len(iovecs)))
+	if errorCode != 0 {
+		return 0, errorCode
+	}
+	return int(n), nil
+}
+
+// clockMonotonic is the id of CLOCK_MONOTONIC, which is the clock of the timestamps of input events.
+const clockMonotonic = 1
+
+func monotonicTime() (time.Duration, error) {
+	var ts syscall.Timespec
+	_, _, errorCode := syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockMonotonic, uintptr(unsafe.Pointer(&ts)), 0)
+	if errorCode != 0 {
+		return 0, fmt.Errorf("failed to read monotonic clock: %v", errorCode)
+	}
+	return time.Duration(ts.Nano()), nil
+}
+
+// pollReadable blocks until the file descriptor is readable or the timeout expires.
+func pollReadable(fd uintptr, timeout time.Duration) (bool, error) {
+	if timeout < 0 {
+		timeout = 0
+	}
+	pfd := struct {
+		fd      int32
+		events  int16
+		revents int16
+	}{fd: int32(fd), events: pollIn}
+	ts := syscall.NsecToTimespec(timeout.Nanoseconds())
+
+	for {
+		n, _, errorCode := syscall.Syscall6(syscall.SYS_PPOLL, uintptr(unsafe.Pointer(&pfd)), 1, uintptr(unsafe.Pointer(&ts)), 0, 0, 0)
+		if errorCode == syscall.EINTR {
+			continue
+		}
+		if errorCode != 0 {
+			return false, fmt.Errorf("failed to poll device file: %v", errorCode)
+		}
+		return n > 0 && pfd.revents&pollIn != 0, nil
+	}
+}
+
+// original function taken from: https://github.com/tianon/debian-golang-pty/blob/master/ioctl.go
+func ioctl(deviceFile *os.File, cmd, ptr uintptr) error {
+	_, _, errorCode := syscall.Syscall(syscall.SYS_IOCTL, deviceFile.Fd(), cmd, ptr)
+	if errorCode != 0 {
+		return errorCode
+	}
+	return nil
+}
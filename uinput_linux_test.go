@@ -0,0 +1,56 @@
+//go:build linux
+// +build linux
+
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestDeviceFileIsClosedOnExecByDefault(t *testing.T) {
+	path := createTestFilePath(t)
+	defer os.Remove(path)
+
+	file, err := openDeviceFile(path, newDeviceOptions(nil))
+	if err != nil {
+		t.Fatalf("Failed to open device file: %v", err)
+	}
+	defer file.Close()
+	if !isCloseOnExec(t, file) {
+		t.Fatalf("Expected close-on-exec flag to be set, but it is not")
+	}
+}
+
+func TestCloseOnExecCanBeDisabled(t *testing.T) {
+	path := createTestFilePath(t)
+	defer os.Remove(path)
+
+	file, err := openDeviceFile(path, newDeviceOptions([]DeviceOption{WithCloseOnExec(false)}))
+	if err != nil {
+		t.Fatalf("Failed to open device file: %v", err)
+	}
+	defer file.Close()
+	if isCloseOnExec(t, file) {
+		t.Fatalf("Expected close-on-exec flag to be cleared, but it is set")
+	}
+}
+
+func createTestFilePath(t *testing.T) string {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-cloexec-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	_ = file.Close()
+	return file.Name()
+}
+
+func isCloseOnExec(t *testing.T, file *os.File) bool {
+	flags, _, errorCode := syscall.Syscall(syscall.SYS_FCNTL, file.Fd(), syscall.F_GETFD, 0)
+	if errorCode != 0 {
+		t.Fatalf("Failed to read file descriptor flags: %v", errorCode)
+	}
+	return flags&syscall.FD_CLOEXEC != 0
+}
@@ -0,0 +1,46 @@
+//go:build !linux
+// +build !linux
+
+package uinput
+
+import (
+	"os"
+	"time"
+)
+
+// The functions below replace the linux specific system calls on all other platforms. Since checkPlatform fails, no
+// uinput device file is ever opened, so the helpers operating on a device file are never reached. Devices created by a
+// Fake do not use a device file and work on all platforms. Helpers that do not need a device file (monotonicTime)
+// return ErrUnsupportedPlatform.
+
+func checkPlatform() error {
+	return ErrUnsupportedPlatform
+}
+
+func setCloseOnExec(deviceFile *os.File, enabled bool) error {
+	return ErrUnsupportedPlatform
+}
+
+func readFd(fd uintptr, buf []byte) (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+func writeFd(fd uintptr, buf []byte) (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+func writevFd(fd uintptr, bufs [][]byte) (int, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+func monotonicTime() (time.Duration, error) {
+	return 0, ErrUnsupportedPlatform
+}
+
+func pollReadable(fd uintptr, timeout time.Duration) (bool, error) {
+	return false, ErrUnsupportedPlatform
+}
+
+func ioctl(deviceFile *os.File, cmd, ptr uintptr) error {
+	return ErrUnsupportedPlatform
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package uinput
+
+import "testing"
+
+func TestDeviceCreationFailsOnUnsupportedPlatform(t *testing.T) {
+	_, err := CreateKeyboard("/dev/uinput", []byte("Test Keyboard"))
+	if err != ErrUnsupportedPlatform {
+		t.Fatalf("Expected error %v, but got %v", ErrUnsupportedPlatform, err)
+	}
+	_, err = CreateMouse("/dev/uinput", []byte("Test Mouse"))
+	if err != ErrUnsupportedPlatform {
+		t.Fatalf("Expected error %v, but got %v", ErrUnsupportedPlatform, err)
+	}
+	_, err = CreateTouchPad("/dev/uinput", []byte("Test TouchPad"), 0, 1024, 0, 768)
+	if err != ErrUnsupportedPlatform {
+		t.Fatalf("Expected error %v, but got %v", ErrUnsupportedPlatform, err)
+	}
+}
@@ -1,9 +1,19 @@
 package uinput
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
+	"unicode/utf8"
 )
 
 func TestValidateDevicePathEmptyPathPanics(t *testing.T) {
@@ -40,7 +50,7 @@ func TestFailedDeviceFileCreationGeneratesError(t *testing.T) {
 
 func TestNonExistentDeviceFileCausesError(t *testing.T) {
 	expected := "failed to write uidev struct to device file:"
-	_, err := createUsbDevice(nil, uinputUserDev{})
+	_, err := createUsbDevice(nil, uinputUserDev{}, newDeviceOptions(nil))
 	if err == nil {
 		t.Fatalf("expected error, but got none")
 	}
@@ -48,3 +58,545 @@ func TestNonExistentDeviceFileCausesError(t *testing.T) {
 		t.Fatalf("got '%v', but expected '%v'", err.Error(), expected)
 	}
 }
+
+func TestReadEventDecodesWrittenEvent(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	buf, err := inputEventToBuffer(inputEvent{Type: evLed, Code: LedCapsl, Value: 1})
+	if err != nil {
+		t.Fatalf("Failed to encode event: %v", err)
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.Fatalf("Failed to write event: %v", err)
+	}
+
+	ev, ok, err := readEvent(r, time.Second)
+	if err != nil || !ok {
+		t.Fatalf("Expected to read an event, but got ok=%v and error %v", ok, err)
+	}
+	if ev.Type != evLed || ev.Code != LedCapsl || ev.Value != 1 {
+		t.Fatalf("Decoded unexpected event: %+v", ev)
+	}
+}
+
+func TestReadEventTimesOut(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	_, ok, err := readEvent(r, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %v", err)
+	}
+	if ok {
+		t.Fatalf("Expected read to time out, but an event was returned")
+	}
+}
+
+func TestSyncEventsEmitsSynReport(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	err := syncEvents(&device{File: file})
+	if err != nil {
+		t.Fatalf("Failed to sync events: %v", err)
+	}
+
+	events := readTestEvents(t, file)
+	if len(events) != 1 || events[0].Type != evSyn || events[0].Code != synReport {
+		t.Fatalf("Expected a single SYN_REPORT, but got %+v", events)
+	}
+}
+
+// createTestEventFile creates a temporary file that can be used in place of a device file, in order to inspect the
+// events that were written.
+func createTestEventFile(t *testing.T) *os.File {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-events-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	_ = os.Remove(file.Name())
+	return file
+}
+
+// openBenchmarkFile opens the null device, which takes the place of the device file in benchmarks, so that only the
+// cost of encoding and writing the events is measured.
+func openBenchmarkFile(b *testing.B) *os.File {
+	file, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatalf("Failed to setup benchmark. Unable to open %s: %v", os.DevNull, err)
+	}
+	return file
+}
+
+// reportEventRate reports the number of events per second sent since the given start of the benchmark.
+func reportEventRate(b *testing.B, start time.Time, eventsPerOp int) {
+	b.ReportMetric(float64(b.N*eventsPerOp)/time.Since(start).Seconds(), "events/s")
+}
+
+// readTestEvents decodes all events written to a file created by createTestEventFile.
+func readTestEvents(t *testing.T, file *os.File) []inputEvent {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		t.Fatalf("Failed to rewind event file: %v", err)
+	}
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+
+	size := binary.Size(inputEvent{})
+	if len(data)%size != 0 {
+		t.Fatalf("Expected a multiple of %d bytes, but got %d", size, len(data))
+	}
+	var events []inputEvent
+	for i := 0; i < len(data); i += size {
+		ev, err := bufferToInputEvent(data[i : i+size])
+		if err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestOpenDeviceCountTracksDevices(t *testing.T) {
+	before := OpenDeviceCount()
+
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Count Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	if OpenDeviceCount() != before+1 {
+		t.Fatalf("Expected %d open devices, but got %d", before+1, OpenDeviceCount())
+	}
+
+	err = vk.Close()
+	if err != nil {
+		t.Fatalf("Failed to close device. Last error was: %s\n", err)
+	}
+	if OpenDeviceCount() != before {
+		t.Fatalf("Expected %d open devices, but got %d", before, OpenDeviceCount())
+	}
+
+	// closing a device twice must not affect the count
+	_ = vk.Close()
+	if OpenDeviceCount() != before {
+		t.Fatalf("Expected %d open devices, but got %d", before, OpenDeviceCount())
+	}
+}
+
+func TestFailedCloseDoesNotAffectOpenDeviceCount(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	before := OpenDeviceCount()
+	err := closeDevice(&device{File: file})
+	if err == nil {
+		t.Fatalf("Expected closing a regular file to fail, but got no error")
+	}
+	if OpenDeviceCount() != before {
+		t.Fatalf("Expected %d open devices, but got %d", before, OpenDeviceCount())
+	}
+}
+
+func TestInputDeviceExists(t *testing.T) {
+	devices := `I: Bus=0011 Vendor=0001 Product=0001 Version=ab41
+N: Name="AT Translated Set 2 keyboard"
+P: Phys=isa0060/serio0/input0
+H: Handlers=sysrq kbd event0 leds
+
+I: Bus=0003 Vendor=046d Product=c52b Version=0111
+N: Name="Logitech USB Receiver"
+H: Handlers=mouse0 event1
+`
+	for name, expected := range map[string]bool{
+		"AT Translated Set 2 keyboard": true,
+		"Logitech USB Receiver":        true,
+		"Logitech":                     false,
+		"Virtual Keyboard":             false,
+	} {
+		exists, err := inputDeviceExists(strings.NewReader(devices), name)
+		if err != nil {
+			t.Fatalf("Failed to parse device list: %v", err)
+		}
+		if exists != expected {
+			t.Fatalf("Expected %v for device %q, but got %v", expected, name, exists)
+		}
+	}
+}
+
+func TestNameCollisionCheckFailsOnExistingName(t *testing.T) {
+	vk, err := CreateKeyboard("/dev/uinput", []byte("Test Collision Keyboard"))
+	if err != nil {
+		t.Fatalf("Failed to create the virtual keyboard. Last error was: %s\n", err)
+	}
+	defer vk.Close()
+
+	_, err = CreateKeyboard("/dev/uinput", []byte("Test Collision Keyboard"), WithNameCollisionCheck(true))
+	if err != ErrNameCollision {
+		t.Fatalf("Expected: %v\nActual: %v", ErrNameCollision, err)
+	}
+}
+
+func TestNameCollisionCheckAppliesToAllDevices(t *testing.T) {
+	devices, err := ioutil.TempFile("", "uinput-devices-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create device list: %v", err)
+	}
+	defer os.Remove(devices.Name())
+	_, err = devices.WriteString("I: Bus=0003 Vendor=046d Product=c52b Version=0111\nN: Name=\"Test Device\"\n\n")
+	_ = devices.Close()
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to write device list: %v", err)
+	}
+	defer func(path string) { inputDevicesPath = path }(inputDevicesPath)
+	inputDevicesPath = devices.Name()
+
+	// the device list doubles as device path, which makes creation fail unless the name is checked beforehand
+	path, name, check := devices.Name(), []byte("Test Device"), WithNameCollisionCheck(true)
+	for device, create := range map[string]func() error{
+		"mouse": func() error { _, err := CreateMouse(path, name, check); return err },
+		"touchpad": func() error {
+			_, err := CreateTouchPad(path, name, 0, 1024, 0, 768, check)
+			return err
+		},
+		"touchscreen": func() error {
+			_, err := CreateTouchScreen(path, name, 0, 1024, 0, 768, 2, check)
+			return err
+		},
+		"clickpad": func() error {
+			_, err := CreateClickPad(path, name, 0, 1024, 0, 768, 2, check)
+			return err
+		},
+		"pen": func() error {
+			_, err := CreatePen(path, name, 0, 1024, 0, 768, 4096, check)
+			return err
+		},
+		"gamepad": func() error { _, err := CreateGamepad(path, name, 0xDEAD, 0xBEEF, check); return err },
+		"dial":    func() error { _, err := CreateDial(path, name, check); return err },
+	} {
+		err = create()
+		if err != ErrNameCollision {
+			t.Fatalf("Expected %s creation to fail with %v, but got %v", device, ErrNameCollision, err)
+		}
+	}
+}
+
+func TestFromFileConstructorsCloseFileOnFailure(t *testing.T) {
+	for device, create := range map[string]func(file *os.File) error{
+		"keyboard": func(file *os.File) error { _, err := CreateKeyboardFromFile(file, nil); return err },
+		"remote":   func(file *os.File) error { _, err := CreateRemoteFromFile(file, nil); return err },
+		"mouse":    func(file *os.File) error { _, err := CreateMouseFromFile(file, nil); return err },
+		"touchpad": func(file *os.File) error {
+			_, err := CreateTouchPadFromFile(file, nil, 0, 1024, 0, 768)
+			return err
+		},
+		"touchscreen": func(file *os.File) error {
+			_, err := CreateTouchScreenFromFile(file, nil, 0, 1024, 0, 768, 2)
+			return err
+		},
+		"clickpad": func(file *os.File) error {
+			_, err := CreateClickPadFromFile(file, nil, 0, 1024, 0, 768, 2)
+			return err
+		},
+		"pen": func(file *os.File) error {
+			_, err := CreatePenFromFile(file, nil, 0, 1024, 0, 768, 4096)
+			return err
+		},
+		"gamepad": func(file *os.File) error {
+			_, err := CreateGamepadFromFile(file, nil, 0xDEAD, 0xBEEF)
+			return err
+		},
+		"dial": func(file *os.File) error { _, err := CreateDialFromFile(file, nil); return err },
+		"switch": func(file *os.File) error {
+			_, err := CreateSwitchDeviceFromFile(file, nil, []int{SwLid})
+			return err
+		},
+		"touchring": func(file *os.File) error { _, err := CreateTouchRingFromFile(file, nil, 0, 71); return err },
+		"custom": func(file *os.File) error {
+			_, err := CreateFromCapabilitiesFile(file, nil, Capabilities{EV: []int{evKey}, Key: []int{KeyA}})
+			return err
+		},
+	} {
+		file := createTestEventFile(t)
+		err := create(file)
+		if err == nil {
+			t.Fatalf("Expected %s creation to fail due to an empty name, but got no error.", device)
+		}
+		err = file.Close()
+		if !errors.Is(err, os.ErrClosed) {
+			t.Fatalf("Expected the device file to be closed after the %s creation failed, but got %v", device, err)
+		}
+	}
+}
+
+func TestFindEventNode(t *testing.T) {
+	sysPath, err := ioutil.TempDir(os.TempDir(), "uinput-sysfs-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(sysPath)
+
+	for _, dir := range []string{"capabilities", "event7", "power"} {
+		err = os.Mkdir(sysPath+"/"+dir, 0755)
+		if err != nil {
+			t.Fatalf("Failed to setup test. Unable to create dir: %v", err)
+		}
+	}
+
+	node, err := findEventNode(sysPath)
+	if err != nil {
+		t.Fatalf("Failed to find event node: %v", err)
+	}
+	if node != "/dev/input/event7" {
+		t.Fatalf("Expected /dev/input/event7, but got %s", node)
+	}
+}
+
+func TestFindEventNodeFailsWithoutEventNode(t *testing.T) {
+	sysPath, err := ioutil.TempDir(os.TempDir(), "uinput-sysfs-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(sysPath)
+
+	_, err = findEventNode(sysPath)
+	if err == nil {
+		t.Fatalf("Expected an error, but got none")
+	}
+}
+
+func TestCloseHooksRunInReverseOrder(t *testing.T) {
+	var hooks closeHooks
+	var order []int
+	hooks.add(func() { order = append(order, 1) })
+	hooks.add(func() { panic("failing hook") })
+	hooks.add(func() { order = append(order, 3) })
+
+	hooks.run()
+
+	if len(order) != 2 || order[0] != 3 || order[1] != 1 {
+		t.Fatalf("Expected hooks to run in order [3 1], but got %v", order)
+	}
+
+	hooks.run()
+	if len(order) != 2 {
+		t.Fatalf("Expected hooks to run only once, but got %v", order)
+	}
+}
+
+func TestSetupStructsMatchIoctlSizes(t *testing.T) {
+	// the size of the argument is encoded in bits 16 to 29 of the request
+	if size := binary.Size(uinputSetup{}); size != uiDevSetup>>16&0x3fff {
+		t.Fatalf("Expected uinput_setup to be %d bytes, but got %d", uiDevSetup>>16&0x3fff, size)
+	}
+	if size := binary.Size(uinputAbsSetup{}); size != uiAbsSetup>>16&0x3fff {
+		t.Fatalf("Expected uinput_abs_setup to be %d bytes, but got %d", uiAbsSetup>>16&0x3fff, size)
+	}
+}
+
+func TestFindSyspathByNamePrefersLatestDevice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uinput-sysfs-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for device, name := range map[string]string{"input3": "gopher", "input12": "gopher", "input13": "other", "mouse0": "gopher"} {
+		err = os.Mkdir(filepath.Join(dir, device), 0755)
+		if err != nil {
+			t.Fatalf("Failed to setup test. Unable to create device dir: %v", err)
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, device, "name"), []byte(name+"\n"), 0644)
+		if err != nil {
+			t.Fatalf("Failed to setup test. Unable to write device name: %v", err)
+		}
+	}
+
+	sysPath, err := findSyspathByName(dir, []byte("gopher"))
+	if err != nil {
+		t.Fatalf("Failed to find syspath: %v", err)
+	}
+	if sysPath != filepath.Join(dir, "input12") {
+		t.Fatalf("Expected syspath %s, but got %s", filepath.Join(dir, "input12"), sysPath)
+	}
+
+	_, err = findSyspathByName(dir, []byte("missing"))
+	if err == nil {
+		t.Fatalf("Expected lookup of a missing device to fail")
+	}
+}
+
+func TestWaitUntilReadyTimesOut(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	start := time.Now()
+	err := waitUntilReady(file, []byte("no such device"), 50*time.Millisecond)
+	if err == nil {
+		t.Fatalf("Expected waiting for a missing device to fail")
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("Expected to wait for the timeout, but returned after %v", elapsed)
+	}
+}
+
+func TestMissingDevicePathMatchesErrUinputMissing(t *testing.T) {
+	err := validateDevicePath("/dev/uinput-does-not-exist")
+	if !errors.Is(err, ErrUinputMissing) || !os.IsNotExist(err) {
+		t.Fatalf("Expected an error matching ErrUinputMissing, but got %v", err)
+	}
+	_, err = createDeviceFile("/dev/uinput-does-not-exist")
+	if !errors.Is(err, ErrUinputMissing) || err.Error() != "could not open device file" {
+		t.Fatalf("Expected an error matching ErrUinputMissing, but got %v", err)
+	}
+}
+
+func TestSentinelErrorKeepsMessageAndCause(t *testing.T) {
+	cause := syscall.EACCES
+	err := fmt.Errorf("failed to create device: %w", &sentinelError{msg: "key code 1000 is not in range", sentinel: ErrKeyOutOfRange, cause: cause})
+	if err.Error() != "failed to create device: key code 1000 is not in range" {
+		t.Fatalf("Unexpected error message: %v", err)
+	}
+	if !errors.Is(err, ErrKeyOutOfRange) || !errors.Is(err, syscall.EACCES) || !errors.Is(err, ErrPermission) {
+		t.Fatalf("Expected error to match ErrKeyOutOfRange and EACCES, but got %v", err)
+	}
+	if errors.Is(err, ErrUinputMissing) {
+		t.Fatalf("Expected error not to match ErrUinputMissing")
+	}
+}
+
+func TestPrepareUinputNameRefusesLongNamesByDefault(t *testing.T) {
+	name := bytes.Repeat([]byte("a"), uinputMaxNameSize+1)
+	_, err := prepareUinputName(name, newDeviceOptions(nil))
+	if err == nil {
+		t.Fatalf("Expected overlong name to be refused")
+	}
+}
+
+func TestPrepareUinputNameTruncatesLongNames(t *testing.T) {
+	name := bytes.Repeat([]byte("a"), uinputMaxNameSize+10)
+	truncated, err := prepareUinputName(name, newDeviceOptions([]DeviceOption{WithNameTruncation(true)}))
+	if err != nil {
+		t.Fatalf("Expected truncated name to be accepted, but got %v", err)
+	}
+	if len(truncated) != uinputMaxNameSize {
+		t.Fatalf("Expected name to be truncated to %d bytes, but got %d", uinputMaxNameSize, len(truncated))
+	}
+}
+
+func TestPrepareUinputNameStillRefusesEmptyNames(t *testing.T) {
+	_, err := prepareUinputName(nil, newDeviceOptions([]DeviceOption{WithNameTruncation(true)}))
+	if err == nil {
+		t.Fatalf("Expected empty name to be refused")
+	}
+}
+
+func TestTruncateUinputNameKeepsCharactersIntact(t *testing.T) {
+	// "ä" is encoded using two bytes, so the 80th byte is the first half of a character
+	name := append(bytes.Repeat([]byte("a"), uinputMaxNameSize-1), []byte("äbc")...)
+	truncated := truncateUinputName(name)
+	if len(truncated) != uinputMaxNameSize-1 || !utf8.Valid(truncated) {
+		t.Fatalf("Expected name to be truncated to %d bytes of valid UTF-8, but got %q", uinputMaxNameSize-1, truncated)
+	}
+
+	short := []byte("short name")
+	if !bytes.Equal(truncateUinputName(short), short) {
+		t.Fatalf("Expected short name to remain unchanged")
+	}
+}
+
+func TestStringNamesAreTruncatedUnlessDisabled(t *testing.T) {
+	if !newDeviceOptions(withStringName(nil)).truncateName {
+		t.Fatalf("Expected name truncation to be enabled for string names")
+	}
+	if newDeviceOptions(withStringName([]DeviceOption{WithNameTruncation(false)})).truncateName {
+		t.Fatalf("Expected name truncation to be disabled by the given option")
+	}
+}
+
+func TestPropertiesAreRegisteredBeforeSetup(t *testing.T) {
+	file := createTestEventFile(t)
+	options := newDeviceOptions([]DeviceOption{WithProperties(PropPointer)})
+
+	expected := "failed to register property 0:"
+	_, err := createUsbDevice(file, uinputUserDev{}, options)
+	if err == nil || !strings.HasPrefix(err.Error(), expected) {
+		t.Fatalf("Expected error to start with '%s', but got %v", expected, err)
+	}
+}
+
+func TestAxisOptionsOverrideAxisSettings(t *testing.T) {
+	options := newDeviceOptions([]DeviceOption{
+		WithAxis(AxisX, AbsRange{Fuzz: 8, Flat: 0, Resolution: 12}),
+		WithAxis(AxisPressure, AbsRange{Min: 0, Max: 255, Fuzz: 1}),
+	})
+	dev := uinputUserDev{}
+	dev.Absmin[absX] = -100
+	dev.Absmax[absX] = 100
+	var absRes [absSize]int32
+
+	err := applyAxisOptions(&dev, &absRes, options)
+	if err != nil {
+		t.Fatalf("Failed to apply axis options: %v", err)
+	}
+	if dev.Absmin[absX] != -100 || dev.Absmax[absX] != 100 {
+		t.Fatalf("Expected the range of the x-axis to be kept, but got %d to %d", dev.Absmin[absX], dev.Absmax[absX])
+	}
+	if dev.Absfuzz[absX] != 8 || absRes[absX] != 12 {
+		t.Fatalf("Expected fuzz 8 and resolution 12 for the x-axis, but got %d and %d", dev.Absfuzz[absX], absRes[absX])
+	}
+	if dev.Absmax[absPressure] != 255 || dev.Absfuzz[absPressure] != 1 {
+		t.Fatalf("Expected the pressure axis to be replaced, but got maximum %d and fuzz %d", dev.Absmax[absPressure], dev.Absfuzz[absPressure])
+	}
+}
+
+func TestAxisOptionsFailOnInvalidAxis(t *testing.T) {
+	var absRes [absSize]int32
+	err := applyAxisOptions(&uinputUserDev{}, &absRes, newDeviceOptions([]DeviceOption{WithAxis(absSize, AbsRange{})}))
+	if err == nil {
+		t.Fatalf("Expected an axis out of range to fail, but got no error.")
+	}
+	err = applyAxisOptions(&uinputUserDev{}, &absRes, newDeviceOptions([]DeviceOption{WithAxis(AxisY, AbsRange{Min: 10, Max: 0})}))
+	if err == nil {
+		t.Fatalf("Expected an invalid range to fail, but got no error.")
+	}
+}
+
+func TestSetPhysFailsOnNonUinputFile(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+
+	expected := "failed to set physical path: inappropriate ioctl for device"
+	err := setPhys(file, "uinput/test0")
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func BenchmarkSendRawEvent(b *testing.B) {
+	file := openBenchmarkFile(b)
+	defer file.Close()
+
+	fd := &device{File: file}
+	b.ReportAllocs()
+	b.ResetTimer()
+	start := time.Now()
+	for i := 0; i < b.N; i++ {
+		err := sendRawEvent(fd, evAbs, absX, int32(i))
+		if err != nil {
+			b.Fatalf("Failed to send event: %v", err)
+		}
+	}
+	reportEventRate(b, start, 1)
+}
@@ -1,6 +1,9 @@
 package uinput
 
-import "syscall"
+import (
+	"syscall"
+	"unsafe"
+)
 
 // types needed from uinput.h
 const (
@@ -8,15 +11,47 @@ const (
 	uiDevCreate       = 0x5501
 	uiDevDestroy      = 0x5502
 	uiDevSetup        = 0x405c5503
+	uiAbsSetup        = 0x401c5504
 	// this is for 64 length buffer to store name
 	// for another length generate using : (len << 16) | 0x8000552C
 	uiGetSysname = 0x8041552c
+	uiGetVersion = 0x8004552d
 	uiSetEvBit   = 0x40045564
 	uiSetKeyBit  = 0x40045565
 
-	uiSetRelBit = 0x40045566
-	uiSetAbsBit = 0x40045567
-	busUsb      = 0x03
+	uiSetRelBit  = 0x40045566
+	uiSetAbsBit  = 0x40045567
+	uiSetMscBit  = 0x40045568
+	uiSetLedBit  = 0x40045569
+	uiSetSndBit  = 0x4004556a
+	uiSetSwBit   = 0x4004556d
+	uiSetPropBit = 0x4004556e
+	uiSetFFBit   = 0x4004556b
+	busUsb       = 0x03
+	busI8042     = 0x11
+	busBluetooth = 0x05
+	busVirtual   = 0x06
+)
+
+// the size of the argument of UI_SET_PHYS is the size of a pointer, since the kernel takes a pointer to the string
+const uiSetPhys = 0x4000556c | unsafe.Sizeof(uintptr(0))<<16
+
+// force feedback requests as defined in uinput.h. The size of the upload request depends on the size of a pointer.
+const (
+	evUinput   = 0x0101
+	uiFFUpload = 1
+	uiFFErase  = 2
+
+	uiBeginFFUpload = 0xc00055c8 | unsafe.Sizeof(uinputFFUpload{})<<16
+	uiEndFFUpload   = 0x400055c9 | unsafe.Sizeof(uinputFFUpload{})<<16
+	uiBeginFFErase  = 0xc00c55ca
+	uiEndFFErase    = 0x400c55cb
+)
+
+// types needed from input.h
+const (
+	evIOCGrab  = 0x40044590
+	evIOCGName = 0x80004506 // EVIOCGNAME(0), the size of the buffer is added in bits 16 to 29
 )
 
 // input event codes as specified in input-event-codes.h
@@ -25,28 +60,74 @@ const (
 	evKey     = 0x01
 	evRel     = 0x02
 	evAbs     = 0x03
+	evMsc     = 0x04
+	evSw      = 0x05
+	evLed     = 0x11
+	evSnd     = 0x12
+	evRep     = 0x14
+	evFf      = 0x15
 	relX      = 0x0
 	relY      = 0x1
 	relHWheel = 0x6
 	relWheel  = 0x8
 	relDial   = 0x7
 
+	relWheelHiRes  = 0xb
+	relHWheelHiRes = 0xc
+
 	absX     = 0x00
 	absY     = 0x01
 	absZ     = 0x02
 	absRX    = 0x03
 	absRY    = 0x04
 	absRZ    = 0x05
+	absWheel = 0x08
 	absHat0X = 0x10
 	absHat0Y = 0x11
 
+	absPressure = 0x18
+	absTiltX    = 0x1a
+	absTiltY    = 0x1b
+
+	absMTSlot       = 0x2f
+	absMTPositionX  = 0x35
+	absMTPositionY  = 0x36
+	absMTToolType   = 0x37
+	absMTTrackingID = 0x39
+	absMTPressure   = 0x3a
+
+	mtToolFinger = 0x00
+	mtToolPalm   = 0x02
+
+	mscScan = 0x04
+
+	repDelay  = 0x00
+	repPeriod = 0x01
+
 	synReport        = 0
 	evMouseBtnLeft   = 0x110
 	evMouseBtnRight  = 0x111
 	evMouseBtnMiddle = 0x112
 	evBtnTouch       = 0x14a
+	evBtn0           = 0x100
+	evBtnToolPen     = 0x140
+	evBtnToolRubber  = 0x141
+	evBtnStylus      = 0x14b
+
+	evBtnToolFinger    = 0x145
+	evBtnToolQuinttap  = 0x148
+	evBtnToolDoubletap = 0x14d
+	evBtnToolTripletap = 0x14e
+	evBtnToolQuadtap   = 0x14f
+
+	inputPropPointer   = 0x00
+	inputPropDirect    = 0x01
+	inputPropButtonpad = 0x02
 )
 
+// poll.h
+const pollIn = 0x1
+
 const (
 	btnStateReleased = 0
 	btnStatePressed  = 1
@@ -71,6 +152,30 @@ type uinputUserDev struct {
 	Absflat    [absSize]int32
 }
 
+// translated to go from uinput.h
+type uinputSetup struct {
+	ID           inputID
+	Name         [uinputMaxNameSize]byte
+	FFEffectsMax uint32
+}
+
+// translated to go from input.h
+type inputAbsinfo struct {
+	Value      int32
+	Minimum    int32
+	Maximum    int32
+	Fuzz       int32
+	Flat       int32
+	Resolution int32
+}
+
+// translated to go from uinput.h
+type uinputAbsSetup struct {
+	Code    uint16
+	_       uint16
+	Absinfo inputAbsinfo
+}
+
 // translated to go from input.h
 type inputEvent struct {
 	Time  syscall.Timeval
@@ -0,0 +1,48 @@
+package uinput
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Versions of the uinput interface (see UINPUT_VERSION in uinput.h), which tell the features the kernel supports.
+const (
+	// UinputVersionSysname is the version that added UI_GET_SYSNAME (kernel 3.15), which is required by FetchSyspath
+	// and EventNode.
+	UinputVersionSysname = 4
+	// UinputVersionDevSetup is the version that added UI_DEV_SETUP, UI_ABS_SETUP and UI_GET_VERSION (kernel 4.5).
+	// Devices are set up using UI_DEV_SETUP if the kernel supports it, which is required for reporting the resolution
+	// of absolute axes.
+	UinputVersionDevSetup = 5
+)
+
+// KernelInterfaceVersion will return the version of the uinput interface provided by the kernel (see UI_GET_VERSION).
+// Since UI_GET_VERSION has been added in version 5, kernels that do not support it are reported as version 4, although
+// they might provide even older versions.
+func KernelInterfaceVersion(path string) (int, error) {
+	err := validateDevicePath(path)
+	if err != nil {
+		return 0, err
+	}
+	deviceFile, err := createDeviceFile(path)
+	if err != nil {
+		return 0, err
+	}
+	defer deviceFile.Close()
+	return interfaceVersion(deviceFile)
+}
+
+func interfaceVersion(deviceFile *os.File) (int, error) {
+	var version uint32
+	err := ioctl(deviceFile, uiGetVersion, uintptr(unsafe.Pointer(&version)))
+	if errors.Is(err, syscall.EINVAL) {
+		return UinputVersionDevSetup - 1, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch uinput version: %w", err)
+	}
+	return int(version), nil
+}
@@ -0,0 +1,40 @@
+package uinput
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestKernelInterfaceVersion(t *testing.T) {
+	version, err := KernelInterfaceVersion("/dev/uinput")
+	if err != nil {
+		t.Fatalf("Failed to fetch the uinput version. Last error was: %s\n", err)
+	}
+	if version < UinputVersionSysname {
+		t.Fatalf("Expected a uinput version of at least %d, but got %d", UinputVersionSysname, version)
+	}
+}
+
+func TestKernelInterfaceVersionFailsOnEmptyPath(t *testing.T) {
+	expected := "device path must not be empty"
+	_, err := KernelInterfaceVersion("")
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
+
+func TestKernelInterfaceVersionFailsOnWrongPathName(t *testing.T) {
+	file, err := ioutil.TempFile(os.TempDir(), "uinput-version-test-")
+	if err != nil {
+		t.Fatalf("Failed to setup test. Unable to create tempfile: %v", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	expected := "failed to fetch uinput version: inappropriate ioctl for device"
+	_, err = KernelInterfaceVersion(file.Name())
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected: %s\nActual: %s", expected, err)
+	}
+}
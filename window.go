@@ -0,0 +1,51 @@
+package uinput
+
+import (
+	"context"
+	"fmt"
+)
+
+// A WindowFocuser brings the window with the given title to the foreground, so that it receives the keyboard input
+// (see TypeToWindow). Focusing windows depends on the display server, so this package does not implement it. Users
+// supply their own focuser, e.g. one that runs "wmctrl -a <title>" or "xdotool search --name <title> windowactivate",
+// or one that talks to the compositor.
+type WindowFocuser interface {
+	FocusWindow(title string) error
+}
+
+// noopWindowFocuser is the default WindowFocuser, which does not change the focus at all. Text is typed into whatever
+// window is focused already.
+type noopWindowFocuser struct{}
+
+func (noopWindowFocuser) FocusWindow(string) error {
+	return nil
+}
+
+// TypeToWindow will focus the window with the given title using the WindowFocuser configured upon creation (see
+// WithWindowFocuser) and type the given text just like Type does. All characters are translated before the window is
+// focused, so neither the focus changes nor anything is typed if the text contains a character that is not supported
+// by the layout. Without a focuser, the text is typed into the window that is focused already.
+func (vk *vKeyboard) TypeToWindow(windowTitle string, text string) error {
+	layout := vk.options.layout
+	if layout == nil {
+		return fmt.Errorf("failed to perform TypeToWindow. The keyboard layout must not be nil")
+	}
+	var shortcuts []shortcut
+	for _, r := range text {
+		s, err := vk.runeShortcuts("TypeToWindow", layout, r)
+		if err != nil {
+			return err
+		}
+		shortcuts = append(shortcuts, s...)
+	}
+
+	focuser := vk.options.windowFocuser
+	if focuser == nil {
+		focuser = noopWindowFocuser{}
+	}
+	err := focuser.FocusWindow(windowTitle)
+	if err != nil {
+		return fmt.Errorf("failed to focus window %q: %w", windowTitle, err)
+	}
+	return vk.typeShortcuts(context.Background(), shortcuts)
+}
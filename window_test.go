@@ -0,0 +1,79 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// recordingFocuser records the focused windows along with the number of bytes written to the device at that time.
+type recordingFocuser struct {
+	file    *os.File
+	titles  []string
+	written []int64
+	err     error
+}
+
+func (f *recordingFocuser) FocusWindow(title string) error {
+	info, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	f.titles = append(f.titles, title)
+	f.written = append(f.written, info.Size())
+	return f.err
+}
+
+func TestTypeToWindowFocusesWindowBeforeTyping(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	focuser := &recordingFocuser{file: file}
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithWindowFocuser(focuser)}), pressed: make(map[int]bool)}
+
+	err := vk.TypeToWindow("Editor", "hi")
+	if err != nil {
+		t.Fatalf("Failed to type to window: %v", err)
+	}
+	if len(focuser.titles) != 1 || focuser.titles[0] != "Editor" || focuser.written[0] != 0 {
+		t.Fatalf("Expected window \"Editor\" to be focused before typing, but got %v after %v bytes", focuser.titles, focuser.written)
+	}
+	if text := typedText(t, readTestEvents(t, file)); text != "hi" {
+		t.Fatalf("Expected \"hi\" to be typed, but got %q", text)
+	}
+}
+
+func TestTypeToWindowWithoutFocuserTypesText(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions(nil), pressed: make(map[int]bool)}
+
+	err := vk.TypeToWindow("Editor", "hi")
+	if err != nil {
+		t.Fatalf("Failed to type to window: %v", err)
+	}
+	if text := typedText(t, readTestEvents(t, file)); text != "hi" {
+		t.Fatalf("Expected \"hi\" to be typed, but got %q", text)
+	}
+}
+
+func TestTypeToWindowFailsWithoutTyping(t *testing.T) {
+	file := createTestEventFile(t)
+	defer file.Close()
+	focuser := &recordingFocuser{file: file}
+	vk := &vKeyboard{deviceFile: &device{File: file}, options: newDeviceOptions([]DeviceOption{WithWindowFocuser(focuser)}), pressed: make(map[int]bool)}
+
+	err := vk.TypeToWindow("Editor", "hi ☃")
+	if err == nil || len(focuser.titles) != 0 {
+		t.Fatalf("Expected an unsupported character to fail before focusing, but got %v and %v", err, focuser.titles)
+	}
+
+	focusErr := errors.New("no such window")
+	focuser.err = focusErr
+	err = vk.TypeToWindow("Editor", "hi")
+	if !errors.Is(err, focusErr) {
+		t.Fatalf("Expected the error of the focuser, but got %v", err)
+	}
+	if events := readTestEvents(t, file); len(events) != 0 {
+		t.Fatalf("Expected nothing to be typed, but got %+v", events)
+	}
+}
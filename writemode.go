@@ -0,0 +1,114 @@
+package uinput
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ErrWouldBlock is returned if an event could not be written to the device without blocking, which happens if the
+// kernel event queue of the device is full (e.g. because nobody reads the events). It is only returned for devices
+// created using WithNonBlockingWrites or WithWriteTimeout, since writes of other devices wait until they succeed.
+var ErrWouldBlock = errors.New("writing to the device would block")
+
+// writeMode holds how events are written to a device, as set using WithNonBlockingWrites and WithWriteTimeout.
+type writeMode struct {
+	nonBlocking bool
+	timeout     time.Duration
+}
+
+// writeDeviceFile writes the buffer to the device file according to the write mode of the device.
+func writeDeviceFile(deviceFile *device, buf []byte) (int, error) {
+	mode := deviceFile.mode
+	switch {
+	case deviceFile.fake != nil:
+		return deviceFile.fake.Write(buf)
+	case !mode.nonBlocking && mode.timeout <= 0:
+		return deviceFile.Write(buf)
+	case mode.nonBlocking:
+		return writeNonBlocking(deviceFile.File, buf)
+	default:
+		return writeWithTimeout(deviceFile.File, buf, mode.timeout)
+	}
+}
+
+// writeDeviceFileVectored writes the buffers to the device file using a single writev call, according to the write mode
+// of the device. Devices using a write timeout, fake devices, as well as files that do not provide access to their file
+// descriptor, are written using a single write of the joined buffers instead.
+func writeDeviceFileVectored(deviceFile *device, bufs [][]byte) (int, error) {
+	mode := deviceFile.mode
+	if deviceFile.fake != nil {
+		return writeDeviceFile(deviceFile, bytes.Join(bufs, nil))
+	}
+	conn, err := deviceFile.SyscallConn()
+	if mode.timeout > 0 || err != nil {
+		return writeDeviceFile(deviceFile, bytes.Join(bufs, nil))
+	}
+
+	total := 0
+	for _, buf := range bufs {
+		total += len(buf)
+	}
+	var n int
+	var writeErr error
+	err = conn.Write(func(fd uintptr) bool {
+		n, writeErr = writevFd(fd, bufs)
+		// unless writes are non-blocking, wait for the device to become writable and try again
+		return writeErr != syscall.EAGAIN || mode.nonBlocking
+	})
+	if err != nil {
+		return 0, err
+	}
+	if writeErr == syscall.EAGAIN {
+		return 0, &sentinelError{msg: "writing to the device would block", sentinel: ErrWouldBlock, cause: writeErr}
+	}
+	if writeErr != nil {
+		return 0, &os.PathError{Op: "writev", Path: deviceFile.Name(), Err: writeErr}
+	}
+	if n < total {
+		return n, io.ErrShortWrite
+	}
+	return n, nil
+}
+
+// writeNonBlocking writes the buffer using a single write call, bypassing the runtime poller, which would otherwise
+// wait for the device to become writable.
+func writeNonBlocking(deviceFile *os.File, buf []byte) (int, error) {
+	conn, err := deviceFile.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	var writeErr error
+	err = conn.Write(func(fd uintptr) bool {
+		n, writeErr = writeFd(fd, buf)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if writeErr == syscall.EAGAIN {
+		return 0, &sentinelError{msg: "writing to the device would block", sentinel: ErrWouldBlock, cause: writeErr}
+	}
+	if writeErr != nil {
+		return 0, &os.PathError{Op: "write", Path: deviceFile.Name(), Err: writeErr}
+	}
+	return n, nil
+}
+
+// writeWithTimeout writes the buffer, waiting for the device to become writable for at most the given timeout. Files
+// that do not support deadlines never block anyway and are written as usual.
+func writeWithTimeout(deviceFile *os.File, buf []byte, timeout time.Duration) (int, error) {
+	err := deviceFile.SetWriteDeadline(time.Now().Add(timeout))
+	if err != nil && err != os.ErrNoDeadline {
+		return 0, err
+	}
+	n, err := deviceFile.Write(buf)
+	if err != nil && os.IsTimeout(err) {
+		return n, &sentinelError{msg: "writing to the device timed out", sentinel: ErrWouldBlock, cause: err}
+	}
+	return n, err
+}
@@ -0,0 +1,56 @@
+package uinput
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// createTestPipe returns a pipe whose writer uses the write mode of the given options.
+func createTestPipe(t *testing.T, opts ...DeviceOption) (*os.File, *device) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	return r, newDevice(w, newDeviceOptions(opts))
+}
+
+func TestNonBlockingWritesFailWithErrWouldBlock(t *testing.T) {
+	r, w := createTestPipe(t, WithNonBlockingWrites(true))
+	defer r.Close()
+	defer w.Close()
+
+	buf := make([]byte, 1<<20)
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		_, err = writeDeviceFile(w, buf)
+	}
+	if !errors.Is(err, ErrWouldBlock) {
+		t.Fatalf("Expected writing to a full pipe to fail with ErrWouldBlock, but got %v", err)
+	}
+}
+
+func TestWriteTimeoutFailsWithErrWouldBlock(t *testing.T) {
+	r, w := createTestPipe(t, WithWriteTimeout(20*time.Millisecond))
+	defer r.Close()
+	defer w.Close()
+
+	start := time.Now()
+	_, err := writeDeviceFile(w, make([]byte, 1<<20))
+	if !errors.Is(err, ErrWouldBlock) {
+		t.Fatalf("Expected writing to a full pipe to fail with ErrWouldBlock, but got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("Expected the write to wait for the timeout, but it failed after %v", elapsed)
+	}
+}
+
+func TestBlockingWritesHaveNoWriteMode(t *testing.T) {
+	r, w := createTestPipe(t)
+	defer r.Close()
+	defer w.Close()
+	if w.mode != (writeMode{}) {
+		t.Fatalf("Expected no write mode for blocking writes")
+	}
+}